@@ -57,6 +57,12 @@ func (m *FileListRequest) validate(all bool) error {
 
 	var errors []error
 
+	// no validation rules for Path
+
+	// no validation rules for Recursive
+
+	// no validation rules for MaxDepth
+
 	if len(errors) > 0 {
 		return FileListRequestMultiError(errors)
 	}
@@ -135,6 +141,141 @@ var _ interface {
 	ErrorName() string
 } = FileListRequestValidationError{}
 
+// Validate checks the field values on FileEntry with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *FileEntry) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on FileEntry with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in FileEntryMultiError, or nil
+// if none found.
+func (m *FileEntry) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *FileEntry) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetName()) < 1 {
+		err := FileEntryValidationError{
+			field:  "Name",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetPath()) < 1 {
+		err := FileEntryValidationError{
+			field:  "Path",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if _, ok := EntryType_name[int32(m.GetType())]; !ok {
+		err := FileEntryValidationError{
+			field:  "Type",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Size
+
+	if len(errors) > 0 {
+		return FileEntryMultiError(errors)
+	}
+
+	return nil
+}
+
+// FileEntryMultiError is an error wrapping multiple validation errors
+// returned by FileEntry.ValidateAll() if the designated constraints aren't
+// met.
+type FileEntryMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m FileEntryMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m FileEntryMultiError) AllErrors() []error { return m }
+
+// FileEntryValidationError is the validation error returned by
+// FileEntry.Validate if the designated constraints aren't met.
+type FileEntryValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e FileEntryValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e FileEntryValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e FileEntryValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e FileEntryValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e FileEntryValidationError) ErrorName() string { return "FileEntryValidationError" }
+
+// Error satisfies the builtin error interface
+func (e FileEntryValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sFileEntry.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = FileEntryValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = FileEntryValidationError{}
+
 // Validate checks the field values on FileListResponse with the rules defined
 // in the proto definition for this message. If any rules are violated, the
 // first error encountered is returned, or nil if there are no violations.
@@ -173,6 +314,40 @@ func (m *FileListResponse) validate(all bool) error {
 
 	}
 
+	for idx, item := range m.GetEntries() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, FileListResponseValidationError{
+						field:  fmt.Sprintf("Entries[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, FileListResponseValidationError{
+						field:  fmt.Sprintf("Entries[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return FileListResponseValidationError{
+					field:  fmt.Sprintf("Entries[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	if len(errors) > 0 {
 		return FileListResponseMultiError(errors)
 	}
@@ -284,6 +459,8 @@ func (m *FileInfoRequest) validate(all bool) error {
 		errors = append(errors, err)
 	}
 
+	// no validation rules for Hash
+
 	if len(errors) > 0 {
 		return FileInfoRequestMultiError(errors)
 	}
@@ -397,6 +574,58 @@ func (m *FileInfoResponse) validate(all bool) error {
 
 	// no validation rules for Size
 
+	if all {
+		switch v := interface{}(m.GetModificationTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, FileInfoResponseValidationError{
+					field:  "ModificationTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, FileInfoResponseValidationError{
+					field:  "ModificationTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetModificationTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return FileInfoResponseValidationError{
+				field:  "ModificationTime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Mode
+
+	// no validation rules for Uid
+
+	// no validation rules for Gid
+
+	if _, ok := EntryType_name[int32(m.GetType())]; !ok {
+		err := FileInfoResponseValidationError{
+			field:  "Type",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for SymlinkTarget
+
+	// no validation rules for MimeType
+
+	// no validation rules for Sha256
+
 	if len(errors) > 0 {
 		return FileInfoResponseMultiError(errors)
 	}
@@ -477,7 +706,8 @@ var _ interface {
 
 // Validate checks the field values on FileContentResponse with the rules
 // defined in the proto definition for this message. If any rules are
-// violated, the first error encountered is returned, or nil if there are no violations.
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
 func (m *FileContentResponse) Validate() error {
 	return m.validate(false)
 }
@@ -589,3 +819,2527 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = FileContentResponseValidationError{}
+
+// Validate checks the field values on DownloadFileRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *DownloadFileRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DownloadFileRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DownloadFileRequestMultiError, or nil if none found.
+func (m *DownloadFileRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DownloadFileRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetFilename()) < 1 {
+		err := DownloadFileRequestValidationError{
+			field:  "Filename",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetOffset() < 0 {
+		err := DownloadFileRequestValidationError{
+			field:  "Offset",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetLength() < 0 {
+		err := DownloadFileRequestValidationError{
+			field:  "Length",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for HashFromStart
+
+	if len(errors) > 0 {
+		return DownloadFileRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// DownloadFileRequestMultiError is an error wrapping multiple validation
+// errors returned by DownloadFileRequest.ValidateAll() if the designated
+// constraints aren't met.
+type DownloadFileRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DownloadFileRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DownloadFileRequestMultiError) AllErrors() []error { return m }
+
+// DownloadFileRequestValidationError is the validation error returned by
+// DownloadFileRequest.Validate if the designated constraints aren't met.
+type DownloadFileRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DownloadFileRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DownloadFileRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DownloadFileRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DownloadFileRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DownloadFileRequestValidationError) ErrorName() string {
+	return "DownloadFileRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DownloadFileRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDownloadFileRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DownloadFileRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DownloadFileRequestValidationError{}
+
+// Validate checks the field values on FileChunk with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *FileChunk) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on FileChunk with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in FileChunkMultiError, or nil
+// if none found.
+func (m *FileChunk) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *FileChunk) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Content
+
+	// no validation rules for Sha256
+
+	if len(errors) > 0 {
+		return FileChunkMultiError(errors)
+	}
+
+	return nil
+}
+
+// FileChunkMultiError is an error wrapping multiple validation errors
+// returned by FileChunk.ValidateAll() if the designated constraints aren't
+// met.
+type FileChunkMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m FileChunkMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m FileChunkMultiError) AllErrors() []error { return m }
+
+// FileChunkValidationError is the validation error returned by
+// FileChunk.Validate if the designated constraints aren't met.
+type FileChunkValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e FileChunkValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e FileChunkValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e FileChunkValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e FileChunkValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e FileChunkValidationError) ErrorName() string { return "FileChunkValidationError" }
+
+// Error satisfies the builtin error interface
+func (e FileChunkValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sFileChunk.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = FileChunkValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = FileChunkValidationError{}
+
+// Validate checks the field values on UploadFileMetadata with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *UploadFileMetadata) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UploadFileMetadata with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UploadFileMetadataMultiError, or nil if none found.
+func (m *UploadFileMetadata) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UploadFileMetadata) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetFilename()) < 1 {
+		err := UploadFileMetadataValidationError{
+			field:  "Filename",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return UploadFileMetadataMultiError(errors)
+	}
+
+	return nil
+}
+
+// UploadFileMetadataMultiError is an error wrapping multiple validation
+// errors returned by UploadFileMetadata.ValidateAll() if the designated
+// constraints aren't met.
+type UploadFileMetadataMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UploadFileMetadataMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UploadFileMetadataMultiError) AllErrors() []error { return m }
+
+// UploadFileMetadataValidationError is the validation error returned by
+// UploadFileMetadata.Validate if the designated constraints aren't met.
+type UploadFileMetadataValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UploadFileMetadataValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UploadFileMetadataValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UploadFileMetadataValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UploadFileMetadataValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UploadFileMetadataValidationError) ErrorName() string {
+	return "UploadFileMetadataValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UploadFileMetadataValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUploadFileMetadata.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UploadFileMetadataValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UploadFileMetadataValidationError{}
+
+// Validate checks the field values on UploadFileRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *UploadFileRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UploadFileRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UploadFileRequestMultiError, or nil if none found.
+func (m *UploadFileRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UploadFileRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	oneofPayloadPresent := false
+	switch v := m.Payload.(type) {
+	case *UploadFileRequest_Metadata:
+		if v == nil {
+			err := UploadFileRequestValidationError{
+				field:  "Payload",
+				reason: "oneof value cannot be a typed-nil",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+		oneofPayloadPresent = true
+
+		if all {
+			switch v := interface{}(m.GetMetadata()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, UploadFileRequestValidationError{
+						field:  "Metadata",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, UploadFileRequestValidationError{
+						field:  "Metadata",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetMetadata()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return UploadFileRequestValidationError{
+					field:  "Metadata",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	case *UploadFileRequest_Chunk:
+		if v == nil {
+			err := UploadFileRequestValidationError{
+				field:  "Payload",
+				reason: "oneof value cannot be a typed-nil",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+		oneofPayloadPresent = true
+		// no validation rules for Chunk
+	default:
+		_ = v // ensures v is used
+	}
+	if !oneofPayloadPresent {
+		err := UploadFileRequestValidationError{
+			field:  "Payload",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return UploadFileRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// UploadFileRequestMultiError is an error wrapping multiple validation errors
+// returned by UploadFileRequest.ValidateAll() if the designated constraints
+// aren't met.
+type UploadFileRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UploadFileRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UploadFileRequestMultiError) AllErrors() []error { return m }
+
+// UploadFileRequestValidationError is the validation error returned by
+// UploadFileRequest.Validate if the designated constraints aren't met.
+type UploadFileRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UploadFileRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UploadFileRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UploadFileRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UploadFileRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UploadFileRequestValidationError) ErrorName() string {
+	return "UploadFileRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UploadFileRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUploadFileRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UploadFileRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UploadFileRequestValidationError{}
+
+// Validate checks the field values on UploadFileResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *UploadFileResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UploadFileResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UploadFileResponseMultiError, or nil if none found.
+func (m *UploadFileResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UploadFileResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetFilename()) < 1 {
+		err := UploadFileResponseValidationError{
+			field:  "Filename",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Size
+
+	if len(errors) > 0 {
+		return UploadFileResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// UploadFileResponseMultiError is an error wrapping multiple validation
+// errors returned by UploadFileResponse.ValidateAll() if the designated
+// constraints aren't met.
+type UploadFileResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UploadFileResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UploadFileResponseMultiError) AllErrors() []error { return m }
+
+// UploadFileResponseValidationError is the validation error returned by
+// UploadFileResponse.Validate if the designated constraints aren't met.
+type UploadFileResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UploadFileResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UploadFileResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UploadFileResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UploadFileResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UploadFileResponseValidationError) ErrorName() string {
+	return "UploadFileResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UploadFileResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUploadFileResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UploadFileResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UploadFileResponseValidationError{}
+
+// Validate checks the field values on DeleteFileRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *DeleteFileRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteFileRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteFileRequestMultiError, or nil if none found.
+func (m *DeleteFileRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteFileRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetFilename()) < 1 {
+		err := DeleteFileRequestValidationError{
+			field:  "Filename",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Recursive
+
+	if len(errors) > 0 {
+		return DeleteFileRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeleteFileRequestMultiError is an error wrapping multiple validation errors
+// returned by DeleteFileRequest.ValidateAll() if the designated constraints
+// aren't met.
+type DeleteFileRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteFileRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteFileRequestMultiError) AllErrors() []error { return m }
+
+// DeleteFileRequestValidationError is the validation error returned by
+// DeleteFileRequest.Validate if the designated constraints aren't met.
+type DeleteFileRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteFileRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteFileRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteFileRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteFileRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteFileRequestValidationError) ErrorName() string {
+	return "DeleteFileRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteFileRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteFileRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteFileRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteFileRequestValidationError{}
+
+// Validate checks the field values on DeleteFileResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *DeleteFileResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteFileResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteFileResponseMultiError, or nil if none found.
+func (m *DeleteFileResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteFileResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return DeleteFileResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeleteFileResponseMultiError is an error wrapping multiple validation
+// errors returned by DeleteFileResponse.ValidateAll() if the designated
+// constraints aren't met.
+type DeleteFileResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteFileResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteFileResponseMultiError) AllErrors() []error { return m }
+
+// DeleteFileResponseValidationError is the validation error returned by
+// DeleteFileResponse.Validate if the designated constraints aren't met.
+type DeleteFileResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteFileResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteFileResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteFileResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteFileResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteFileResponseValidationError) ErrorName() string {
+	return "DeleteFileResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteFileResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteFileResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteFileResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteFileResponseValidationError{}
+
+// Validate checks the field values on RenameFileRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *RenameFileRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RenameFileRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RenameFileRequestMultiError, or nil if none found.
+func (m *RenameFileRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RenameFileRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetSource()) < 1 {
+		err := RenameFileRequestValidationError{
+			field:  "Source",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetDestination()) < 1 {
+		err := RenameFileRequestValidationError{
+			field:  "Destination",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Overwrite
+
+	if len(errors) > 0 {
+		return RenameFileRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// RenameFileRequestMultiError is an error wrapping multiple validation errors
+// returned by RenameFileRequest.ValidateAll() if the designated constraints
+// aren't met.
+type RenameFileRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RenameFileRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RenameFileRequestMultiError) AllErrors() []error { return m }
+
+// RenameFileRequestValidationError is the validation error returned by
+// RenameFileRequest.Validate if the designated constraints aren't met.
+type RenameFileRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RenameFileRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RenameFileRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RenameFileRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RenameFileRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RenameFileRequestValidationError) ErrorName() string {
+	return "RenameFileRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RenameFileRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRenameFileRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RenameFileRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RenameFileRequestValidationError{}
+
+// Validate checks the field values on RenameFileResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *RenameFileResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RenameFileResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RenameFileResponseMultiError, or nil if none found.
+func (m *RenameFileResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RenameFileResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return RenameFileResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// RenameFileResponseMultiError is an error wrapping multiple validation
+// errors returned by RenameFileResponse.ValidateAll() if the designated
+// constraints aren't met.
+type RenameFileResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RenameFileResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RenameFileResponseMultiError) AllErrors() []error { return m }
+
+// RenameFileResponseValidationError is the validation error returned by
+// RenameFileResponse.Validate if the designated constraints aren't met.
+type RenameFileResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RenameFileResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RenameFileResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RenameFileResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RenameFileResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RenameFileResponseValidationError) ErrorName() string {
+	return "RenameFileResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RenameFileResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRenameFileResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RenameFileResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RenameFileResponseValidationError{}
+
+// Validate checks the field values on CopyFileRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *CopyFileRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CopyFileRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// CopyFileRequestMultiError, or nil if none found.
+func (m *CopyFileRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CopyFileRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetSource()) < 1 {
+		err := CopyFileRequestValidationError{
+			field:  "Source",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetDestination()) < 1 {
+		err := CopyFileRequestValidationError{
+			field:  "Destination",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Overwrite
+
+	if len(errors) > 0 {
+		return CopyFileRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// CopyFileRequestMultiError is an error wrapping multiple validation errors
+// returned by CopyFileRequest.ValidateAll() if the designated constraints
+// aren't met.
+type CopyFileRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CopyFileRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CopyFileRequestMultiError) AllErrors() []error { return m }
+
+// CopyFileRequestValidationError is the validation error returned by
+// CopyFileRequest.Validate if the designated constraints aren't met.
+type CopyFileRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CopyFileRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CopyFileRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CopyFileRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CopyFileRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CopyFileRequestValidationError) ErrorName() string { return "CopyFileRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CopyFileRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCopyFileRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CopyFileRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CopyFileRequestValidationError{}
+
+// Validate checks the field values on CopyFileResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *CopyFileResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CopyFileResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// CopyFileResponseMultiError, or nil if none found.
+func (m *CopyFileResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CopyFileResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Size
+
+	if len(errors) > 0 {
+		return CopyFileResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// CopyFileResponseMultiError is an error wrapping multiple validation errors
+// returned by CopyFileResponse.ValidateAll() if the designated constraints
+// aren't met.
+type CopyFileResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CopyFileResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CopyFileResponseMultiError) AllErrors() []error { return m }
+
+// CopyFileResponseValidationError is the validation error returned by
+// CopyFileResponse.Validate if the designated constraints aren't met.
+type CopyFileResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CopyFileResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CopyFileResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CopyFileResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CopyFileResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CopyFileResponseValidationError) ErrorName() string { return "CopyFileResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CopyFileResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCopyFileResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CopyFileResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CopyFileResponseValidationError{}
+
+// Validate checks the field values on MakeDirectoryRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *MakeDirectoryRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on MakeDirectoryRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// MakeDirectoryRequestMultiError, or nil if none found.
+func (m *MakeDirectoryRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *MakeDirectoryRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetPath()) < 1 {
+		err := MakeDirectoryRequestValidationError{
+			field:  "Path",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Parents
+
+	if len(errors) > 0 {
+		return MakeDirectoryRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// MakeDirectoryRequestMultiError is an error wrapping multiple validation
+// errors returned by MakeDirectoryRequest.ValidateAll() if the designated
+// constraints aren't met.
+type MakeDirectoryRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m MakeDirectoryRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m MakeDirectoryRequestMultiError) AllErrors() []error { return m }
+
+// MakeDirectoryRequestValidationError is the validation error returned by
+// MakeDirectoryRequest.Validate if the designated constraints aren't met.
+type MakeDirectoryRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e MakeDirectoryRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e MakeDirectoryRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e MakeDirectoryRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e MakeDirectoryRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e MakeDirectoryRequestValidationError) ErrorName() string {
+	return "MakeDirectoryRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e MakeDirectoryRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMakeDirectoryRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MakeDirectoryRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MakeDirectoryRequestValidationError{}
+
+// Validate checks the field values on MakeDirectoryResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *MakeDirectoryResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on MakeDirectoryResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// MakeDirectoryResponseMultiError, or nil if none found.
+func (m *MakeDirectoryResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *MakeDirectoryResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return MakeDirectoryResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// MakeDirectoryResponseMultiError is an error wrapping multiple validation
+// errors returned by MakeDirectoryResponse.ValidateAll() if the designated
+// constraints aren't met.
+type MakeDirectoryResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m MakeDirectoryResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m MakeDirectoryResponseMultiError) AllErrors() []error { return m }
+
+// MakeDirectoryResponseValidationError is the validation error returned by
+// MakeDirectoryResponse.Validate if the designated constraints aren't met.
+type MakeDirectoryResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e MakeDirectoryResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e MakeDirectoryResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e MakeDirectoryResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e MakeDirectoryResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e MakeDirectoryResponseValidationError) ErrorName() string {
+	return "MakeDirectoryResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e MakeDirectoryResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMakeDirectoryResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MakeDirectoryResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MakeDirectoryResponseValidationError{}
+
+// Validate checks the field values on DeltaDownloadMetadata with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *DeltaDownloadMetadata) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeltaDownloadMetadata with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeltaDownloadMetadataMultiError, or nil if none found.
+func (m *DeltaDownloadMetadata) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeltaDownloadMetadata) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetFilename()) < 1 {
+		err := DeltaDownloadMetadataValidationError{
+			field:  "Filename",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if val := m.GetBlockSize(); val < 512 || val > 8388608 {
+		err := DeltaDownloadMetadataValidationError{
+			field:  "BlockSize",
+			reason: "value must be inside range [512, 8388608]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Size
+
+	if len(errors) > 0 {
+		return DeltaDownloadMetadataMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeltaDownloadMetadataMultiError is an error wrapping multiple validation
+// errors returned by DeltaDownloadMetadata.ValidateAll() if the designated
+// constraints aren't met.
+type DeltaDownloadMetadataMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeltaDownloadMetadataMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeltaDownloadMetadataMultiError) AllErrors() []error { return m }
+
+// DeltaDownloadMetadataValidationError is the validation error returned by
+// DeltaDownloadMetadata.Validate if the designated constraints aren't met.
+type DeltaDownloadMetadataValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeltaDownloadMetadataValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeltaDownloadMetadataValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeltaDownloadMetadataValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeltaDownloadMetadataValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeltaDownloadMetadataValidationError) ErrorName() string {
+	return "DeltaDownloadMetadataValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeltaDownloadMetadataValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeltaDownloadMetadata.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeltaDownloadMetadataValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeltaDownloadMetadataValidationError{}
+
+// Validate checks the field values on BlockSignature with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *BlockSignature) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on BlockSignature with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// BlockSignatureMultiError, or nil if none found.
+func (m *BlockSignature) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *BlockSignature) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Weak
+
+	if len(m.GetStrong()) != 16 {
+		err := BlockSignatureValidationError{
+			field:  "Strong",
+			reason: "value length must be 16 bytes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return BlockSignatureMultiError(errors)
+	}
+
+	return nil
+}
+
+// BlockSignatureMultiError is an error wrapping multiple validation errors
+// returned by BlockSignature.ValidateAll() if the designated constraints
+// aren't met.
+type BlockSignatureMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BlockSignatureMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m BlockSignatureMultiError) AllErrors() []error { return m }
+
+// BlockSignatureValidationError is the validation error returned by
+// BlockSignature.Validate if the designated constraints aren't met.
+type BlockSignatureValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BlockSignatureValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BlockSignatureValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BlockSignatureValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BlockSignatureValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BlockSignatureValidationError) ErrorName() string { return "BlockSignatureValidationError" }
+
+// Error satisfies the builtin error interface
+func (e BlockSignatureValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBlockSignature.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BlockSignatureValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BlockSignatureValidationError{}
+
+// Validate checks the field values on BlockSignatures with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *BlockSignatures) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on BlockSignatures with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// BlockSignaturesMultiError, or nil if none found.
+func (m *BlockSignatures) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *BlockSignatures) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetBlocks() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, BlockSignaturesValidationError{
+						field:  fmt.Sprintf("Blocks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, BlockSignaturesValidationError{
+						field:  fmt.Sprintf("Blocks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return BlockSignaturesValidationError{
+					field:  fmt.Sprintf("Blocks[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return BlockSignaturesMultiError(errors)
+	}
+
+	return nil
+}
+
+// BlockSignaturesMultiError is an error wrapping multiple validation errors
+// returned by BlockSignatures.ValidateAll() if the designated constraints
+// aren't met.
+type BlockSignaturesMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BlockSignaturesMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m BlockSignaturesMultiError) AllErrors() []error { return m }
+
+// BlockSignaturesValidationError is the validation error returned by
+// BlockSignatures.Validate if the designated constraints aren't met.
+type BlockSignaturesValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BlockSignaturesValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BlockSignaturesValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BlockSignaturesValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BlockSignaturesValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BlockSignaturesValidationError) ErrorName() string { return "BlockSignaturesValidationError" }
+
+// Error satisfies the builtin error interface
+func (e BlockSignaturesValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBlockSignatures.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BlockSignaturesValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BlockSignaturesValidationError{}
+
+// Validate checks the field values on DeltaDownloadRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *DeltaDownloadRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeltaDownloadRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeltaDownloadRequestMultiError, or nil if none found.
+func (m *DeltaDownloadRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeltaDownloadRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	oneofPayloadPresent := false
+	switch v := m.Payload.(type) {
+	case *DeltaDownloadRequest_Metadata:
+		if v == nil {
+			err := DeltaDownloadRequestValidationError{
+				field:  "Payload",
+				reason: "oneof value cannot be a typed-nil",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+		oneofPayloadPresent = true
+
+		if all {
+			switch v := interface{}(m.GetMetadata()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, DeltaDownloadRequestValidationError{
+						field:  "Metadata",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, DeltaDownloadRequestValidationError{
+						field:  "Metadata",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetMetadata()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return DeltaDownloadRequestValidationError{
+					field:  "Metadata",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	case *DeltaDownloadRequest_Signatures:
+		if v == nil {
+			err := DeltaDownloadRequestValidationError{
+				field:  "Payload",
+				reason: "oneof value cannot be a typed-nil",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+		oneofPayloadPresent = true
+
+		if all {
+			switch v := interface{}(m.GetSignatures()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, DeltaDownloadRequestValidationError{
+						field:  "Signatures",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, DeltaDownloadRequestValidationError{
+						field:  "Signatures",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetSignatures()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return DeltaDownloadRequestValidationError{
+					field:  "Signatures",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	default:
+		_ = v // ensures v is used
+	}
+	if !oneofPayloadPresent {
+		err := DeltaDownloadRequestValidationError{
+			field:  "Payload",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return DeltaDownloadRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeltaDownloadRequestMultiError is an error wrapping multiple validation
+// errors returned by DeltaDownloadRequest.ValidateAll() if the designated
+// constraints aren't met.
+type DeltaDownloadRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeltaDownloadRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeltaDownloadRequestMultiError) AllErrors() []error { return m }
+
+// DeltaDownloadRequestValidationError is the validation error returned by
+// DeltaDownloadRequest.Validate if the designated constraints aren't met.
+type DeltaDownloadRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeltaDownloadRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeltaDownloadRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeltaDownloadRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeltaDownloadRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeltaDownloadRequestValidationError) ErrorName() string {
+	return "DeltaDownloadRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeltaDownloadRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeltaDownloadRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeltaDownloadRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeltaDownloadRequestValidationError{}
+
+// Validate checks the field values on BlockRange with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *BlockRange) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on BlockRange with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in BlockRangeMultiError, or
+// nil if none found.
+func (m *BlockRange) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *BlockRange) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Index
+
+	if m.GetCount() < 1 {
+		err := BlockRangeValidationError{
+			field:  "Count",
+			reason: "value must be greater than or equal to 1",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return BlockRangeMultiError(errors)
+	}
+
+	return nil
+}
+
+// BlockRangeMultiError is an error wrapping multiple validation errors
+// returned by BlockRange.ValidateAll() if the designated constraints aren't
+// met.
+type BlockRangeMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BlockRangeMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m BlockRangeMultiError) AllErrors() []error { return m }
+
+// BlockRangeValidationError is the validation error returned by
+// BlockRange.Validate if the designated constraints aren't met.
+type BlockRangeValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BlockRangeValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BlockRangeValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BlockRangeValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BlockRangeValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BlockRangeValidationError) ErrorName() string { return "BlockRangeValidationError" }
+
+// Error satisfies the builtin error interface
+func (e BlockRangeValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBlockRange.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BlockRangeValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BlockRangeValidationError{}
+
+// Validate checks the field values on DeltaInstruction with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *DeltaInstruction) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeltaInstruction with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeltaInstructionMultiError, or nil if none found.
+func (m *DeltaInstruction) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeltaInstruction) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	switch v := m.Operation.(type) {
+	case *DeltaInstruction_Copy:
+		if v == nil {
+			err := DeltaInstructionValidationError{
+				field:  "Operation",
+				reason: "oneof value cannot be a typed-nil",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+		if all {
+			switch v := interface{}(m.GetCopy()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, DeltaInstructionValidationError{
+						field:  "Copy",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, DeltaInstructionValidationError{
+						field:  "Copy",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetCopy()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return DeltaInstructionValidationError{
+					field:  "Copy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	case *DeltaInstruction_Literal:
+		if v == nil {
+			err := DeltaInstructionValidationError{
+				field:  "Operation",
+				reason: "oneof value cannot be a typed-nil",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+		// no validation rules for Literal
+	default:
+		_ = v // ensures v is used
+	}
+
+	if len(errors) > 0 {
+		return DeltaInstructionMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeltaInstructionMultiError is an error wrapping multiple validation errors
+// returned by DeltaInstruction.ValidateAll() if the designated constraints
+// aren't met.
+type DeltaInstructionMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeltaInstructionMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeltaInstructionMultiError) AllErrors() []error { return m }
+
+// DeltaInstructionValidationError is the validation error returned by
+// DeltaInstruction.Validate if the designated constraints aren't met.
+type DeltaInstructionValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeltaInstructionValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeltaInstructionValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeltaInstructionValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeltaInstructionValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeltaInstructionValidationError) ErrorName() string { return "DeltaInstructionValidationError" }
+
+// Error satisfies the builtin error interface
+func (e DeltaInstructionValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeltaInstruction.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeltaInstructionValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeltaInstructionValidationError{}
+
+// Validate checks the field values on DeltaChunk with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *DeltaChunk) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeltaChunk with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in DeltaChunkMultiError, or
+// nil if none found.
+func (m *DeltaChunk) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeltaChunk) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetInstructions() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, DeltaChunkValidationError{
+						field:  fmt.Sprintf("Instructions[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, DeltaChunkValidationError{
+						field:  fmt.Sprintf("Instructions[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return DeltaChunkValidationError{
+					field:  fmt.Sprintf("Instructions[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for Sha256
+
+	if len(errors) > 0 {
+		return DeltaChunkMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeltaChunkMultiError is an error wrapping multiple validation errors
+// returned by DeltaChunk.ValidateAll() if the designated constraints aren't
+// met.
+type DeltaChunkMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeltaChunkMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeltaChunkMultiError) AllErrors() []error { return m }
+
+// DeltaChunkValidationError is the validation error returned by
+// DeltaChunk.Validate if the designated constraints aren't met.
+type DeltaChunkValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeltaChunkValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeltaChunkValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeltaChunkValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeltaChunkValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeltaChunkValidationError) ErrorName() string { return "DeltaChunkValidationError" }
+
+// Error satisfies the builtin error interface
+func (e DeltaChunkValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeltaChunk.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeltaChunkValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeltaChunkValidationError{}
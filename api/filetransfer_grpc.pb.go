@@ -22,6 +22,13 @@ const (
 	FileTransfer_GetFileList_FullMethodName    = "/api.FileTransfer/GetFileList"
 	FileTransfer_GetFileInfo_FullMethodName    = "/api.FileTransfer/GetFileInfo"
 	FileTransfer_GetFileContent_FullMethodName = "/api.FileTransfer/GetFileContent"
+	FileTransfer_DownloadFile_FullMethodName   = "/api.FileTransfer/DownloadFile"
+	FileTransfer_UploadFile_FullMethodName     = "/api.FileTransfer/UploadFile"
+	FileTransfer_DeleteFile_FullMethodName     = "/api.FileTransfer/DeleteFile"
+	FileTransfer_RenameFile_FullMethodName     = "/api.FileTransfer/RenameFile"
+	FileTransfer_CopyFile_FullMethodName       = "/api.FileTransfer/CopyFile"
+	FileTransfer_MakeDirectory_FullMethodName  = "/api.FileTransfer/MakeDirectory"
+	FileTransfer_DeltaDownload_FullMethodName  = "/api.FileTransfer/DeltaDownload"
 )
 
 // FileTransferClient is the client API for FileTransfer service.
@@ -30,7 +37,23 @@ const (
 type FileTransferClient interface {
 	GetFileList(ctx context.Context, in *FileListRequest, opts ...grpc.CallOption) (*FileListResponse, error)
 	GetFileInfo(ctx context.Context, in *FileInfoRequest, opts ...grpc.CallOption) (*FileInfoResponse, error)
+	// Deprecated: GetFileContent loads the whole file into a single message, use DownloadFile instead.
 	GetFileContent(ctx context.Context, in *FileInfoRequest, opts ...grpc.CallOption) (*FileContentResponse, error)
+	// DownloadFile streams the content in chunks followed by a final message carrying its checksum.
+	DownloadFile(ctx context.Context, in *DownloadFileRequest, opts ...grpc.CallOption) (FileTransfer_DownloadFileClient, error)
+	// UploadFile expects the first message to carry the file metadata and every following message to carry a data chunk.
+	UploadFile(ctx context.Context, opts ...grpc.CallOption) (FileTransfer_UploadFileClient, error)
+	DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*DeleteFileResponse, error)
+	// RenameFile renames or moves a file or directory.
+	RenameFile(ctx context.Context, in *RenameFileRequest, opts ...grpc.CallOption) (*RenameFileResponse, error)
+	// CopyFile copies a file within the storage without transferring its content to the client.
+	CopyFile(ctx context.Context, in *CopyFileRequest, opts ...grpc.CallOption) (*CopyFileResponse, error)
+	MakeDirectory(ctx context.Context, in *MakeDirectoryRequest, opts ...grpc.CallOption) (*MakeDirectoryResponse, error)
+	// DeltaDownload transfers only the parts of a file that differ from a local copy of the client.
+	// The client sends the metadata, then the signatures of the blocks of its copy and closes its side of the stream.
+	// The server answers with instructions rebuilding the file from blocks of the copy and literal data,
+	// followed by a final message carrying the checksum of the file.
+	DeltaDownload(ctx context.Context, opts ...grpc.CallOption) (FileTransfer_DeltaDownloadClient, error)
 }
 
 type fileTransferClient struct {
@@ -68,13 +91,162 @@ func (c *fileTransferClient) GetFileContent(ctx context.Context, in *FileInfoReq
 	return out, nil
 }
 
+func (c *fileTransferClient) DownloadFile(ctx context.Context, in *DownloadFileRequest, opts ...grpc.CallOption) (FileTransfer_DownloadFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileTransfer_ServiceDesc.Streams[0], FileTransfer_DownloadFile_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileTransferDownloadFileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FileTransfer_DownloadFileClient interface {
+	Recv() (*FileChunk, error)
+	grpc.ClientStream
+}
+
+type fileTransferDownloadFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileTransferDownloadFileClient) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileTransferClient) UploadFile(ctx context.Context, opts ...grpc.CallOption) (FileTransfer_UploadFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileTransfer_ServiceDesc.Streams[1], FileTransfer_UploadFile_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileTransferUploadFileClient{stream}
+	return x, nil
+}
+
+type FileTransfer_UploadFileClient interface {
+	Send(*UploadFileRequest) error
+	CloseAndRecv() (*UploadFileResponse, error)
+	grpc.ClientStream
+}
+
+type fileTransferUploadFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileTransferUploadFileClient) Send(m *UploadFileRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fileTransferUploadFileClient) CloseAndRecv() (*UploadFileResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadFileResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileTransferClient) DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*DeleteFileResponse, error) {
+	out := new(DeleteFileResponse)
+	err := c.cc.Invoke(ctx, FileTransfer_DeleteFile_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) RenameFile(ctx context.Context, in *RenameFileRequest, opts ...grpc.CallOption) (*RenameFileResponse, error) {
+	out := new(RenameFileResponse)
+	err := c.cc.Invoke(ctx, FileTransfer_RenameFile_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) CopyFile(ctx context.Context, in *CopyFileRequest, opts ...grpc.CallOption) (*CopyFileResponse, error) {
+	out := new(CopyFileResponse)
+	err := c.cc.Invoke(ctx, FileTransfer_CopyFile_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) MakeDirectory(ctx context.Context, in *MakeDirectoryRequest, opts ...grpc.CallOption) (*MakeDirectoryResponse, error) {
+	out := new(MakeDirectoryResponse)
+	err := c.cc.Invoke(ctx, FileTransfer_MakeDirectory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) DeltaDownload(ctx context.Context, opts ...grpc.CallOption) (FileTransfer_DeltaDownloadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileTransfer_ServiceDesc.Streams[2], FileTransfer_DeltaDownload_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileTransferDeltaDownloadClient{stream}
+	return x, nil
+}
+
+type FileTransfer_DeltaDownloadClient interface {
+	Send(*DeltaDownloadRequest) error
+	Recv() (*DeltaChunk, error)
+	grpc.ClientStream
+}
+
+type fileTransferDeltaDownloadClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileTransferDeltaDownloadClient) Send(m *DeltaDownloadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fileTransferDeltaDownloadClient) Recv() (*DeltaChunk, error) {
+	m := new(DeltaChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // FileTransferServer is the server API for FileTransfer service.
 // All implementations must embed UnimplementedFileTransferServer
 // for forward compatibility
 type FileTransferServer interface {
 	GetFileList(context.Context, *FileListRequest) (*FileListResponse, error)
 	GetFileInfo(context.Context, *FileInfoRequest) (*FileInfoResponse, error)
+	// Deprecated: GetFileContent loads the whole file into a single message, use DownloadFile instead.
 	GetFileContent(context.Context, *FileInfoRequest) (*FileContentResponse, error)
+	// DownloadFile streams the content in chunks followed by a final message carrying its checksum.
+	DownloadFile(*DownloadFileRequest, FileTransfer_DownloadFileServer) error
+	// UploadFile expects the first message to carry the file metadata and every following message to carry a data chunk.
+	UploadFile(FileTransfer_UploadFileServer) error
+	DeleteFile(context.Context, *DeleteFileRequest) (*DeleteFileResponse, error)
+	// RenameFile renames or moves a file or directory.
+	RenameFile(context.Context, *RenameFileRequest) (*RenameFileResponse, error)
+	// CopyFile copies a file within the storage without transferring its content to the client.
+	CopyFile(context.Context, *CopyFileRequest) (*CopyFileResponse, error)
+	MakeDirectory(context.Context, *MakeDirectoryRequest) (*MakeDirectoryResponse, error)
+	// DeltaDownload transfers only the parts of a file that differ from a local copy of the client.
+	// The client sends the metadata, then the signatures of the blocks of its copy and closes its side of the stream.
+	// The server answers with instructions rebuilding the file from blocks of the copy and literal data,
+	// followed by a final message carrying the checksum of the file.
+	DeltaDownload(FileTransfer_DeltaDownloadServer) error
 	mustEmbedUnimplementedFileTransferServer()
 }
 
@@ -91,6 +263,27 @@ func (UnimplementedFileTransferServer) GetFileInfo(context.Context, *FileInfoReq
 func (UnimplementedFileTransferServer) GetFileContent(context.Context, *FileInfoRequest) (*FileContentResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetFileContent not implemented")
 }
+func (UnimplementedFileTransferServer) DownloadFile(*DownloadFileRequest, FileTransfer_DownloadFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadFile not implemented")
+}
+func (UnimplementedFileTransferServer) UploadFile(FileTransfer_UploadFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
+}
+func (UnimplementedFileTransferServer) DeleteFile(context.Context, *DeleteFileRequest) (*DeleteFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
+}
+func (UnimplementedFileTransferServer) RenameFile(context.Context, *RenameFileRequest) (*RenameFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameFile not implemented")
+}
+func (UnimplementedFileTransferServer) CopyFile(context.Context, *CopyFileRequest) (*CopyFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CopyFile not implemented")
+}
+func (UnimplementedFileTransferServer) MakeDirectory(context.Context, *MakeDirectoryRequest) (*MakeDirectoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MakeDirectory not implemented")
+}
+func (UnimplementedFileTransferServer) DeltaDownload(FileTransfer_DeltaDownloadServer) error {
+	return status.Errorf(codes.Unimplemented, "method DeltaDownload not implemented")
+}
 func (UnimplementedFileTransferServer) mustEmbedUnimplementedFileTransferServer() {}
 
 // UnsafeFileTransferServer may be embedded to opt out of forward compatibility for this service.
@@ -158,6 +351,151 @@ func _FileTransfer_GetFileContent_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileTransfer_DownloadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileTransferServer).DownloadFile(m, &fileTransferDownloadFileServer{stream})
+}
+
+type FileTransfer_DownloadFileServer interface {
+	Send(*FileChunk) error
+	grpc.ServerStream
+}
+
+type fileTransferDownloadFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileTransferDownloadFileServer) Send(m *FileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FileTransfer_UploadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileTransferServer).UploadFile(&fileTransferUploadFileServer{stream})
+}
+
+type FileTransfer_UploadFileServer interface {
+	SendAndClose(*UploadFileResponse) error
+	Recv() (*UploadFileRequest, error)
+	grpc.ServerStream
+}
+
+type fileTransferUploadFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileTransferUploadFileServer) SendAndClose(m *UploadFileResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fileTransferUploadFileServer) Recv() (*UploadFileRequest, error) {
+	m := new(UploadFileRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FileTransfer_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).DeleteFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_DeleteFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).DeleteFile(ctx, req.(*DeleteFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_RenameFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).RenameFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_RenameFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).RenameFile(ctx, req.(*RenameFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_CopyFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).CopyFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_CopyFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).CopyFile(ctx, req.(*CopyFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_MakeDirectory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MakeDirectoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).MakeDirectory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_MakeDirectory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).MakeDirectory(ctx, req.(*MakeDirectoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_DeltaDownload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileTransferServer).DeltaDownload(&fileTransferDeltaDownloadServer{stream})
+}
+
+type FileTransfer_DeltaDownloadServer interface {
+	Send(*DeltaChunk) error
+	Recv() (*DeltaDownloadRequest, error)
+	grpc.ServerStream
+}
+
+type fileTransferDeltaDownloadServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileTransferDeltaDownloadServer) Send(m *DeltaChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fileTransferDeltaDownloadServer) Recv() (*DeltaDownloadRequest, error) {
+	m := new(DeltaDownloadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // FileTransfer_ServiceDesc is the grpc.ServiceDesc for FileTransfer service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -177,7 +515,40 @@ var FileTransfer_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetFileContent",
 			Handler:    _FileTransfer_GetFileContent_Handler,
 		},
+		{
+			MethodName: "DeleteFile",
+			Handler:    _FileTransfer_DeleteFile_Handler,
+		},
+		{
+			MethodName: "RenameFile",
+			Handler:    _FileTransfer_RenameFile_Handler,
+		},
+		{
+			MethodName: "CopyFile",
+			Handler:    _FileTransfer_CopyFile_Handler,
+		},
+		{
+			MethodName: "MakeDirectory",
+			Handler:    _FileTransfer_MakeDirectory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DownloadFile",
+			Handler:       _FileTransfer_DownloadFile_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadFile",
+			Handler:       _FileTransfer_UploadFile_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DeltaDownload",
+			Handler:       _FileTransfer_DeltaDownload_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "filetransfer.proto",
 }
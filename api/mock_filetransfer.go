@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: filetransfer/api (interfaces: FileTransferClient)
+// Source: filetransfer/api (interfaces: FileTransferClient,FileTransfer_DownloadFileClient,FileTransfer_DownloadFileServer,FileTransfer_UploadFileClient,FileTransfer_UploadFileServer,FileTransfer_DeltaDownloadClient,FileTransfer_DeltaDownloadServer)
 //
 // Generated by this command:
 //
-//	mockgen.exe . FileTransferClient
+//	mockgen.exe . FileTransferClient,FileTransfer_DownloadFileClient,FileTransfer_DownloadFileServer,FileTransfer_UploadFileClient,FileTransfer_UploadFileServer,FileTransfer_DeltaDownloadClient,FileTransfer_DeltaDownloadServer
 //
 // Package mock_api is a generated GoMock package.
 package api
@@ -14,6 +14,7 @@ import (
 
 	gomock "go.uber.org/mock/gomock"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 )
 
 // MockFileTransferClient is a mock of FileTransferClient interface.
@@ -39,6 +40,86 @@ func (m *MockFileTransferClient) EXPECT() *MockFileTransferClientMockRecorder {
 	return m.recorder
 }
 
+// CopyFile mocks base method.
+func (m *MockFileTransferClient) CopyFile(arg0 context.Context, arg1 *CopyFileRequest, arg2 ...grpc.CallOption) (*CopyFileResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CopyFile", varargs...)
+	ret0, _ := ret[0].(*CopyFileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFile indicates an expected call of CopyFile.
+func (mr *MockFileTransferClientMockRecorder) CopyFile(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFile", reflect.TypeOf((*MockFileTransferClient)(nil).CopyFile), varargs...)
+}
+
+// DeleteFile mocks base method.
+func (m *MockFileTransferClient) DeleteFile(arg0 context.Context, arg1 *DeleteFileRequest, arg2 ...grpc.CallOption) (*DeleteFileResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteFile", varargs...)
+	ret0, _ := ret[0].(*DeleteFileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockFileTransferClientMockRecorder) DeleteFile(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockFileTransferClient)(nil).DeleteFile), varargs...)
+}
+
+// DeltaDownload mocks base method.
+func (m *MockFileTransferClient) DeltaDownload(arg0 context.Context, arg1 ...grpc.CallOption) (FileTransfer_DeltaDownloadClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeltaDownload", varargs...)
+	ret0, _ := ret[0].(FileTransfer_DeltaDownloadClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeltaDownload indicates an expected call of DeltaDownload.
+func (mr *MockFileTransferClientMockRecorder) DeltaDownload(arg0 any, arg1 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeltaDownload", reflect.TypeOf((*MockFileTransferClient)(nil).DeltaDownload), varargs...)
+}
+
+// DownloadFile mocks base method.
+func (m *MockFileTransferClient) DownloadFile(arg0 context.Context, arg1 *DownloadFileRequest, arg2 ...grpc.CallOption) (FileTransfer_DownloadFileClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DownloadFile", varargs...)
+	ret0, _ := ret[0].(FileTransfer_DownloadFileClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadFile indicates an expected call of DownloadFile.
+func (mr *MockFileTransferClientMockRecorder) DownloadFile(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadFile", reflect.TypeOf((*MockFileTransferClient)(nil).DownloadFile), varargs...)
+}
+
 // GetFileContent mocks base method.
 func (m *MockFileTransferClient) GetFileContent(arg0 context.Context, arg1 *FileInfoRequest, arg2 ...grpc.CallOption) (*FileContentResponse, error) {
 	m.ctrl.T.Helper()
@@ -98,3 +179,847 @@ func (mr *MockFileTransferClientMockRecorder) GetFileList(arg0, arg1 any, arg2 .
 	varargs := append([]any{arg0, arg1}, arg2...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileList", reflect.TypeOf((*MockFileTransferClient)(nil).GetFileList), varargs...)
 }
+
+// MakeDirectory mocks base method.
+func (m *MockFileTransferClient) MakeDirectory(arg0 context.Context, arg1 *MakeDirectoryRequest, arg2 ...grpc.CallOption) (*MakeDirectoryResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MakeDirectory", varargs...)
+	ret0, _ := ret[0].(*MakeDirectoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MakeDirectory indicates an expected call of MakeDirectory.
+func (mr *MockFileTransferClientMockRecorder) MakeDirectory(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeDirectory", reflect.TypeOf((*MockFileTransferClient)(nil).MakeDirectory), varargs...)
+}
+
+// RenameFile mocks base method.
+func (m *MockFileTransferClient) RenameFile(arg0 context.Context, arg1 *RenameFileRequest, arg2 ...grpc.CallOption) (*RenameFileResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RenameFile", varargs...)
+	ret0, _ := ret[0].(*RenameFileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameFile indicates an expected call of RenameFile.
+func (mr *MockFileTransferClientMockRecorder) RenameFile(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameFile", reflect.TypeOf((*MockFileTransferClient)(nil).RenameFile), varargs...)
+}
+
+// UploadFile mocks base method.
+func (m *MockFileTransferClient) UploadFile(arg0 context.Context, arg1 ...grpc.CallOption) (FileTransfer_UploadFileClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UploadFile", varargs...)
+	ret0, _ := ret[0].(FileTransfer_UploadFileClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadFile indicates an expected call of UploadFile.
+func (mr *MockFileTransferClientMockRecorder) UploadFile(arg0 any, arg1 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockFileTransferClient)(nil).UploadFile), varargs...)
+}
+
+// MockFileTransfer_DownloadFileClient is a mock of FileTransfer_DownloadFileClient interface.
+type MockFileTransfer_DownloadFileClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileTransfer_DownloadFileClientMockRecorder
+}
+
+// MockFileTransfer_DownloadFileClientMockRecorder is the mock recorder for MockFileTransfer_DownloadFileClient.
+type MockFileTransfer_DownloadFileClientMockRecorder struct {
+	mock *MockFileTransfer_DownloadFileClient
+}
+
+// NewMockFileTransfer_DownloadFileClient creates a new mock instance.
+func NewMockFileTransfer_DownloadFileClient(ctrl *gomock.Controller) *MockFileTransfer_DownloadFileClient {
+	mock := &MockFileTransfer_DownloadFileClient{ctrl: ctrl}
+	mock.recorder = &MockFileTransfer_DownloadFileClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileTransfer_DownloadFileClient) EXPECT() *MockFileTransfer_DownloadFileClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockFileTransfer_DownloadFileClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockFileTransfer_DownloadFileClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockFileTransfer_DownloadFileClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockFileTransfer_DownloadFileClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockFileTransfer_DownloadFileClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockFileTransfer_DownloadFileClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockFileTransfer_DownloadFileClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockFileTransfer_DownloadFileClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockFileTransfer_DownloadFileClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockFileTransfer_DownloadFileClient) Recv() (*FileChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*FileChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockFileTransfer_DownloadFileClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockFileTransfer_DownloadFileClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockFileTransfer_DownloadFileClient) RecvMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockFileTransfer_DownloadFileClientMockRecorder) RecvMsg(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockFileTransfer_DownloadFileClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockFileTransfer_DownloadFileClient) SendMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockFileTransfer_DownloadFileClientMockRecorder) SendMsg(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockFileTransfer_DownloadFileClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockFileTransfer_DownloadFileClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockFileTransfer_DownloadFileClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockFileTransfer_DownloadFileClient)(nil).Trailer))
+}
+
+// MockFileTransfer_DownloadFileServer is a mock of FileTransfer_DownloadFileServer interface.
+type MockFileTransfer_DownloadFileServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileTransfer_DownloadFileServerMockRecorder
+}
+
+// MockFileTransfer_DownloadFileServerMockRecorder is the mock recorder for MockFileTransfer_DownloadFileServer.
+type MockFileTransfer_DownloadFileServerMockRecorder struct {
+	mock *MockFileTransfer_DownloadFileServer
+}
+
+// NewMockFileTransfer_DownloadFileServer creates a new mock instance.
+func NewMockFileTransfer_DownloadFileServer(ctrl *gomock.Controller) *MockFileTransfer_DownloadFileServer {
+	mock := &MockFileTransfer_DownloadFileServer{ctrl: ctrl}
+	mock.recorder = &MockFileTransfer_DownloadFileServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileTransfer_DownloadFileServer) EXPECT() *MockFileTransfer_DownloadFileServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockFileTransfer_DownloadFileServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockFileTransfer_DownloadFileServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockFileTransfer_DownloadFileServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m *MockFileTransfer_DownloadFileServer) RecvMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockFileTransfer_DownloadFileServerMockRecorder) RecvMsg(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockFileTransfer_DownloadFileServer)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method.
+func (m *MockFileTransfer_DownloadFileServer) Send(arg0 *FileChunk) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockFileTransfer_DownloadFileServerMockRecorder) Send(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockFileTransfer_DownloadFileServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockFileTransfer_DownloadFileServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockFileTransfer_DownloadFileServerMockRecorder) SendHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockFileTransfer_DownloadFileServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockFileTransfer_DownloadFileServer) SendMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockFileTransfer_DownloadFileServerMockRecorder) SendMsg(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockFileTransfer_DownloadFileServer)(nil).SendMsg), arg0)
+}
+
+// SetHeader mocks base method.
+func (m *MockFileTransfer_DownloadFileServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockFileTransfer_DownloadFileServerMockRecorder) SetHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockFileTransfer_DownloadFileServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockFileTransfer_DownloadFileServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockFileTransfer_DownloadFileServerMockRecorder) SetTrailer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockFileTransfer_DownloadFileServer)(nil).SetTrailer), arg0)
+}
+
+// MockFileTransfer_UploadFileClient is a mock of FileTransfer_UploadFileClient interface.
+type MockFileTransfer_UploadFileClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileTransfer_UploadFileClientMockRecorder
+}
+
+// MockFileTransfer_UploadFileClientMockRecorder is the mock recorder for MockFileTransfer_UploadFileClient.
+type MockFileTransfer_UploadFileClientMockRecorder struct {
+	mock *MockFileTransfer_UploadFileClient
+}
+
+// NewMockFileTransfer_UploadFileClient creates a new mock instance.
+func NewMockFileTransfer_UploadFileClient(ctrl *gomock.Controller) *MockFileTransfer_UploadFileClient {
+	mock := &MockFileTransfer_UploadFileClient{ctrl: ctrl}
+	mock.recorder = &MockFileTransfer_UploadFileClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileTransfer_UploadFileClient) EXPECT() *MockFileTransfer_UploadFileClientMockRecorder {
+	return m.recorder
+}
+
+// CloseAndRecv mocks base method.
+func (m *MockFileTransfer_UploadFileClient) CloseAndRecv() (*UploadFileResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAndRecv")
+	ret0, _ := ret[0].(*UploadFileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAndRecv indicates an expected call of CloseAndRecv.
+func (mr *MockFileTransfer_UploadFileClientMockRecorder) CloseAndRecv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAndRecv", reflect.TypeOf((*MockFileTransfer_UploadFileClient)(nil).CloseAndRecv))
+}
+
+// CloseSend mocks base method.
+func (m *MockFileTransfer_UploadFileClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockFileTransfer_UploadFileClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockFileTransfer_UploadFileClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockFileTransfer_UploadFileClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockFileTransfer_UploadFileClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockFileTransfer_UploadFileClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockFileTransfer_UploadFileClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockFileTransfer_UploadFileClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockFileTransfer_UploadFileClient)(nil).Header))
+}
+
+// RecvMsg mocks base method.
+func (m *MockFileTransfer_UploadFileClient) RecvMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockFileTransfer_UploadFileClientMockRecorder) RecvMsg(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockFileTransfer_UploadFileClient)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method.
+func (m *MockFileTransfer_UploadFileClient) Send(arg0 *UploadFileRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockFileTransfer_UploadFileClientMockRecorder) Send(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockFileTransfer_UploadFileClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockFileTransfer_UploadFileClient) SendMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockFileTransfer_UploadFileClientMockRecorder) SendMsg(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockFileTransfer_UploadFileClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockFileTransfer_UploadFileClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockFileTransfer_UploadFileClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockFileTransfer_UploadFileClient)(nil).Trailer))
+}
+
+// MockFileTransfer_UploadFileServer is a mock of FileTransfer_UploadFileServer interface.
+type MockFileTransfer_UploadFileServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileTransfer_UploadFileServerMockRecorder
+}
+
+// MockFileTransfer_UploadFileServerMockRecorder is the mock recorder for MockFileTransfer_UploadFileServer.
+type MockFileTransfer_UploadFileServerMockRecorder struct {
+	mock *MockFileTransfer_UploadFileServer
+}
+
+// NewMockFileTransfer_UploadFileServer creates a new mock instance.
+func NewMockFileTransfer_UploadFileServer(ctrl *gomock.Controller) *MockFileTransfer_UploadFileServer {
+	mock := &MockFileTransfer_UploadFileServer{ctrl: ctrl}
+	mock.recorder = &MockFileTransfer_UploadFileServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileTransfer_UploadFileServer) EXPECT() *MockFileTransfer_UploadFileServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockFileTransfer_UploadFileServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockFileTransfer_UploadFileServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockFileTransfer_UploadFileServer)(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockFileTransfer_UploadFileServer) Recv() (*UploadFileRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*UploadFileRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockFileTransfer_UploadFileServerMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockFileTransfer_UploadFileServer)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockFileTransfer_UploadFileServer) RecvMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockFileTransfer_UploadFileServerMockRecorder) RecvMsg(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockFileTransfer_UploadFileServer)(nil).RecvMsg), arg0)
+}
+
+// SendAndClose mocks base method.
+func (m *MockFileTransfer_UploadFileServer) SendAndClose(arg0 *UploadFileResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendAndClose", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAndClose indicates an expected call of SendAndClose.
+func (mr *MockFileTransfer_UploadFileServerMockRecorder) SendAndClose(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAndClose", reflect.TypeOf((*MockFileTransfer_UploadFileServer)(nil).SendAndClose), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockFileTransfer_UploadFileServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockFileTransfer_UploadFileServerMockRecorder) SendHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockFileTransfer_UploadFileServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockFileTransfer_UploadFileServer) SendMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockFileTransfer_UploadFileServerMockRecorder) SendMsg(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockFileTransfer_UploadFileServer)(nil).SendMsg), arg0)
+}
+
+// SetHeader mocks base method.
+func (m *MockFileTransfer_UploadFileServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockFileTransfer_UploadFileServerMockRecorder) SetHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockFileTransfer_UploadFileServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockFileTransfer_UploadFileServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockFileTransfer_UploadFileServerMockRecorder) SetTrailer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockFileTransfer_UploadFileServer)(nil).SetTrailer), arg0)
+}
+
+// MockFileTransfer_DeltaDownloadClient is a mock of FileTransfer_DeltaDownloadClient interface.
+type MockFileTransfer_DeltaDownloadClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileTransfer_DeltaDownloadClientMockRecorder
+}
+
+// MockFileTransfer_DeltaDownloadClientMockRecorder is the mock recorder for MockFileTransfer_DeltaDownloadClient.
+type MockFileTransfer_DeltaDownloadClientMockRecorder struct {
+	mock *MockFileTransfer_DeltaDownloadClient
+}
+
+// NewMockFileTransfer_DeltaDownloadClient creates a new mock instance.
+func NewMockFileTransfer_DeltaDownloadClient(ctrl *gomock.Controller) *MockFileTransfer_DeltaDownloadClient {
+	mock := &MockFileTransfer_DeltaDownloadClient{ctrl: ctrl}
+	mock.recorder = &MockFileTransfer_DeltaDownloadClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileTransfer_DeltaDownloadClient) EXPECT() *MockFileTransfer_DeltaDownloadClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockFileTransfer_DeltaDownloadClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockFileTransfer_DeltaDownloadClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockFileTransfer_DeltaDownloadClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockFileTransfer_DeltaDownloadClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockFileTransfer_DeltaDownloadClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockFileTransfer_DeltaDownloadClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockFileTransfer_DeltaDownloadClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockFileTransfer_DeltaDownloadClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockFileTransfer_DeltaDownloadClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockFileTransfer_DeltaDownloadClient) Recv() (*DeltaChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*DeltaChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockFileTransfer_DeltaDownloadClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockFileTransfer_DeltaDownloadClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockFileTransfer_DeltaDownloadClient) RecvMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockFileTransfer_DeltaDownloadClientMockRecorder) RecvMsg(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockFileTransfer_DeltaDownloadClient)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method.
+func (m *MockFileTransfer_DeltaDownloadClient) Send(arg0 *DeltaDownloadRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockFileTransfer_DeltaDownloadClientMockRecorder) Send(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockFileTransfer_DeltaDownloadClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockFileTransfer_DeltaDownloadClient) SendMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockFileTransfer_DeltaDownloadClientMockRecorder) SendMsg(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockFileTransfer_DeltaDownloadClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockFileTransfer_DeltaDownloadClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockFileTransfer_DeltaDownloadClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockFileTransfer_DeltaDownloadClient)(nil).Trailer))
+}
+
+// MockFileTransfer_DeltaDownloadServer is a mock of FileTransfer_DeltaDownloadServer interface.
+type MockFileTransfer_DeltaDownloadServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileTransfer_DeltaDownloadServerMockRecorder
+}
+
+// MockFileTransfer_DeltaDownloadServerMockRecorder is the mock recorder for MockFileTransfer_DeltaDownloadServer.
+type MockFileTransfer_DeltaDownloadServerMockRecorder struct {
+	mock *MockFileTransfer_DeltaDownloadServer
+}
+
+// NewMockFileTransfer_DeltaDownloadServer creates a new mock instance.
+func NewMockFileTransfer_DeltaDownloadServer(ctrl *gomock.Controller) *MockFileTransfer_DeltaDownloadServer {
+	mock := &MockFileTransfer_DeltaDownloadServer{ctrl: ctrl}
+	mock.recorder = &MockFileTransfer_DeltaDownloadServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileTransfer_DeltaDownloadServer) EXPECT() *MockFileTransfer_DeltaDownloadServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockFileTransfer_DeltaDownloadServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockFileTransfer_DeltaDownloadServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockFileTransfer_DeltaDownloadServer)(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockFileTransfer_DeltaDownloadServer) Recv() (*DeltaDownloadRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*DeltaDownloadRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockFileTransfer_DeltaDownloadServerMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockFileTransfer_DeltaDownloadServer)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockFileTransfer_DeltaDownloadServer) RecvMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockFileTransfer_DeltaDownloadServerMockRecorder) RecvMsg(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockFileTransfer_DeltaDownloadServer)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method.
+func (m *MockFileTransfer_DeltaDownloadServer) Send(arg0 *DeltaChunk) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockFileTransfer_DeltaDownloadServerMockRecorder) Send(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockFileTransfer_DeltaDownloadServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockFileTransfer_DeltaDownloadServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockFileTransfer_DeltaDownloadServerMockRecorder) SendHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockFileTransfer_DeltaDownloadServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockFileTransfer_DeltaDownloadServer) SendMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockFileTransfer_DeltaDownloadServerMockRecorder) SendMsg(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockFileTransfer_DeltaDownloadServer)(nil).SendMsg), arg0)
+}
+
+// SetHeader mocks base method.
+func (m *MockFileTransfer_DeltaDownloadServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockFileTransfer_DeltaDownloadServerMockRecorder) SetHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockFileTransfer_DeltaDownloadServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockFileTransfer_DeltaDownloadServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockFileTransfer_DeltaDownloadServerMockRecorder) SetTrailer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockFileTransfer_DeltaDownloadServer)(nil).SetTrailer), arg0)
+}
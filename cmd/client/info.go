@@ -0,0 +1,46 @@
+package main
+
+import (
+	"filetransfer/api"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// entryTypeNames maps entry types to the names printed by the CLI.
+var entryTypeNames = map[api.EntryType]string{
+	api.EntryType_ENTRY_TYPE_FILE:      "file",
+	api.EntryType_ENTRY_TYPE_DIRECTORY: "directory",
+	api.EntryType_ENTRY_TYPE_SYMLINK:   "symbolic link",
+}
+
+// printFileInfo prints the file metadata as aligned label and value lines, leaving out fields that are not set.
+func printFileInfo(w io.Writer, info *api.FileInfoResponse) {
+	typeName, ok := entryTypeNames[info.Type]
+	if !ok {
+		typeName = "other"
+	}
+
+	fmt.Fprintf(w, "File:     %s\n", info.Filename)
+	fmt.Fprintf(w, "Type:     %s\n", typeName)
+	if info.SymlinkTarget != "" {
+		fmt.Fprintf(w, "Target:   %s\n", info.SymlinkTarget)
+	}
+	fmt.Fprintf(w, "Size:     %d bytes\n", info.Size)
+	mode := os.FileMode(info.Mode & 0777)
+	if info.Type == api.EntryType_ENTRY_TYPE_DIRECTORY {
+		mode |= os.ModeDir
+	}
+	fmt.Fprintf(w, "Mode:     %04o (%s)\n", info.Mode, mode)
+	fmt.Fprintf(w, "Owner:    uid %d, gid %d\n", info.Uid, info.Gid)
+	if info.ModificationTime != nil {
+		fmt.Fprintf(w, "Modified: %s\n", info.ModificationTime.AsTime().Local().Format(time.RFC3339))
+	}
+	if info.MimeType != "" {
+		fmt.Fprintf(w, "MIME:     %s\n", info.MimeType)
+	}
+	if info.Sha256 != "" {
+		fmt.Fprintf(w, "SHA-256:  %s\n", info.Sha256)
+	}
+}
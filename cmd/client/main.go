@@ -1,12 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"filetransfer/internal/client"
+	"filetransfer/internal/compression"
+	"filetransfer/internal/logger"
+	"filetransfer/internal/ratelimit"
+	"filetransfer/internal/tlsconfig"
+	"filetransfer/internal/tracing"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
 
 	"github.com/urfave/cli"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func main() {
@@ -15,59 +28,257 @@ func main() {
 	app.Name = "FileTransferClient"
 	app.Usage = "CLI Client for File Transfer gRPC Service"
 
-	// Define a command-line flag for specifying the gRPC server address
-	var serverAddress string
+	// Define command-line flags for specifying the gRPC server address, the TLS, the logging and the transfer settings
+	var serverAddress, caFile, certFile, keyFile, serverName, logLevel, logFormat, traceExporter, otlpEndpoint, limitRate, compress string
+	var useTLS bool
+	var timeout, transferTimeout, retryBackoff, retryMaxBackoff, retryTimeout time.Duration
+	var retries int
 	app.Flags = []cli.Flag{
 		cli.StringFlag{
-			Name:        "server",
+			Name:        "server, s",
 			Value:       "localhost:50051",
 			Usage:       "Address of the gRPC server",
 			Destination: &serverAddress,
 		},
+		cli.BoolFlag{
+			Name:        "tls",
+			Usage:       "Connect over TLS, implied by --ca-file, --cert and --server-name",
+			Destination: &useTLS,
+		},
+		cli.StringFlag{
+			Name:        "ca-file",
+			Usage:       "Path to a PEM encoded CA bundle used to verify the server instead of the system roots",
+			Destination: &caFile,
+		},
+		cli.StringFlag{
+			Name:        "cert",
+			Usage:       "Path to a PEM encoded client certificate for mutual TLS",
+			Destination: &certFile,
+		},
+		cli.StringFlag{
+			Name:        "key",
+			Usage:       "Path to the PEM encoded private key of the client certificate",
+			Destination: &keyFile,
+		},
+		cli.StringFlag{
+			Name:        "server-name",
+			Usage:       "Override the server name used to verify the server certificate",
+			Destination: &serverName,
+		},
+		cli.StringFlag{
+			Name:        "log-level",
+			Value:       "info",
+			Usage:       "Minimum level of logged messages: debug, info, warn or error",
+			Destination: &logLevel,
+		},
+		cli.StringFlag{
+			Name:        "log-format",
+			Value:       logger.FormatText,
+			Usage:       "Format of logged messages: text or json",
+			Destination: &logFormat,
+		},
+		cli.StringFlag{
+			Name:        "trace-exporter",
+			Value:       tracing.ExporterNone,
+			Usage:       "Exporter of OpenTelemetry traces: none, stdout (written to stderr) or otlp",
+			Destination: &traceExporter,
+		},
+		cli.StringFlag{
+			Name:        "otlp-endpoint",
+			Usage:       "Address of the OpenTelemetry collector for the otlp exporter, an http:// prefix disables TLS",
+			Destination: &otlpEndpoint,
+		},
+		cli.StringFlag{
+			Name:        "limit-rate",
+			Usage:       "Maximum transfer rate of downloads and uploads in bytes per second, with an optional K, M or G suffix",
+			Destination: &limitRate,
+		},
+		cli.StringFlag{
+			Name:        "compress",
+			Value:       compression.None,
+			Usage:       "Compression of transferred file content: none, gzip or zstd, already compressed files are sent uncompressed",
+			Destination: &compress,
+		},
+		cli.DurationFlag{
+			Name:        "timeout",
+			Value:       client.DefaultCallTimeout,
+			Usage:       "Maximum duration of calls without file content transfer including retries, 0 means no limit",
+			Destination: &timeout,
+		},
+		cli.DurationFlag{
+			Name:        "transfer-timeout",
+			Usage:       "Maximum duration of downloads, uploads and copies, 0 means no limit",
+			Destination: &transferTimeout,
+		},
+		cli.IntFlag{
+			Name:        "retries",
+			Value:       client.DefaultRetryPolicy.MaxAttempts - 1,
+			Usage:       "Number of retries of reads failing with Unavailable or DeadlineExceeded, at most 4",
+			Destination: &retries,
+		},
+		cli.DurationFlag{
+			Name:        "retry-backoff",
+			Value:       client.DefaultRetryPolicy.InitialBackoff,
+			Usage:       "Maximum delay before the first retry, doubling with every further retry",
+			Destination: &retryBackoff,
+		},
+		cli.DurationFlag{
+			Name:        "retry-max-backoff",
+			Value:       client.DefaultRetryPolicy.MaxBackoff,
+			Usage:       "Maximum delay between retries",
+			Destination: &retryMaxBackoff,
+		},
+		cli.DurationFlag{
+			Name:        "retry-timeout",
+			Usage:       "Maximum duration of every attempt of a read without file content transfer, 0 means no limit",
+			Destination: &retryTimeout,
+		},
+	}
+
+	// Cancel the running call when the command is interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Create the client logger and the tracer provider according to the global flags,
+	// both write to stderr to keep stdout free for file content
+	var clientLogger *slog.Logger
+	var tracerProvider *sdktrace.TracerProvider
+	app.Before = func(c *cli.Context) error {
+		var err error
+		clientLogger, err = logger.New(os.Stderr, logFormat, logLevel)
+		if err != nil {
+			return err
+		}
+		tracerProvider, err = tracing.NewTracerProvider(context.Background(), "filetransfer-client", traceExporter,
+			tracing.WithOTLPEndpoint(otlpEndpoint), tracing.WithWriter(os.Stderr))
+		return err
+	}
+
+	// Flush the remaining spans before exiting
+	app.After = func(c *cli.Context) error {
+		if tracerProvider == nil {
+			return nil
+		}
+		return tracerProvider.Shutdown(context.Background())
+	}
+
+	// newFileTransferClient creates a file transfer client connected according to the global flags
+	newFileTransferClient := func(opts ...client.Option) (*client.FileTransferClient, error) {
+		if useTLS || caFile != "" || certFile != "" || serverName != "" {
+			tlsConfig, err := tlsconfig.NewClientConfig(caFile, certFile, keyFile, serverName)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, client.WithTLSConfig(tlsConfig))
+		}
+		if limitRate != "" {
+			bytesPerSecond, err := ratelimit.ParseBytes(limitRate)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, client.WithRateLimit(bytesPerSecond))
+		}
+		if err := compression.Validate(compress); err != nil {
+			return nil, err
+		}
+		if retries >= client.MaxRetryAttempts {
+			return nil, fmt.Errorf("--retries must be at most %d, got %d", client.MaxRetryAttempts-1, retries)
+		}
+		retryPolicy := client.RetryPolicy{
+			MaxAttempts:       retries + 1,
+			InitialBackoff:    retryBackoff,
+			MaxBackoff:        retryMaxBackoff,
+			BackoffMultiplier: client.DefaultRetryPolicy.BackoffMultiplier,
+			PerAttemptTimeout: retryTimeout,
+		}
+		opts = append(opts, client.WithCompression(compress), client.WithTracerProvider(tracerProvider),
+			client.WithCallTimeout(timeout), client.WithTransferTimeout(transferTimeout), client.WithRetryPolicy(retryPolicy))
+		return client.NewFileTransferClient(serverAddress, clientLogger, opts...)
 	}
 
 	// Define CLI commands for interacting with the file transfer service
 	app.Commands = []cli.Command{
 		{
-			Name:    "list",
-			Aliases: []string{"ls"},
-			Usage:   "Get the list of files on the server",
+			Name:      "list",
+			Aliases:   []string{"ls"},
+			Usage:     "List the entries of a directory on the server",
+			ArgsUsage: "[directory]",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "recursive, R",
+					Usage: "List the content of subdirectories as well",
+				},
+				cli.UintFlag{
+					Name:  "max-depth, L",
+					Usage: "Maximum number of directory levels to descend into with --recursive, 0 means no limit",
+				},
+			},
 			Action: func(c *cli.Context) error {
-				// Create a logger for the client
-				clientLogger := log.New(os.Stdout, "[Client] ", log.LstdFlags)
-
 				// Create a new file transfer client
-				fileTransferClient, err := client.NewFileTransferClient(serverAddress, clientLogger)
+				fileTransferClient, err := newFileTransferClient()
 				if err != nil {
 					return err
 				}
 				defer fileTransferClient.Close()
 
-				// Retrieve the list of files from the server
-				files, err := fileTransferClient.GetFileList()
+				// Retrieve the entries of the directory from the server, the root by default
+				dir := c.Args().First()
+				entries, err := fileTransferClient.GetFileList(ctx, dir, c.Bool("recursive"), uint32(c.Uint("max-depth")))
 				if err != nil {
 					return err
 				}
 
-				// Print the list of files
-				fmt.Println("Files:")
-				for _, file := range files {
-					fmt.Printf("- %s\n", file)
-				}
+				// Print the entries
+				printList(os.Stdout, listingRoot(dir), entries)
 
 				return nil
 			},
 		},
 		{
-			Name:    "info",
-			Aliases: []string{"i"},
-			Usage:   "Get information about a specific file",
+			Name:      "tree",
+			Usage:     "Show the content of a directory on the server as a tree",
+			ArgsUsage: "[directory]",
+			Flags: []cli.Flag{
+				cli.UintFlag{
+					Name:  "max-depth, L",
+					Usage: "Maximum number of directory levels to show, 0 means no limit",
+				},
+			},
 			Action: func(c *cli.Context) error {
-				// Create a logger for the client
-				clientLogger := log.New(os.Stdout, "[Client] ", log.LstdFlags)
+				// Create a new file transfer client
+				fileTransferClient, err := newFileTransferClient()
+				if err != nil {
+					return err
+				}
+				defer fileTransferClient.Close()
+
+				// Retrieve the whole subtree of the directory from the server, the root by default
+				dir := c.Args().First()
+				entries, err := fileTransferClient.GetFileList(ctx, dir, true, uint32(c.Uint("max-depth")))
+				if err != nil {
+					return err
+				}
+
+				// Print the entries as a tree
+				printTree(os.Stdout, listingRoot(dir), entries)
 
+				return nil
+			},
+		},
+		{
+			Name:      "info",
+			Aliases:   []string{"i"},
+			Usage:     "Get information about a specific file",
+			ArgsUsage: "[filename]",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "hash",
+					Usage: "Also compute the SHA-256 hash of the file content on the server",
+				},
+			},
+			Action: func(c *cli.Context) error {
 				// Create a new file transfer client
-				fileTransferClient, err := client.NewFileTransferClient(serverAddress, clientLogger)
+				fileTransferClient, err := newFileTransferClient()
 				if err != nil {
 					return err
 				}
@@ -80,27 +291,242 @@ func main() {
 				}
 
 				// Retrieve information about the specified file from the server
-				fileInfo, err := fileTransferClient.GetFileInfo(filename)
+				fileInfo, err := fileTransferClient.GetFileInfo(ctx, filename, c.Bool("hash"))
 				if err != nil {
 					return err
 				}
 
 				// Print the file information
-				fmt.Printf("File information for %s:\n%s\n", filename, fileInfo)
+				printFileInfo(os.Stdout, fileInfo)
+
+				return nil
+			},
+		},
+		{
+			Name:      "get",
+			Aliases:   []string{"g"},
+			Usage:     "Download a specific file to a local path or to stdout",
+			ArgsUsage: "[filename] [destination]",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "resume, c",
+					Usage: "Continue a partial download into an existing destination file",
+				},
+				cli.BoolFlag{
+					Name:  "delta",
+					Usage: "Update an existing destination file, downloading only the blocks that differ from it",
+				},
+				cli.BoolFlag{
+					Name:  "verify",
+					Usage: "Verify the downloaded content against the SHA-256 checksum sent by the server (default)",
+				},
+				cli.BoolFlag{
+					Name:  "no-verify",
+					Usage: "Accept the downloaded content without verifying its checksum",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				// Verify the checksum of the downloaded content unless disabled
+				if c.Bool("verify") && c.Bool("no-verify") {
+					return fmt.Errorf("--verify and --no-verify are mutually exclusive")
+				}
+				var opts []client.Option
+				if c.Bool("no-verify") {
+					opts = append(opts, client.WithoutVerification())
+				}
+
+				// Create a new file transfer client
+				fileTransferClient, err := newFileTransferClient(opts...)
+				if err != nil {
+					return err
+				}
+				defer fileTransferClient.Close()
+
+				// Retrieve the filename from the command-line arguments
+				filename := c.Args().First()
+				if filename == "" {
+					return fmt.Errorf("please provide a filename")
+				}
+
+				// Resume the download after the content already present in the destination
+				destination := c.Args().Get(1)
+				if c.Bool("resume") && c.Bool("delta") {
+					return fmt.Errorf("--resume and --delta are mutually exclusive")
+				}
+				if c.Bool("resume") {
+					if destination == "" {
+						return fmt.Errorf("please provide a destination to resume into")
+					}
+
+					written, err := fileTransferClient.ResumeDownload(ctx, filename, destination)
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("Resumed %s into %s (%d bytes)\n", filename, destination, written)
+					return nil
+				}
+
+				// Download only the blocks of the file that differ from the destination
+				if c.Bool("delta") {
+					if destination == "" {
+						return fmt.Errorf("please provide a destination to update")
+					}
+
+					report, err := fileTransferClient.DeltaDownload(ctx, filename, destination)
+					if err != nil {
+						return err
+					}
+
+					if !report.Delta {
+						fmt.Printf("Saved %s to %s (%d bytes, downloaded completely)\n", filename, destination, report.Size)
+						return nil
+					}
+					fmt.Printf("Saved %s to %s (%d bytes, %d reused, %d downloaded, %d bytes saved)\n",
+						filename, destination, report.Size, report.ReusedBytes, report.LiteralBytes, report.BytesSaved())
+					if report.Unprofitable() {
+						fmt.Printf("The delta saved nothing: %s shares too little content with %s, download it without --delta\n",
+							destination, filename)
+					}
+					return nil
+				}
+
+				// Write the content to stdout unless a local destination is provided
+				var out io.Writer = os.Stdout
+				if destination != "" {
+					file, err := os.Create(destination)
+					if err != nil {
+						return err
+					}
+					defer file.Close()
+					out = file
+				}
+
+				// Stream the content of the specified file from the server
+				written, err := fileTransferClient.DownloadFile(ctx, filename, out)
+				if err != nil {
+					// Do not leave corrupted content behind
+					if errors.Is(err, client.ErrChecksumMismatch) && destination != "" {
+						os.Remove(destination)
+					}
+					return err
+				}
+
+				// Report where the file content was saved
+				if destination != "" {
+					fmt.Printf("Saved %s to %s (%d bytes)\n", filename, destination, written)
+				}
 
 				return nil
 			},
 		},
 		{
-			Name:    "get",
-			Aliases: []string{"g"},
-			Usage:   "Get content of a specific file",
+			Name:      "put",
+			Aliases:   []string{"p"},
+			Usage:     "Upload a local file to the server",
+			ArgsUsage: "[local] [remote]",
 			Action: func(c *cli.Context) error {
-				// Create a logger for the client
-				clientLogger := log.New(os.Stdout, "[Client] ", log.LstdFlags)
+				// Create a new file transfer client
+				fileTransferClient, err := newFileTransferClient()
+				if err != nil {
+					return err
+				}
+				defer fileTransferClient.Close()
+
+				// Retrieve the local path from the command-line arguments
+				localPath := c.Args().First()
+				if localPath == "" {
+					return fmt.Errorf("please provide a local file path")
+				}
+
+				// Store the file under its base name unless a remote filename is provided
+				remoteName := c.Args().Get(1)
+				if remoteName == "" {
+					remoteName = filepath.Base(localPath)
+				}
+
+				file, err := os.Open(localPath)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+
+				// Stream the content of the local file to the server
+				resp, err := fileTransferClient.UploadFile(ctx, remoteName, file)
+				if err != nil {
+					return err
+				}
+
+				// Report where the file was stored
+				fmt.Printf("Uploaded %s to %s (%d bytes)\n", localPath, resp.Filename, resp.Size)
+
+				return nil
+			},
+		},
+		{
+			Name:      "sync",
+			Usage:     "Mirror a directory of the server into a local directory, downloading only new and changed files",
+			ArgsUsage: "[remote-dir] [local-dir]",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "delete",
+					Usage: "Delete local files and directories that do not exist on the server",
+				},
+				cli.BoolFlag{
+					Name:  "dry-run, n",
+					Usage: "Only report the changes without making them",
+				},
+				cli.BoolFlag{
+					Name:  "checksum",
+					Usage: "Compare the hash of files whose size and modification time match as well",
+				},
+				cli.BoolFlag{
+					Name:  "delta",
+					Usage: "Download only the blocks of changed files that differ from their local copy",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				// Create a new file transfer client
+				fileTransferClient, err := newFileTransferClient()
+				if err != nil {
+					return err
+				}
+				defer fileTransferClient.Close()
+
+				// Retrieve the directories from the command-line arguments, the remote root is ""
+				if c.NArg() != 2 {
+					return fmt.Errorf("please provide a remote and a local directory")
+				}
+				remoteDir, localDir := c.Args().Get(0), c.Args().Get(1)
 
+				// Mirror the remote directory, printing every change as it is made
+				dryRun := c.Bool("dry-run")
+				report, err := fileTransferClient.Sync(ctx, remoteDir, localDir, client.SyncOptions{
+					Delete:   c.Bool("delete"),
+					DryRun:   dryRun,
+					Checksum: c.Bool("checksum"),
+					Delta:    c.Bool("delta"),
+					OnAction: func(action client.SyncAction) { printSyncAction(os.Stdout, action) },
+				})
+				if report != nil {
+					printSyncReport(os.Stdout, report, dryRun)
+				}
+				return err
+			},
+		},
+		{
+			Name:      "rm",
+			Usage:     "Delete a file or directory on the server",
+			ArgsUsage: "[filename]",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "recursive, r",
+					Usage: "Delete directories together with their content",
+				},
+			},
+			Action: func(c *cli.Context) error {
 				// Create a new file transfer client
-				fileTransferClient, err := client.NewFileTransferClient(serverAddress, clientLogger)
+				fileTransferClient, err := newFileTransferClient()
 				if err != nil {
 					return err
 				}
@@ -112,14 +538,115 @@ func main() {
 					return fmt.Errorf("please provide a filename")
 				}
 
-				// Retrieve the content of the specified file from the server
-				fileContent, err := fileTransferClient.GetFileContent(filename)
+				// Delete the file on the server
+				if err := fileTransferClient.DeleteFile(ctx, filename, c.Bool("recursive")); err != nil {
+					return err
+				}
+
+				fmt.Printf("Deleted %s\n", filename)
+
+				return nil
+			},
+		},
+		{
+			Name:      "mv",
+			Usage:     "Rename or move a file or directory on the server",
+			ArgsUsage: "[source] [destination]",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "force, f",
+					Usage: "Replace an existing destination",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				// Create a new file transfer client
+				fileTransferClient, err := newFileTransferClient()
+				if err != nil {
+					return err
+				}
+				defer fileTransferClient.Close()
+
+				// Retrieve the source and destination from the command-line arguments
+				source, destination := c.Args().Get(0), c.Args().Get(1)
+				if source == "" || destination == "" {
+					return fmt.Errorf("please provide a source and a destination")
+				}
+
+				// Rename the file on the server
+				if err := fileTransferClient.RenameFile(ctx, source, destination, c.Bool("force")); err != nil {
+					return err
+				}
+
+				fmt.Printf("Moved %s to %s\n", source, destination)
+
+				return nil
+			},
+		},
+		{
+			Name:      "cp",
+			Usage:     "Copy a file on the server without downloading it",
+			ArgsUsage: "[source] [destination]",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "force, f",
+					Usage: "Replace an existing destination",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				// Create a new file transfer client
+				fileTransferClient, err := newFileTransferClient()
+				if err != nil {
+					return err
+				}
+				defer fileTransferClient.Close()
+
+				// Retrieve the source and destination from the command-line arguments
+				source, destination := c.Args().Get(0), c.Args().Get(1)
+				if source == "" || destination == "" {
+					return fmt.Errorf("please provide a source and a destination")
+				}
+
+				// Copy the file on the server
+				resp, err := fileTransferClient.CopyFile(ctx, source, destination, c.Bool("force"))
 				if err != nil {
 					return err
 				}
 
-				// Print the file content
-				fmt.Printf("File content for %s:\n%s\n", filename, fileContent.Content)
+				fmt.Printf("Copied %s to %s (%d bytes)\n", source, destination, resp.Size)
+
+				return nil
+			},
+		},
+		{
+			Name:      "mkdir",
+			Usage:     "Create a directory on the server",
+			ArgsUsage: "[directory]",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "parents, p",
+					Usage: "Create missing parent directories and accept an existing directory",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				// Create a new file transfer client
+				fileTransferClient, err := newFileTransferClient()
+				if err != nil {
+					return err
+				}
+				defer fileTransferClient.Close()
+
+				// Retrieve the directory from the command-line arguments
+				dir := c.Args().First()
+				if dir == "" {
+					return fmt.Errorf("please provide a directory")
+				}
+
+				// Create the directory on the server
+				if err := fileTransferClient.MakeDirectory(ctx, dir, c.Bool("parents")); err != nil {
+					return err
+				}
+
+				fmt.Printf("Created %s\n", dir)
 
 				return nil
 			},
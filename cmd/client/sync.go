@@ -0,0 +1,40 @@
+package main
+
+import (
+	"filetransfer/internal/client"
+	"fmt"
+	"io"
+)
+
+// printSyncAction prints a line describing an action of a sync.
+func printSyncAction(w io.Writer, action client.SyncAction) {
+	name := action.Path
+	if action.Directory {
+		name += "/"
+	}
+
+	switch {
+	case action.Err != nil:
+		fmt.Fprintf(w, "%-6s  %s: %v\n", action.Type, name, action.Err)
+	case action.Size > 0:
+		fmt.Fprintf(w, "%-6s  %s (%d bytes)\n", action.Type, name, action.Size)
+	default:
+		fmt.Fprintf(w, "%-6s  %s\n", action.Type, name)
+	}
+}
+
+// printSyncReport prints the summary of a sync.
+func printSyncReport(w io.Writer, report *client.SyncReport, dryRun bool) {
+	if dryRun {
+		fmt.Fprint(w, "Dry run, nothing was changed: ")
+	}
+	fmt.Fprintf(w, "%d created, %d updated, %d unchanged, %d deleted, %d skipped, %d failed, %d bytes transferred\n",
+		report.Created, report.Updated, report.Unchanged, report.Deleted, report.Skipped, report.Failed, report.BytesTransferred)
+	if report.BytesSaved != 0 {
+		fmt.Fprintf(w, "Delta transfer saved %d bytes\n", report.BytesSaved)
+	}
+	if report.UnprofitableDeltas > 0 {
+		fmt.Fprintf(w, "%d delta downloads saved nothing, their local copies share too little content with the server\n",
+			report.UnprofitableDeltas)
+	}
+}
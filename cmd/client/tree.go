@@ -0,0 +1,76 @@
+package main
+
+import (
+	"filetransfer/api"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// listingRoot returns the directory a listing was requested for in the form used by the entry paths.
+func listingRoot(dir string) string {
+	return path.Clean("./" + dir)
+}
+
+// relativePath returns the path of the entry relative to the listed directory.
+func relativePath(root string, entry *api.FileEntry) string {
+	if root == "." {
+		return entry.Path
+	}
+	return strings.TrimPrefix(entry.Path, root+"/")
+}
+
+// printList prints one line per entry with its type, size and path relative to the listed directory.
+func printList(w io.Writer, root string, entries []*api.FileEntry) {
+	for _, entry := range entries {
+		name := relativePath(root, entry)
+		switch entry.Type {
+		case api.EntryType_ENTRY_TYPE_DIRECTORY:
+			fmt.Fprintf(w, "d %12s  %s/\n", "-", name)
+		case api.EntryType_ENTRY_TYPE_SYMLINK:
+			fmt.Fprintf(w, "l %12s  %s\n", "-", name)
+		case api.EntryType_ENTRY_TYPE_FILE:
+			fmt.Fprintf(w, "- %12d  %s\n", entry.Size, name)
+		default:
+			fmt.Fprintf(w, "? %12s  %s\n", "-", name)
+		}
+	}
+}
+
+// printTree prints the entries as an indented tree below the listed directory,
+// followed by the number of directories and files like tree(1).
+func printTree(w io.Writer, root string, entries []*api.FileEntry) {
+	// Group the entries by their parent directory, keeping the listing order
+	children := make(map[string][]*api.FileEntry)
+	var directories, files int
+	for _, entry := range entries {
+		parent := path.Dir(entry.Path)
+		children[parent] = append(children[parent], entry)
+		if entry.Type == api.EntryType_ENTRY_TYPE_DIRECTORY {
+			directories++
+		} else {
+			files++
+		}
+	}
+
+	fmt.Fprintln(w, root)
+	printTreeLevel(w, children, root, "")
+	fmt.Fprintf(w, "\n%d directories, %d files\n", directories, files)
+}
+
+// printTreeLevel prints the children of the parent directory and their subtrees with the given line prefix.
+func printTreeLevel(w io.Writer, children map[string][]*api.FileEntry, parent, prefix string) {
+	entries := children[parent]
+	for i, entry := range entries {
+		connector, indent := "├── ", "│   "
+		if i == len(entries)-1 {
+			connector, indent = "└── ", "    "
+		}
+
+		fmt.Fprintf(w, "%s%s%s\n", prefix, connector, entry.Name)
+		if entry.Type == api.EntryType_ENTRY_TYPE_DIRECTORY {
+			printTreeLevel(w, children, entry.Path, prefix+indent)
+		}
+	}
+}
@@ -1,34 +1,143 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"filetransfer/internal/admission"
+	"filetransfer/internal/authz"
+	"filetransfer/internal/config"
+	"filetransfer/internal/logger"
+	"filetransfer/internal/metrics"
+	"filetransfer/internal/ratelimit"
 	"filetransfer/internal/repository"
 	"filetransfer/internal/server"
+	"filetransfer/internal/tlsconfig"
+	"filetransfer/internal/tracing"
 	"filetransfer/internal/usecase"
-	"log"
+	"flag"
+	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 )
 
 func main() {
-	// Initialize the server logger
-	logger := log.New(os.Stdout, "[Server] ", log.LstdFlags)
+	// Load the configuration from flags, environment variables and the config file
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
 
-	// Create a new instance of the local file repository with the root directory "/"
-	fileRepository := repository.NewLocalFileRepository("/")
+	// Initialize the server logger writing to stderr, messages below the configured level are dropped
+	serverLogger, err := logger.New(os.Stderr, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	serverOptions := []server.Option{
+		server.WithMaxRecvMessageSize(cfg.Limits.MaxRecvMessageSize),
+		server.WithMaxConcurrentStreams(cfg.Limits.MaxConcurrentStreams),
+	}
+
+	// Limit the request rate and the bandwidth of clients if configured
+	if cfg.RateLimit != (config.RateLimitConfig{}) {
+		serverOptions = append(serverOptions, server.WithRateLimiter(ratelimit.New(ratelimit.Config{
+			RequestsPerSecond:    cfg.RateLimit.RequestsPerSecond,
+			RequestBurst:         cfg.RateLimit.RequestBurst,
+			ClientBytesPerSecond: cfg.RateLimit.ClientBandwidth,
+			GlobalBytesPerSecond: cfg.RateLimit.GlobalBandwidth,
+		})))
+	}
+
+	// Load the TLS settings, the server accepts plaintext connections unless a certificate is provided
+	if cfg.TLS.CertFile != "" {
+		tlsConfig, err := tlsconfig.NewServerConfig(cfg.TLS.CertFile, cfg.TLS.KeyFile, cfg.TLS.ClientCAFile)
+		if err != nil {
+			serverLogger.Error("Error loading TLS configuration", "error", err)
+			os.Exit(1)
+		}
+		serverOptions = append(serverOptions, server.WithTLSConfig(tlsConfig))
+	}
+
+	// Load the authorization policy
+	if cfg.AuthzPolicy != "" {
+		policy, err := authz.LoadPolicy(cfg.AuthzPolicy)
+		if err != nil {
+			serverLogger.Error("Error loading authorization policy", "error", err)
+			os.Exit(1)
+		}
+		serverOptions = append(serverOptions, server.WithAuthorizer(policy))
+	}
+
+	// Create the tracer provider exporting the spans of the server, the remaining spans are flushed on shutdown
+	tracerProvider, err := tracing.NewTracerProvider(context.Background(), "filetransfer-server", cfg.Tracing.Exporter,
+		tracing.WithOTLPEndpoint(cfg.Tracing.OTLPEndpoint))
+	if err != nil {
+		serverLogger.Error("Error creating tracer provider", "error", err)
+		os.Exit(1)
+	}
+	serverOptions = append(serverOptions, server.WithTracerProvider(tracerProvider))
+
+	// Create the file repository of the storage backend selected by the storage root
+	fileRepository, err := newFileRepository(context.Background(), cfg)
+	if err != nil {
+		serverLogger.Error("Error opening storage", "root", cfg.StorageRoot, "error", err)
+		os.Exit(1)
+	}
+
+	// Collect metrics of the calls and the repository errors, served on a separate HTTP server
+	var metricsServer *http.Server
+	var serverMetrics *metrics.ServerMetrics
+	if cfg.MetricsAddress != "" {
+		serverMetrics = metrics.NewServerMetrics()
+		fileRepository = serverMetrics.InstrumentRepository(fileRepository)
+		serverOptions = append(serverOptions, server.WithMetrics(serverMetrics))
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", serverMetrics.Handler())
+		metricsServer = &http.Server{Addr: cfg.MetricsAddress, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverLogger.Error("Error serving metrics", "address", cfg.MetricsAddress, "error", err)
+			}
+		}()
+		serverLogger.Info("Metrics server started", "address", cfg.MetricsAddress)
+	}
+
+	// Queue calls exceeding the concurrency limits and shed them when the queue is full or the wait too long
+	if cfg.Limits.MaxConcurrentCalls > 0 || cfg.Limits.MaxConcurrentTransfers > 0 || cfg.Limits.MaxBufferedBytes > 0 {
+		var admissionOptions []admission.Option
+		if serverMetrics != nil {
+			admissionOptions = append(admissionOptions, admission.WithMetrics(serverMetrics))
+		}
+		serverOptions = append(serverOptions, server.WithAdmissionController(admission.New(admission.Config{
+			MaxConcurrentCalls:     cfg.Limits.MaxConcurrentCalls,
+			MaxConcurrentTransfers: cfg.Limits.MaxConcurrentTransfers,
+			MaxBufferedBytes:       cfg.Limits.MaxBufferedBytes,
+			MaxQueueLength:         cfg.Limits.MaxQueueLength,
+			QueueTimeout:           time.Duration(cfg.Limits.QueueTimeout),
+		}, admissionOptions...)))
+	}
 
 	// Create a new file usecase with the file repository
-	fileUsecase := usecase.NewFileUsecase(fileRepository)
+	fileUsecase := usecase.NewFileUsecase(fileRepository, usecase.WithTracerProvider(tracerProvider))
 
 	// Create a new file transfer server with the file usecase and logger
-	fileServer := server.NewFileTransferServer(fileUsecase, logger)
+	fileServer := server.NewFileTransferServer(fileUsecase, serverLogger, serverOptions...)
 
-	// Start the server in a separate goroutine
-	go func() {
-		if err := fileServer.Start(50051); err != nil {
-			logger.Printf("Error starting server: %v", err)
-		}
-	}()
+	// Start the server, the server logs why it failed to listen
+	if err := fileServer.Start(cfg.ListenAddress); err != nil {
+		os.Exit(1)
+	}
 
 	// Set up a signal channel to handle interrupt and termination signals
 	sigCh := make(chan os.Signal, 1)
@@ -36,8 +145,41 @@ func main() {
 
 	// Wait for a signal
 	sig := <-sigCh
-	logger.Printf("Received signal %v. Shutting down...", sig)
+	serverLogger.Info("Shutting down", "signal", sig.String())
 
 	// Stop the server gracefully
 	fileServer.Stop()
+	if metricsServer != nil {
+		metricsServer.Close()
+	}
+	if err := tracerProvider.Shutdown(context.Background()); err != nil {
+		serverLogger.Error("Error flushing traces", "error", err)
+	}
+}
+
+// newFileRepository returns the file repository of the storage backend selected by the configured storage root.
+func newFileRepository(ctx context.Context, cfg *config.ServerConfig) (repository.FileRepository, error) {
+	backend, location := cfg.StorageBackend()
+	switch backend {
+	case config.StorageDedup:
+		return repository.NewDedupFileRepository(location)
+	case config.StorageMemory:
+		return repository.NewMemFileRepository(repository.MemConfig{
+			MaxFileSize: cfg.Memory.MaxFileSize,
+			MaxSize:     cfg.Memory.MaxSize,
+		}), nil
+	case config.StorageS3:
+		bucket, prefix, _ := strings.Cut(location, "/")
+		return repository.NewS3FileRepository(ctx, repository.S3Config{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          bucket,
+			Prefix:          prefix,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+			PathStyle:       cfg.S3.PathStyle,
+		})
+	default:
+		return repository.NewLocalFileRepository(location), nil
+	}
 }
@@ -0,0 +1,210 @@
+package admission
+
+import (
+	"context"
+	"filetransfer/internal/metrics"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Names of the limited resources, used in errors and metrics.
+const (
+	ResourceCalls         = "calls"
+	ResourceTransfers     = "transfers"
+	ResourceBufferedBytes = "buffered_bytes"
+)
+
+// Reasons for rejecting a call, used in metrics.
+const (
+	ReasonQueueFull = "queue_full"
+	ReasonTimeout   = "timeout"
+	ReasonTooLarge  = "too_large"
+)
+
+// Config holds the limits enforced by a Controller, zero limits disable the respective limit.
+type Config struct {
+	// MaxConcurrentCalls is the number of calls running at once.
+	MaxConcurrentCalls int64
+	// MaxConcurrentTransfers is the number of file transfers running at once.
+	MaxConcurrentTransfers int64
+	// MaxBufferedBytes is the number of bytes the running calls may hold in memory at once.
+	MaxBufferedBytes int64
+	// MaxQueueLength is the number of calls that may wait for a resource at once, more calls are rejected.
+	MaxQueueLength int
+	// QueueTimeout is how long a call waits for admission before it is rejected, zero rejects calls
+	// that cannot be admitted at once.
+	QueueTimeout time.Duration
+}
+
+// Cost describes the resources a call needs besides a call slot.
+type Cost struct {
+	// Transfer is set for calls transferring file content.
+	Transfer bool
+	// BufferedBytes is the number of bytes the call holds in memory.
+	BufferedBytes int64
+}
+
+// Controller decides whether calls may run, so bursts of calls cannot exhaust the memory of the server.
+// Calls exceeding a limit wait in a bounded queue for a bounded time. It is safe for concurrent use.
+type Controller struct {
+	queueTimeout time.Duration
+	metrics      *metrics.ServerMetrics
+	calls        *resource
+	transfers    *resource
+	bytes        *resource
+}
+
+// Option configures optional behaviour of a Controller.
+type Option func(*Controller)
+
+// WithMetrics makes the controller record the length of its queues and the rejected calls.
+func WithMetrics(m *metrics.ServerMetrics) Option {
+	return func(c *Controller) {
+		c.metrics = m
+	}
+}
+
+// New creates a Controller enforcing the provided limits.
+func New(config Config, opts ...Option) *Controller {
+	c := &Controller{
+		queueTimeout: config.QueueTimeout,
+		calls:        newResource(ResourceCalls, config.MaxConcurrentCalls, config.MaxQueueLength),
+		transfers:    newResource(ResourceTransfers, config.MaxConcurrentTransfers, config.MaxQueueLength),
+		bytes:        newResource(ResourceBufferedBytes, config.MaxBufferedBytes, config.MaxQueueLength),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Admit waits until a call with the provided cost may run and reserves the resources it needs.
+// The returned function releases them and must be called when the call has finished.
+// A call that cannot be admitted within the queue timeout is rejected with Unavailable,
+// a call finding the queue full or needing more than a limit allows is rejected with ResourceExhausted.
+func (c *Controller) Admit(ctx context.Context, cost Cost) (func(), error) {
+	waitCtx, cancel := context.WithTimeout(ctx, c.queueTimeout)
+	defer cancel()
+
+	var releases []func()
+	release := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+
+	acquire := func(r *resource, n int64) error {
+		if r == nil || n == 0 {
+			return nil
+		}
+		if err := c.acquire(ctx, waitCtx, r, n); err != nil {
+			return err
+		}
+		releases = append(releases, func() { r.release(n) })
+		return nil
+	}
+
+	// Resources are always taken in the same order, so waiting calls cannot deadlock each other
+	err := acquire(c.calls, 1)
+	if err == nil && cost.Transfer {
+		err = acquire(c.transfers, 1)
+	}
+	if err == nil {
+		err = acquire(c.bytes, cost.BufferedBytes)
+	}
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// acquire takes n units of the resource, waiting in its queue until waitCtx is done if necessary.
+// The error reports why the call was rejected.
+func (c *Controller) acquire(ctx, waitCtx context.Context, r *resource, n int64) error {
+	if n > r.limit {
+		c.rejected(r.name, ReasonTooLarge)
+		return status.Errorf(codes.ResourceExhausted, "call needs %d %s, more than the limit of %d", n, r.name, r.limit)
+	}
+	if r.sem.TryAcquire(n) {
+		return nil
+	}
+
+	if !r.enqueue() {
+		c.rejected(r.name, ReasonQueueFull)
+		return status.Errorf(codes.ResourceExhausted, "server overloaded: too many calls waiting for %s", r.name)
+	}
+	if c.metrics != nil {
+		c.metrics.AdmissionQueued(r.name)
+	}
+	err := r.sem.Acquire(waitCtx, n)
+	r.dequeue()
+	if c.metrics != nil {
+		c.metrics.AdmissionDequeued(r.name)
+	}
+	if err == nil {
+		return nil
+	}
+
+	// The call was canceled by the client rather than rejected
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+	c.rejected(r.name, ReasonTimeout)
+	return status.Errorf(codes.Unavailable, "server overloaded: no %s available within %v", r.name, c.queueTimeout)
+}
+
+// rejected records a rejected call.
+func (c *Controller) rejected(resource, reason string) {
+	if c.metrics != nil {
+		c.metrics.AdmissionRejected(resource, reason)
+	}
+}
+
+// resource is a limited resource with a queue of calls waiting for it.
+type resource struct {
+	name     string
+	limit    int64
+	sem      *semaphore.Weighted
+	maxQueue int
+
+	mu     sync.Mutex
+	queued int
+}
+
+// newResource returns a resource with the limit, or nil if the resource is not limited.
+// A maxQueue of zero does not limit the number of waiting calls.
+func newResource(name string, limit int64, maxQueue int) *resource {
+	if limit <= 0 {
+		return nil
+	}
+	return &resource{name: name, limit: limit, sem: semaphore.NewWeighted(limit), maxQueue: maxQueue}
+}
+
+// enqueue adds a call to the queue, it returns false if the queue is full.
+func (r *resource) enqueue() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxQueue > 0 && r.queued >= r.maxQueue {
+		return false
+	}
+	r.queued++
+	return true
+}
+
+// dequeue removes a call from the queue.
+func (r *resource) dequeue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queued--
+}
+
+// release returns n units of the resource.
+func (r *resource) release(n int64) {
+	r.sem.Release(n)
+}
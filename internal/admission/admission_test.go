@@ -0,0 +1,142 @@
+package admission
+
+import (
+	"context"
+	"filetransfer/internal/metrics"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestController_Unlimited(t *testing.T) {
+	controller := New(Config{})
+
+	for i := 0; i < 100; i++ {
+		_, err := controller.Admit(context.Background(), Cost{Transfer: true, BufferedBytes: 1 << 30})
+		require.NoError(t, err)
+	}
+}
+
+func TestController_QueuesUntilReleased(t *testing.T) {
+	controller := New(Config{MaxConcurrentCalls: 1, QueueTimeout: 5 * time.Second})
+
+	release, err := controller.Admit(context.Background(), Cost{})
+	require.NoError(t, err)
+
+	// The second call waits in the queue until the first one finishes
+	admitted := make(chan error)
+	go func() {
+		release, err := controller.Admit(context.Background(), Cost{})
+		if err == nil {
+			release()
+		}
+		admitted <- err
+	}()
+
+	select {
+	case <-admitted:
+		t.Fatal("the second call must wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	assert.NoError(t, <-admitted)
+}
+
+func TestController_QueueTimeout(t *testing.T) {
+	controller := New(Config{MaxConcurrentTransfers: 1, QueueTimeout: 20 * time.Millisecond})
+
+	_, err := controller.Admit(context.Background(), Cost{Transfer: true})
+	require.NoError(t, err)
+
+	// Other calls are not limited by the transfer limit
+	_, err = controller.Admit(context.Background(), Cost{})
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = controller.Admit(context.Background(), Cost{Transfer: true})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestController_QueueFull(t *testing.T) {
+	m := metrics.NewServerMetrics()
+	controller := New(Config{MaxConcurrentCalls: 1, MaxQueueLength: 1, QueueTimeout: 5 * time.Second}, WithMetrics(m))
+
+	release, err := controller.Admit(context.Background(), Cost{})
+	require.NoError(t, err)
+
+	// One call fits into the queue
+	ctx, cancel := context.WithCancel(context.Background())
+	queued := make(chan error)
+	go func() {
+		_, err := controller.Admit(ctx, Cost{})
+		queued <- err
+	}()
+	require.Eventually(t, func() bool {
+		return queueLength(t, m) == 1
+	}, time.Second, time.Millisecond)
+
+	// The next one is rejected at once
+	_, err = controller.Admit(context.Background(), Cost{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// A queued call canceled by the client is not counted as a rejection
+	cancel()
+	assert.Equal(t, codes.Canceled, status.Code(<-queued))
+	release()
+
+	expected := `
+# HELP filetransfer_admission_queue_length Number of calls waiting for admission by limited resource.
+# TYPE filetransfer_admission_queue_length gauge
+filetransfer_admission_queue_length{resource="calls"} 0
+# HELP filetransfer_admission_rejections_total Number of calls rejected by admission control by limited resource and reason.
+# TYPE filetransfer_admission_rejections_total counter
+filetransfer_admission_rejections_total{reason="queue_full",resource="calls"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(m.Registry(), strings.NewReader(expected),
+		"filetransfer_admission_queue_length", "filetransfer_admission_rejections_total"))
+}
+
+func TestController_BufferedBytes(t *testing.T) {
+	controller := New(Config{MaxConcurrentCalls: 2, MaxBufferedBytes: 100})
+
+	// A call needing more than the limit can never run
+	_, err := controller.Admit(context.Background(), Cost{BufferedBytes: 101})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	release, err := controller.Admit(context.Background(), Cost{BufferedBytes: 80})
+	require.NoError(t, err)
+
+	// A rejected call releases the call slot it already reserved
+	_, err = controller.Admit(context.Background(), Cost{BufferedBytes: 30})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = controller.Admit(context.Background(), Cost{BufferedBytes: 20})
+	require.NoError(t, err)
+
+	release()
+	_, err = controller.Admit(context.Background(), Cost{BufferedBytes: 80})
+	assert.NoError(t, err)
+}
+
+// queueLength returns the number of calls waiting for a call slot.
+func queueLength(t *testing.T, m *metrics.ServerMetrics) float64 {
+	families, err := m.Registry().Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() == "filetransfer_admission_queue_length" {
+			for _, metric := range family.GetMetric() {
+				if metric.GetLabel()[0].GetValue() == ResourceCalls {
+					return metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
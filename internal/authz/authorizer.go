@@ -0,0 +1,44 @@
+package authz
+
+import (
+	"errors"
+	"filetransfer/internal/identity"
+)
+
+// ErrPermissionDenied is returned when a caller is not allowed to perform an operation on a path.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Operation is a kind of access to a path that can be granted to callers.
+type Operation string
+
+const (
+	// OperationList allows seeing a path in file listings.
+	OperationList Operation = "list"
+	// OperationInfo allows retrieving the metadata of a path.
+	OperationInfo Operation = "info"
+	// OperationRead allows downloading the content of a path.
+	OperationRead Operation = "read"
+	// OperationWrite allows creating or replacing the content of a path and creating directories.
+	OperationWrite Operation = "write"
+	// OperationDelete allows deleting a path and moving it away.
+	OperationDelete Operation = "delete"
+)
+
+// operations contains every known operation.
+var operations = map[Operation]bool{
+	OperationList:   true,
+	OperationInfo:   true,
+	OperationRead:   true,
+	OperationWrite:  true,
+	OperationDelete: true,
+}
+
+// Authorizer is an interface for deciding which operations a caller may perform on which paths.
+// Paths are relative to the storage root and use forward slashes.
+type Authorizer interface {
+	// Authorize returns ErrPermissionDenied if the identity may not perform the operation on the path.
+	Authorize(id identity.Identity, op Operation, path string) error
+
+	// CanSee reports whether an entry at the path should be visible to the identity in file listings.
+	CanSee(id identity.Identity, path string) bool
+}
@@ -0,0 +1,176 @@
+package authz
+
+import (
+	"filetransfer/internal/identity"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Everyone is the user name that matches every caller, including anonymous ones.
+const Everyone = "*"
+
+// Rule grants operations on path prefixes to users and groups.
+type Rule struct {
+	// Users are the user names the rule applies to, Everyone matches all callers.
+	Users []string `yaml:"users"`
+	// Groups are the group names the rule applies to.
+	Groups []string `yaml:"groups"`
+	// Paths are the path prefixes the rule applies to, "/" matches the whole storage.
+	// A prefix matches the path itself and everything below it.
+	Paths []string `yaml:"paths"`
+	// Operations are the operations granted by the rule.
+	Operations []Operation `yaml:"operations"`
+}
+
+// policyFile is the layout of a policy file.
+type policyFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Policy is an Authorizer granting access according to a list of rules.
+// Every operation not granted by at least one rule is denied.
+type Policy struct {
+	rules []Rule
+}
+
+// NewPolicy creates a new instance of Policy with the provided rules.
+// It returns an error if a rule does not name any user or group, path or known operation.
+func NewPolicy(rules []Rule) (*Policy, error) {
+	normalized := make([]Rule, 0, len(rules))
+	for i, rule := range rules {
+		if len(rule.Users) == 0 && len(rule.Groups) == 0 {
+			return nil, fmt.Errorf("rule %d: no users or groups", i+1)
+		}
+		if len(rule.Paths) == 0 {
+			return nil, fmt.Errorf("rule %d: no paths", i+1)
+		}
+		if len(rule.Operations) == 0 {
+			return nil, fmt.Errorf("rule %d: no operations", i+1)
+		}
+		for _, op := range rule.Operations {
+			if !operations[op] {
+				return nil, fmt.Errorf("rule %d: unknown operation %q", i+1, op)
+			}
+		}
+
+		paths := make([]string, len(rule.Paths))
+		for j, p := range rule.Paths {
+			paths[j] = normalizePath(p)
+		}
+		rule.Paths = paths
+		normalized = append(normalized, rule)
+	}
+
+	return &Policy{
+		rules: normalized,
+	}, nil
+}
+
+// LoadPolicy creates a new instance of Policy from a YAML policy file of the form:
+//
+//	rules:
+//	  - users: [alice]
+//	    groups: [developers]
+//	    paths: [builds/, docs/]
+//	    operations: [list, info, read]
+func LoadPolicy(policyPath string) (*Policy, error) {
+	content, err := os.ReadFile(policyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var file policyFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("parsing policy %s: %w", policyPath, err)
+	}
+
+	policy, err := NewPolicy(file.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("policy %s: %w", policyPath, err)
+	}
+	return policy, nil
+}
+
+// Authorize returns ErrPermissionDenied unless a rule grants the operation on the path to the identity.
+func (p *Policy) Authorize(id identity.Identity, op Operation, filePath string) error {
+	filePath = normalizePath(filePath)
+	for _, rule := range p.rules {
+		if !rule.appliesTo(id) || !rule.grants(op) {
+			continue
+		}
+		for _, prefix := range rule.Paths {
+			if hasPathPrefix(filePath, prefix) {
+				return nil
+			}
+		}
+	}
+	return ErrPermissionDenied
+}
+
+// CanSee reports whether the identity may list the path itself or anything below it,
+// so that directories leading to permitted paths stay visible.
+func (p *Policy) CanSee(id identity.Identity, filePath string) bool {
+	if p.Authorize(id, OperationList, filePath) == nil {
+		return true
+	}
+
+	filePath = normalizePath(filePath)
+	for _, rule := range p.rules {
+		if !rule.appliesTo(id) || !rule.grants(OperationList) {
+			continue
+		}
+		for _, prefix := range rule.Paths {
+			if hasPathPrefix(prefix, filePath) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// appliesTo reports whether the rule names the user or one of the groups of the identity.
+func (r Rule) appliesTo(id identity.Identity) bool {
+	for _, user := range r.Users {
+		if user == Everyone || (id.User != "" && user == id.User) {
+			return true
+		}
+	}
+	for _, group := range r.Groups {
+		for _, idGroup := range id.Groups {
+			if group == idGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// grants reports whether the rule grants the operation.
+func (r Rule) grants(op Operation) bool {
+	for _, granted := range r.Operations {
+		if granted == op {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePath converts a path to a clean slash-separated path relative to the storage root,
+// where "." stands for the root itself.
+func normalizePath(p string) string {
+	cleaned := strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(p)), "/")
+	if cleaned == "" {
+		return "."
+	}
+	return cleaned
+}
+
+// hasPathPrefix reports whether the normalized path equals the normalized prefix or is located below it.
+func hasPathPrefix(p, prefix string) bool {
+	return prefix == "." || p == prefix || strings.HasPrefix(p, prefix+"/")
+}
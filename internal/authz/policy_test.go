@@ -0,0 +1,106 @@
+package authz
+
+import (
+	"filetransfer/internal/identity"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPolicy(t *testing.T) *Policy {
+	policy, err := NewPolicy([]Rule{
+		{Users: []string{"alice"}, Paths: []string{"/"}, Operations: []Operation{OperationList, OperationInfo, OperationRead, OperationWrite}},
+		{Groups: []string{"developers"}, Paths: []string{"builds/"}, Operations: []Operation{OperationList, OperationRead}},
+		{Users: []string{Everyone}, Paths: []string{"public"}, Operations: []Operation{OperationList, OperationRead}},
+	})
+	require.NoError(t, err)
+	return policy
+}
+
+func TestPolicy_Authorize(t *testing.T) {
+	policy := newTestPolicy(t)
+	alice := identity.Identity{User: "alice"}
+	bob := identity.Identity{User: "bob", Groups: []string{"developers"}}
+	anonymous := identity.Identity{}
+
+	for _, tc := range []struct {
+		id      identity.Identity
+		op      Operation
+		path    string
+		allowed bool
+	}{
+		{alice, OperationWrite, "secret/file.txt", true},
+		{alice, OperationRead, "", true},
+		{bob, OperationRead, "builds/app.tar", true},
+		{bob, OperationRead, "./builds/../builds/app.tar", true},
+		{bob, OperationRead, "builds", true},
+		{bob, OperationWrite, "builds/app.tar", false},
+		{bob, OperationRead, "builds-old/app.tar", false},
+		{bob, OperationRead, "secret/file.txt", false},
+		{bob, OperationRead, "builds/../secret/file.txt", false},
+		{bob, OperationRead, "public/readme.md", true},
+		{anonymous, OperationRead, "public/readme.md", true},
+		{anonymous, OperationInfo, "public/readme.md", false},
+		{anonymous, OperationRead, "builds/app.tar", false},
+	} {
+		err := policy.Authorize(tc.id, tc.op, tc.path)
+		if tc.allowed {
+			assert.NoError(t, err, "%s %s %s", tc.id.User, tc.op, tc.path)
+		} else {
+			assert.ErrorIs(t, err, ErrPermissionDenied, "%s %s %s", tc.id.User, tc.op, tc.path)
+		}
+	}
+}
+
+func TestPolicy_CanSee(t *testing.T) {
+	policy := newTestPolicy(t)
+	bob := identity.Identity{User: "bob", Groups: []string{"developers"}}
+
+	assert.True(t, policy.CanSee(bob, "builds"))
+	assert.True(t, policy.CanSee(bob, "builds/app.tar"))
+	assert.True(t, policy.CanSee(bob, "."))
+	assert.False(t, policy.CanSee(bob, "secret"))
+	assert.False(t, policy.CanSee(bob, "secret.txt"))
+	assert.False(t, policy.CanSee(identity.Identity{}, "builds"))
+}
+
+func TestNewPolicy_InvalidRules(t *testing.T) {
+	for _, rule := range []Rule{
+		{Paths: []string{"/"}, Operations: []Operation{OperationRead}},
+		{Users: []string{"alice"}, Operations: []Operation{OperationRead}},
+		{Users: []string{"alice"}, Paths: []string{"/"}},
+		{Users: []string{"alice"}, Paths: []string{"/"}, Operations: []Operation{"execute"}},
+	} {
+		_, err := NewPolicy([]Rule{rule})
+		assert.Error(t, err)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+	err := os.WriteFile(policyPath, []byte(`
+rules:
+  - users: [alice]
+    groups: [developers]
+    paths: [builds/, docs]
+    operations: [list, info, read]
+`), 0644)
+	require.NoError(t, err)
+
+	policy, err := LoadPolicy(policyPath)
+	require.NoError(t, err)
+	assert.NoError(t, policy.Authorize(identity.Identity{User: "alice"}, OperationInfo, "docs/guide.md"))
+	assert.NoError(t, policy.Authorize(identity.Identity{User: "carol", Groups: []string{"developers"}}, OperationRead, "builds/app.tar"))
+	assert.ErrorIs(t, policy.Authorize(identity.Identity{User: "alice"}, OperationWrite, "docs/guide.md"), ErrPermissionDenied)
+
+	err = os.WriteFile(policyPath, []byte("rules:\n  - users: [alice]\n    paths: [/]\n    operations: [delete-everything]\n"), 0644)
+	require.NoError(t, err)
+	_, err = LoadPolicy(policyPath)
+	assert.Error(t, err)
+
+	_, err = LoadPolicy(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
@@ -2,28 +2,176 @@ package client
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
 	"filetransfer/api"
 	"filetransfer/internal/client/client_interceptor"
+	"filetransfer/internal/compression"
 	"filetransfer/internal/logger"
+	"filetransfer/internal/ratelimit"
+	"filetransfer/internal/tracing"
+	"fmt"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"hash"
+	"io"
+	"os"
 	"time"
 )
 
+// uploadChunkSize is the maximum number of content bytes sent in a single upload message.
+const uploadChunkSize = 64 * 1024
+
+var (
+	// ErrChecksumMismatch is returned when downloaded content does not match the checksum sent by the server.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrChecksumMissing is returned when the server finishes a download without sending a checksum.
+	ErrChecksumMissing = errors.New("server did not send a checksum")
+	// ErrLocalFileLarger is returned when a download is resumed into a local file larger than the file on the server.
+	ErrLocalFileLarger = errors.New("local file is larger than the file on the server")
+)
+
 // FileTransferClient represents a gRPC client for file transfer operations.
+// Every call ends when the context passed to it is done or its timeout expires, whichever comes first.
+// Idempotent reads failing with a transient error are retried according to the retry policy.
 type FileTransferClient struct {
-	conn   *grpc.ClientConn
-	client api.FileTransferClient
-	logger logger.ClientLogger
+	conn             *grpc.ClientConn
+	client           api.FileTransferClient
+	logger           logger.ClientLogger
+	skipVerification bool
+	// bandwidth limits the content bytes transferred by downloads and uploads, nil means no limit.
+	bandwidth *rate.Limiter
+	// compressor is the name of the compressor of transfers, empty or compression.None disables compression.
+	compressor string
+	// callTimeout limits calls without file content transfer, transferTimeout limits downloads, uploads and copies.
+	// Zero means no limit besides the deadline of the context of the call.
+	callTimeout     time.Duration
+	transferTimeout time.Duration
+}
+
+// Option configures optional behaviour of a FileTransferClient.
+type Option func(*clientOptions)
+
+// clientOptions holds the optional settings of a FileTransferClient.
+type clientOptions struct {
+	tlsConfig        *tls.Config
+	skipVerification bool
+	tracerProvider   trace.TracerProvider
+	bytesPerSecond   int64
+	compressor       string
+	callTimeout      time.Duration
+	transferTimeout  time.Duration
+	retryPolicy      RetryPolicy
+}
+
+// WithTLSConfig makes the client connect to the server over TLS using the provided configuration.
+// Without it the connection is not encrypted.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(o *clientOptions) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// WithoutVerification makes the client accept downloaded content without verifying its checksum.
+func WithoutVerification() Option {
+	return func(o *clientOptions) {
+		o.skipVerification = true
+	}
+}
+
+// WithTracerProvider makes the client create a span for every call with the provided tracer provider
+// and propagate the trace context to the server.
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(o *clientOptions) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
+// WithRateLimit limits the content bytes downloaded and uploaded by the client to bytesPerSecond,
+// shared by all transfers of the client. A limit of zero means no limit.
+func WithRateLimit(bytesPerSecond int64) Option {
+	return func(o *clientOptions) {
+		o.bytesPerSecond = bytesPerSecond
+	}
+}
+
+// WithCompression makes the client compress file content transfers with the named compressor,
+// compression.Gzip or compression.Zstd. Content that is already compressed is transferred uncompressed.
+func WithCompression(compressor string) Option {
+	return func(o *clientOptions) {
+		o.compressor = compressor
+	}
+}
+
+// WithCallTimeout limits how long calls without file content transfer may take, including their retries.
+// It defaults to DefaultCallTimeout, zero disables the limit.
+func WithCallTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.callTimeout = timeout
+	}
+}
+
+// WithTransferTimeout limits how long downloads, uploads and copies on the server may take.
+// By default they are not limited, since the time depends on the size of the file.
+func WithTransferTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.transferTimeout = timeout
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy for retrying idempotent reads.
+// A policy with less than two attempts disables retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.retryPolicy = policy
+	}
 }
 
 // NewFileTransferClient creates a new FileTransferClient instance.
 // It establishes a connection to the gRPC server at the specified address.
-func NewFileTransferClient(serverAddress string, logger logger.ClientLogger) (*FileTransferClient, error) {
+func NewFileTransferClient(serverAddress string, logger logger.ClientLogger, opts ...Option) (*FileTransferClient, error) {
+	options := &clientOptions{callTimeout: DefaultCallTimeout, retryPolicy: DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if err := options.retryPolicy.Validate(); err != nil {
+		return nil, err
+	}
+
+	transportCredentials := insecure.NewCredentials()
+	if options.tlsConfig != nil {
+		transportCredentials = credentials.NewTLS(options.tlsConfig)
+	}
+
+	// Tracing comes first, so the messages logged for a call carry its trace ID
+	var unaryInterceptors []grpc.UnaryClientInterceptor
+	var streamInterceptors []grpc.StreamClientInterceptor
+	if options.tracerProvider != nil {
+		tracingOptions := []otelgrpc.Option{otelgrpc.WithTracerProvider(options.tracerProvider), otelgrpc.WithPropagators(tracing.Propagator())}
+		unaryInterceptors = append(unaryInterceptors, otelgrpc.UnaryClientInterceptor(tracingOptions...))
+		streamInterceptors = append(streamInterceptors, otelgrpc.StreamClientInterceptor(tracingOptions...))
+	}
+	unaryInterceptors = append(unaryInterceptors, client_interceptor.ClientLoggingInterceptor(logger))
+	// Retries come after logging, so a call is logged once with the result of its last attempt
+	if options.retryPolicy.MaxAttempts >= 2 {
+		unaryInterceptors = append(unaryInterceptors, options.retryPolicy.unaryInterceptor())
+	}
+	streamInterceptors = append(streamInterceptors, client_interceptor.ClientStreamLoggingInterceptor(logger))
+
 	conn, err := grpc.Dial(
 		serverAddress,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithUnaryInterceptor(client_interceptor.ClientLoggingInterceptor(logger)),
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithChainUnaryInterceptor(unaryInterceptors...),
+		grpc.WithChainStreamInterceptor(streamInterceptors...),
+		grpc.WithDefaultServiceConfig(options.retryPolicy.serviceConfig()),
 	)
 	if err != nil {
 		return nil, err
@@ -32,9 +180,14 @@ func NewFileTransferClient(serverAddress string, logger logger.ClientLogger) (*F
 	fileTransferClient := api.NewFileTransferClient(conn)
 
 	return &FileTransferClient{
-		conn:   conn,
-		client: fileTransferClient,
-		logger: logger,
+		conn:             conn,
+		client:           fileTransferClient,
+		logger:           logger,
+		skipVerification: options.skipVerification,
+		bandwidth:        ratelimit.NewBandwidthLimiter(options.bytesPerSecond),
+		compressor:       options.compressor,
+		callTimeout:      options.callTimeout,
+		transferTimeout:  options.transferTimeout,
 	}, nil
 }
 
@@ -45,27 +198,34 @@ func (c *FileTransferClient) Close() {
 	}
 }
 
-// GetFileList retrieves the list of files from the gRPC server.
-func (c *FileTransferClient) GetFileList() ([]string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Microsecond)
+// GetFileList retrieves the entries below a specific directory from the gRPC server, an empty dir being the root.
+// A recursive listing descends up to maxDepth directory levels, a maxDepth of 0 lists the whole subtree.
+func (c *FileTransferClient) GetFileList(ctx context.Context, dir string, recursive bool, maxDepth uint32) ([]*api.FileEntry, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
 	defer cancel()
 
-	req := &api.FileListRequest{}
+	req := &api.FileListRequest{
+		Path:      dir,
+		Recursive: recursive,
+		MaxDepth:  maxDepth,
+	}
 	resp, err := c.client.GetFileList(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	return resp.Files, nil
+	return resp.Entries, nil
 }
 
 // GetFileInfo retrieves information about a specific file from the gRPC server.
-func (c *FileTransferClient) GetFileInfo(filename string) (*api.FileInfoResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// If hash is set, the server also computes the SHA-256 hash of the file content.
+func (c *FileTransferClient) GetFileInfo(ctx context.Context, filename string, hash bool) (*api.FileInfoResponse, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
 	defer cancel()
 
 	req := &api.FileInfoRequest{
 		Filename: filename,
+		Hash:     hash,
 	}
 	resp, err := c.client.GetFileInfo(ctx, req)
 	if err != nil {
@@ -76,17 +236,284 @@ func (c *FileTransferClient) GetFileInfo(filename string) (*api.FileInfoResponse
 }
 
 // GetFileContent retrieves the content of a specific file from the gRPC server.
-func (c *FileTransferClient) GetFileContent(filename string) (*api.FileContentResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (c *FileTransferClient) GetFileContent(ctx context.Context, filename string) (*api.FileContentResponse, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
 	defer cancel()
 
 	req := &api.FileInfoRequest{
 		Filename: filename,
 	}
-	resp, err := c.client.GetFileContent(ctx, req)
+	resp, err := c.client.GetFileContent(ctx, req, c.compressionOptions()...)
 	if err != nil {
 		return nil, err
 	}
 
 	return resp, nil
 }
+
+// DownloadFile streams the content of a specific file from the gRPC server into the provided writer.
+// It returns the number of bytes written.
+func (c *FileTransferClient) DownloadFile(ctx context.Context, filename string, w io.Writer) (int64, error) {
+	return c.DownloadFileRange(ctx, filename, 0, 0, w)
+}
+
+// DownloadFileRange streams the byte range of a specific file starting at offset from the gRPC server
+// into the provided writer. A length of zero downloads until the end of the file.
+// Unless verification is disabled, the received content is checked against the SHA-256 checksum sent
+// by the server and ErrChecksumMismatch is returned if they differ.
+// It returns the number of bytes written.
+func (c *FileTransferClient) DownloadFileRange(ctx context.Context, filename string, offset, length int64, w io.Writer) (int64, error) {
+	req := &api.DownloadFileRequest{
+		Filename: filename,
+		Offset:   offset,
+		Length:   length,
+	}
+	return c.download(ctx, req, w, sha256.New())
+}
+
+// download streams the content requested by req into the provided writer and adds it to hash,
+// which is verified against the checksum sent by the server. It returns the number of bytes written.
+func (c *FileTransferClient) download(ctx context.Context, req *api.DownloadFileRequest, w io.Writer, hash hash.Hash) (int64, error) {
+	ctx, cancel := withTimeout(ctx, c.transferTimeout)
+	defer cancel()
+
+	stream, err := c.client.DownloadFile(ctx, req, c.compressionOptions()...)
+	if err != nil {
+		return 0, err
+	}
+
+	var written int64
+	var checksum string
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return written, err
+		}
+		if err := ratelimit.WaitBytes(ctx, c.bandwidth, len(chunk.Content)); err != nil {
+			return written, err
+		}
+
+		n, err := w.Write(chunk.Content)
+		hash.Write(chunk.Content[:n])
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+
+		if chunk.Sha256 != "" {
+			checksum = chunk.Sha256
+		}
+	}
+
+	return written, c.verify(req.Filename, checksum, hash)
+}
+
+// verify checks the hash of the received content against the checksum sent by the server, unless verification is disabled.
+func (c *FileTransferClient) verify(filename, checksum string, hash hash.Hash) error {
+	if c.skipVerification {
+		return nil
+	}
+	if checksum == "" {
+		return ErrChecksumMissing
+	}
+	if received := hex.EncodeToString(hash.Sum(nil)); received != checksum {
+		return fmt.Errorf("%w for %s: server sent %s, received content hashes to %s", ErrChecksumMismatch, filename, checksum, received)
+	}
+	return nil
+}
+
+// UploadFile streams the content read from the provided reader to the gRPC server,
+// storing it as the specified file.
+func (c *FileTransferClient) UploadFile(ctx context.Context, filename string, r io.Reader) (*api.UploadFileResponse, error) {
+	ctx, cancel := withTimeout(ctx, c.transferTimeout)
+	defer cancel()
+
+	// The first chunk is read before the stream is opened, so already compressed content can be sent uncompressed
+	buf := make([]byte, uploadChunkSize)
+	n, readErr := io.ReadFull(r, buf)
+	if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+		return nil, readErr
+	}
+	var callOptions []grpc.CallOption
+	if !compression.IsCompressed(filename, buf[:n]) {
+		callOptions = c.compressionOptions()
+	}
+
+	stream, err := c.client.UploadFile(ctx, callOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the file metadata first
+	metadata := &api.UploadFileRequest{
+		Payload: &api.UploadFileRequest_Metadata{Metadata: &api.UploadFileMetadata{Filename: filename}},
+	}
+	if err := stream.Send(metadata); err != nil {
+		return nil, err
+	}
+
+	// Send the content in chunks
+	for {
+		if n > 0 {
+			if err := ratelimit.WaitBytes(ctx, c.bandwidth, n); err != nil {
+				return nil, err
+			}
+			chunk := &api.UploadFileRequest{
+				Payload: &api.UploadFileRequest_Chunk{Chunk: buf[:n]},
+			}
+			if err := stream.Send(chunk); err != nil {
+				// The server closed the stream, its status is returned by CloseAndRecv
+				if err == io.EOF {
+					break
+				}
+				return nil, err
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+		n, readErr = io.ReadFull(r, buf)
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// compressionOptions returns the call options requesting compression of file content transfers.
+func (c *FileTransferClient) compressionOptions() []grpc.CallOption {
+	if c.compressor == "" || c.compressor == compression.None {
+		return nil
+	}
+	return []grpc.CallOption{grpc.UseCompressor(c.compressor)}
+}
+
+// ResumeDownload downloads a specific file from the gRPC server into a local file, continuing
+// after the content that is already present in the local file. The local file is created if it does not exist.
+// Unless verification is disabled, the whole resulting file, including the content that was already present,
+// is checked against the SHA-256 hash the server computes from the start of the file while it sends the rest,
+// so a stale or corrupted local file is not reported as resumed. If a checksum does not match, the local file is
+// truncated back to its previous size and ErrChecksumMismatch is returned. ErrLocalFileLarger is returned if the
+// local file is larger than the file on the server. It returns the number of bytes written by this call.
+func (c *FileTransferClient) ResumeDownload(ctx context.Context, filename, localPath string) (int64, error) {
+	file, err := os.OpenFile(localPath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	// The size of the local file is the offset to resume from
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	// Hash the content already present, the server hashes the same content of its file before sending the rest
+	hash := sha256.New()
+	if !c.skipVerification {
+		if _, err := io.Copy(hash, io.LimitReader(file, fileInfo.Size())); err != nil {
+			return 0, err
+		}
+	}
+
+	req := &api.DownloadFileRequest{
+		Filename:      filename,
+		Offset:        fileInfo.Size(),
+		HashFromStart: !c.skipVerification,
+	}
+	written, err := c.download(ctx, req, file, hash)
+	if status.Code(err) == codes.OutOfRange {
+		return 0, fmt.Errorf("%w: %s has %d bytes, more than %s on the server", ErrLocalFileLarger, localPath, fileInfo.Size(), filename)
+	}
+	if errors.Is(err, ErrChecksumMismatch) {
+		if truncateErr := file.Truncate(fileInfo.Size()); truncateErr != nil {
+			return written, fmt.Errorf("%w (truncating %s: %v)", err, localPath, truncateErr)
+		}
+		return 0, err
+	}
+	if err != nil {
+		return written, err
+	}
+
+	return written, file.Close()
+}
+
+// DeleteFile deletes a specific file or directory on the gRPC server.
+// Directories with content are only deleted if recursive is set.
+func (c *FileTransferClient) DeleteFile(ctx context.Context, filename string, recursive bool) error {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+
+	req := &api.DeleteFileRequest{
+		Filename:  filename,
+		Recursive: recursive,
+	}
+	_, err := c.client.DeleteFile(ctx, req)
+	return err
+}
+
+// RenameFile renames or moves a file or directory on the gRPC server.
+// An existing destination is only replaced if overwrite is set.
+func (c *FileTransferClient) RenameFile(ctx context.Context, source, destination string, overwrite bool) error {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+
+	req := &api.RenameFileRequest{
+		Source:      source,
+		Destination: destination,
+		Overwrite:   overwrite,
+	}
+	_, err := c.client.RenameFile(ctx, req)
+	return err
+}
+
+// CopyFile copies a file on the gRPC server without transferring its content to the client.
+// An existing destination is only replaced if overwrite is set.
+func (c *FileTransferClient) CopyFile(ctx context.Context, source, destination string, overwrite bool) (*api.CopyFileResponse, error) {
+	// Copying large files takes a while, so the request is limited by the transfer timeout
+	ctx, cancel := withTimeout(ctx, c.transferTimeout)
+	defer cancel()
+
+	req := &api.CopyFileRequest{
+		Source:      source,
+		Destination: destination,
+		Overwrite:   overwrite,
+	}
+	resp, err := c.client.CopyFile(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// MakeDirectory creates a directory on the gRPC server.
+// If parents is set, missing parent directories are created as well and an existing directory is not an error.
+func (c *FileTransferClient) MakeDirectory(ctx context.Context, path string, parents bool) error {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+
+	req := &api.MakeDirectoryRequest{
+		Path:    path,
+		Parents: parents,
+	}
+	_, err := c.client.MakeDirectory(ctx, req)
+	return err
+}
+
+// withTimeout returns a context derived from ctx that is canceled after timeout, or only with ctx if timeout is zero.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -4,15 +4,17 @@ import (
 	"context"
 	"filetransfer/internal/logger"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
-// ClientLoggingInterceptor returns a gRPC unary client interceptor that logs the duration
-// of each gRPC method call and any errors that occur.
-func ClientLoggingInterceptor(logger logger.ClientLogger) grpc.UnaryClientInterceptor {
+// ClientLoggingInterceptor returns a gRPC unary client interceptor that sends a request ID to the server
+// and logs the method, request ID, duration and status code of each gRPC method call.
+func ClientLoggingInterceptor(log logger.ClientLogger) grpc.UnaryClientInterceptor {
 	return func(
 		ctx context.Context,
 		method string,
@@ -22,23 +24,86 @@ func ClientLoggingInterceptor(logger logger.ClientLogger) grpc.UnaryClientInterc
 		opts ...grpc.CallOption,
 	) error {
 		startTime := time.Now()
+		ctx = requestContext(ctx)
 
 		// Invoke the gRPC method
 		err := invoker(ctx, method, req, reply, cc, opts...)
 
 		duration := time.Since(startTime)
-		logger.Printf("gRPC method %s took %s\n", method, duration)
+		attrs := []any{"method", method, "duration", duration, "code", status.Code(err).String()}
 
 		// Log any errors that occurred during the gRPC method call
 		if err != nil {
 			statusErr, ok := status.FromError(err)
 			if ok {
-				loggedError := fmt.Errorf("gRPC method %s failed: %s", method, statusErr.Message())
-				logger.Printf(loggedError.Error())
-				return loggedError
+				log.Log(ctx, slog.LevelError, "gRPC call failed", append(attrs, "error", statusErr.Message())...)
+				return &callError{message: fmt.Sprintf("gRPC method %s failed: %s", method, statusErr.Message()), status: statusErr}
 			}
 		}
 
+		log.Log(ctx, slog.LevelInfo, "gRPC call finished", attrs...)
 		return err
 	}
 }
+
+// ClientStreamLoggingInterceptor returns a gRPC stream client interceptor that sends a request ID to the server
+// and logs the time needed to establish each gRPC stream and any errors that occur while establishing it.
+func ClientStreamLoggingInterceptor(log logger.ClientLogger) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		startTime := time.Now()
+		ctx = requestContext(ctx)
+
+		// Establish the gRPC stream
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+
+		duration := time.Since(startTime)
+		attrs := []any{"method", method, "duration", duration}
+
+		// Log any errors that occurred while establishing the gRPC stream
+		if err != nil {
+			statusErr, ok := status.FromError(err)
+			if ok {
+				log.Log(ctx, slog.LevelError, "gRPC stream failed", append(attrs, "code", statusErr.Code().String(), "error", statusErr.Message())...)
+				return nil, &callError{message: fmt.Sprintf("gRPC stream %s failed: %s", method, statusErr.Message()), status: statusErr}
+			}
+		}
+
+		log.Log(ctx, slog.LevelInfo, "gRPC stream opened", attrs...)
+		return stream, err
+	}
+}
+
+// requestContext returns a copy of ctx carrying a request ID, which is sent to the server in the outgoing metadata.
+// A request ID already carried by ctx is kept, so related calls can share an ID.
+func requestContext(ctx context.Context) context.Context {
+	id := logger.RequestID(ctx)
+	if id == "" {
+		id = logger.NewRequestID()
+		ctx = logger.ContextWithRequestID(ctx, id)
+	}
+	return metadata.AppendToOutgoingContext(ctx, logger.RequestIDMetadataKey, id)
+}
+
+// callError is the error of a failed call. It keeps the status of the call, so callers can tell
+// failures apart with status.Code.
+type callError struct {
+	message string
+	status  *status.Status
+}
+
+// Error returns the message of the error.
+func (e *callError) Error() string {
+	return e.message
+}
+
+// GRPCStatus returns the status of the failed call.
+func (e *callError) GRPCStatus() *status.Status {
+	return e.status
+}
@@ -0,0 +1,60 @@
+package client_interceptor
+
+import (
+	"context"
+	"filetransfer/internal/logger"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestClientLoggingInterceptor_SendsRequestID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	interceptor := ClientLoggingInterceptor(mockLogger)
+
+	var sentID string
+	mockLogger.EXPECT().Log(gomock.Any(), slog.LevelInfo, "gRPC call finished", gomock.Any()).Do(func(ctx context.Context, level slog.Level, msg string, args ...any) {
+		// The logged request ID is the one sent to the server
+		assert.Equal(t, sentID, logger.RequestID(ctx))
+	})
+
+	err := interceptor(context.Background(), "/api.FileTransfer/GetFileInfo", nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		values := md.Get(logger.RequestIDMetadataKey)
+		assert.Len(t, values, 1)
+		sentID = values[0]
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, sentID)
+}
+
+func TestClientLoggingInterceptor_KeepsRequestID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	interceptor := ClientLoggingInterceptor(mockLogger)
+
+	mockLogger.EXPECT().Log(gomock.Any(), slog.LevelError, "gRPC call failed", gomock.Any())
+
+	ctx := logger.ContextWithRequestID(context.Background(), "abc123")
+	err := interceptor(ctx, "/api.FileTransfer/GetFileInfo", nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		assert.Equal(t, []string{"abc123"}, md.Get(logger.RequestIDMetadataKey))
+		return status.Error(codes.NotFound, "no such file")
+	})
+
+	assert.EqualError(t, err, "gRPC method /api.FileTransfer/GetFileInfo failed: no such file")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -1,12 +1,22 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"filetransfer/api"
+	"filetransfer/internal/compression"
 	"filetransfer/internal/logger"
+	"filetransfer/internal/ratelimit"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestFileTransferClient_GetFileList(t *testing.T) {
@@ -21,13 +31,17 @@ func TestFileTransferClient_GetFileList(t *testing.T) {
 		logger: mockLogger,
 	}
 
-	mockLogger.EXPECT().Printf(gomock.Any(), gomock.Any()).AnyTimes()
-	mockClient.EXPECT().GetFileList(gomock.Any(), gomock.Any()).Return(&api.FileListResponse{Files: []string{"file1.txt", "file2.txt"}}, nil)
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	entries := []*api.FileEntry{
+		{Name: "file1.txt", Path: "dir/file1.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 10},
+		{Name: "sub", Path: "dir/sub", Type: api.EntryType_ENTRY_TYPE_DIRECTORY},
+	}
+	mockClient.EXPECT().GetFileList(gomock.Any(), &api.FileListRequest{Path: "dir", Recursive: true, MaxDepth: 2}).Return(&api.FileListResponse{Entries: entries}, nil)
 
-	files, err := client.GetFileList()
+	files, err := client.GetFileList(context.Background(), "dir", true, 2)
 
 	assert.NoError(t, err)
-	assert.Equal(t, []string{"file1.txt", "file2.txt"}, files)
+	assert.Equal(t, entries, files)
 }
 
 func TestFileTransferClient_GetFileInfo(t *testing.T) {
@@ -42,10 +56,10 @@ func TestFileTransferClient_GetFileInfo(t *testing.T) {
 		logger: mockLogger,
 	}
 
-	mockLogger.EXPECT().Printf(gomock.Any(), gomock.Any()).AnyTimes()
-	mockClient.EXPECT().GetFileInfo(gomock.Any(), gomock.Any()).Return(&api.FileInfoResponse{Filename: "file1.txt", Size: 100}, nil)
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockClient.EXPECT().GetFileInfo(gomock.Any(), &api.FileInfoRequest{Filename: "file1.txt", Hash: true}).Return(&api.FileInfoResponse{Filename: "file1.txt", Size: 100}, nil)
 
-	fileInfo, err := client.GetFileInfo("file1.txt")
+	fileInfo, err := client.GetFileInfo(context.Background(), "file1.txt", true)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, fileInfo)
@@ -65,10 +79,10 @@ func TestFileTransferClient_GetFileContent(t *testing.T) {
 		logger: mockLogger,
 	}
 
-	mockLogger.EXPECT().Printf(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 	mockClient.EXPECT().GetFileContent(gomock.Any(), gomock.Any()).Return(&api.FileContentResponse{Filename: "file1.txt", Content: []byte("file content")}, nil)
 
-	fileContent, err := client.GetFileContent("file1.txt")
+	fileContent, err := client.GetFileContent(context.Background(), "file1.txt")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, fileContent)
@@ -88,11 +102,357 @@ func TestFileTransferClient_GetFileList_Error(t *testing.T) {
 		logger: mockLogger,
 	}
 
-	mockLogger.EXPECT().Printf(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 	mockClient.EXPECT().GetFileList(gomock.Any(), gomock.Any()).Return(nil, errors.New("mock error"))
 
-	files, err := client.GetFileList()
+	files, err := client.GetFileList(context.Background(), "", false, 0)
 
 	assert.Error(t, err)
 	assert.Nil(t, files)
 }
+
+func TestFileTransferClient_DownloadFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileClient(ctrl)
+
+	client := &FileTransferClient{
+		client: mockClient,
+		logger: mockLogger,
+	}
+
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockClient.EXPECT().DownloadFile(gomock.Any(), gomock.Any()).Return(mockStream, nil)
+	gomock.InOrder(
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Content: []byte("file ")}, nil),
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Content: []byte("content")}, nil),
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Sha256: "e0ac3601005dfa1864f5392aabaf7d898b1b5bab854f1acb4491bcd806b76b0c"}, nil),
+		mockStream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	var buf bytes.Buffer
+	written, err := client.DownloadFile(context.Background(), "file1.txt", &buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), written)
+	assert.Equal(t, "file content", buf.String())
+}
+
+func TestFileTransferClient_DownloadFile_ChecksumMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileClient(ctrl)
+
+	client := &FileTransferClient{
+		client: mockClient,
+		logger: mockLogger,
+	}
+
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockClient.EXPECT().DownloadFile(gomock.Any(), gomock.Any()).Return(mockStream, nil).Times(2)
+	gomock.InOrder(
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Content: []byte("corrupted")}, nil),
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Sha256: "e0ac3601005dfa1864f5392aabaf7d898b1b5bab854f1acb4491bcd806b76b0c"}, nil),
+		mockStream.EXPECT().Recv().Return(nil, io.EOF),
+		// A server that does not send a checksum
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Content: []byte("file content")}, nil),
+		mockStream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	_, err := client.DownloadFile(context.Background(), "file1.txt", io.Discard)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+	_, err = client.DownloadFile(context.Background(), "file1.txt", io.Discard)
+	assert.ErrorIs(t, err, ErrChecksumMissing)
+}
+
+func TestFileTransferClient_DownloadFile_WithoutVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileClient(ctrl)
+
+	client := &FileTransferClient{
+		client:           mockClient,
+		logger:           mockLogger,
+		skipVerification: true,
+	}
+
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockClient.EXPECT().DownloadFile(gomock.Any(), gomock.Any()).Return(mockStream, nil)
+	gomock.InOrder(
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Content: []byte("corrupted")}, nil),
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Sha256: "e0ac3601005dfa1864f5392aabaf7d898b1b5bab854f1acb4491bcd806b76b0c"}, nil),
+		mockStream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	var buf bytes.Buffer
+	written, err := client.DownloadFile(context.Background(), "file1.txt", &buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), written)
+	assert.Equal(t, "corrupted", buf.String())
+}
+
+func TestFileTransferClient_ResumeDownload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileClient(ctrl)
+
+	client := &FileTransferClient{
+		client: mockClient,
+		logger: mockLogger,
+	}
+
+	localPath := filepath.Join(t.TempDir(), "file1.txt")
+	err := os.WriteFile(localPath, []byte("file "), 0644)
+	assert.NoError(t, err)
+
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockClient.EXPECT().DownloadFile(gomock.Any(), &api.DownloadFileRequest{Filename: "file1.txt", Offset: 5, HashFromStart: true}).Return(mockStream, nil)
+	gomock.InOrder(
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Content: []byte("content")}, nil),
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Sha256: "e0ac3601005dfa1864f5392aabaf7d898b1b5bab854f1acb4491bcd806b76b0c"}, nil),
+		mockStream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	written, err := client.ResumeDownload(context.Background(), "file1.txt", localPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), written)
+
+	content, err := os.ReadFile(localPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("file content"), content)
+}
+
+func TestFileTransferClient_ResumeDownload_ChecksumMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileClient(ctrl)
+
+	client := &FileTransferClient{
+		client: mockClient,
+		logger: mockLogger,
+	}
+
+	localPath := filepath.Join(t.TempDir(), "file1.txt")
+	err := os.WriteFile(localPath, []byte("file "), 0644)
+	assert.NoError(t, err)
+
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockClient.EXPECT().DownloadFile(gomock.Any(), &api.DownloadFileRequest{Filename: "file1.txt", Offset: 5, HashFromStart: true}).Return(mockStream, nil)
+	gomock.InOrder(
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Content: []byte("other")}, nil),
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Sha256: "e0ac3601005dfa1864f5392aabaf7d898b1b5bab854f1acb4491bcd806b76b0c"}, nil),
+		mockStream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	written, err := client.ResumeDownload(context.Background(), "file1.txt", localPath)
+
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.Equal(t, int64(0), written)
+
+	// The corrupted content is removed again
+	content, err := os.ReadFile(localPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("file "), content)
+}
+
+func TestFileTransferClient_ResumeDownload_StalePrefix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileClient(ctrl)
+
+	client := &FileTransferClient{
+		client: mockClient,
+		logger: mockLogger,
+	}
+
+	// The local content differs from the start of the file on the server
+	localPath := filepath.Join(t.TempDir(), "file1.txt")
+	err := os.WriteFile(localPath, []byte("fiLe "), 0644)
+	assert.NoError(t, err)
+
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockClient.EXPECT().DownloadFile(gomock.Any(), &api.DownloadFileRequest{Filename: "file1.txt", Offset: 5, HashFromStart: true}).Return(mockStream, nil)
+	gomock.InOrder(
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Content: []byte("content")}, nil),
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Sha256: "e0ac3601005dfa1864f5392aabaf7d898b1b5bab854f1acb4491bcd806b76b0c"}, nil),
+		mockStream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	// The resumed range is intact, but the whole file is not
+	written, err := client.ResumeDownload(context.Background(), "file1.txt", localPath)
+
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.Equal(t, int64(0), written)
+
+	content, err := os.ReadFile(localPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("fiLe "), content)
+}
+
+func TestFileTransferClient_ResumeDownload_LocalFileLarger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileClient(ctrl)
+
+	client := &FileTransferClient{
+		client: mockClient,
+		logger: mockLogger,
+	}
+
+	localPath := filepath.Join(t.TempDir(), "file1.txt")
+	err := os.WriteFile(localPath, []byte("file content and more"), 0644)
+	assert.NoError(t, err)
+
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockClient.EXPECT().DownloadFile(gomock.Any(), &api.DownloadFileRequest{Filename: "file1.txt", Offset: 21, HashFromStart: true}).Return(mockStream, nil)
+	mockStream.EXPECT().Recv().Return(nil, status.Error(codes.OutOfRange, "requested range is outside of the file"))
+
+	written, err := client.ResumeDownload(context.Background(), "file1.txt", localPath)
+
+	assert.ErrorIs(t, err, ErrLocalFileLarger)
+	assert.Equal(t, int64(0), written)
+
+	content, err := os.ReadFile(localPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("file content and more"), content)
+}
+
+func TestFileTransferClient_UploadFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockStream := api.NewMockFileTransfer_UploadFileClient(ctrl)
+
+	client := &FileTransferClient{
+		client: mockClient,
+		logger: mockLogger,
+	}
+
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockClient.EXPECT().UploadFile(gomock.Any()).Return(mockStream, nil)
+	gomock.InOrder(
+		mockStream.EXPECT().Send(&api.UploadFileRequest{
+			Payload: &api.UploadFileRequest_Metadata{Metadata: &api.UploadFileMetadata{Filename: "file1.txt"}},
+		}).Return(nil),
+		mockStream.EXPECT().Send(&api.UploadFileRequest{
+			Payload: &api.UploadFileRequest_Chunk{Chunk: []byte("file content")},
+		}).Return(nil),
+		mockStream.EXPECT().CloseAndRecv().Return(&api.UploadFileResponse{Filename: "file1.txt", Size: 12}, nil),
+	)
+
+	resp, err := client.UploadFile(context.Background(), "file1.txt", bytes.NewReader([]byte("file content")))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "file1.txt", resp.Filename)
+	assert.Equal(t, uint64(12), resp.Size)
+}
+
+func TestFileTransferClient_UploadFile_RateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockStream := api.NewMockFileTransfer_UploadFileClient(ctrl)
+
+	client := &FileTransferClient{
+		client:    mockClient,
+		logger:    mockLogger,
+		bandwidth: ratelimit.NewBandwidthLimiter(uploadChunkSize),
+	}
+
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockClient.EXPECT().UploadFile(gomock.Any()).Return(mockStream, nil)
+	mockStream.EXPECT().Send(gomock.Any()).Return(nil).Times(3)
+	mockStream.EXPECT().CloseAndRecv().Return(&api.UploadFileResponse{Filename: "file1.txt", Size: 3 * uploadChunkSize / 2}, nil)
+
+	// The first chunk is within the burst of one second, the next half chunk takes half a second
+	start := time.Now()
+	_, err := client.UploadFile(context.Background(), "file1.txt", bytes.NewReader(make([]byte, 3*uploadChunkSize/2)))
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+}
+
+func TestFileTransferClient_UploadFile_Compression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockStream := api.NewMockFileTransfer_UploadFileClient(ctrl)
+
+	client := &FileTransferClient{
+		client:     mockClient,
+		logger:     mockLogger,
+		compressor: compression.Zstd,
+	}
+
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockStream.EXPECT().Send(gomock.Any()).Return(nil).AnyTimes()
+	mockStream.EXPECT().CloseAndRecv().Return(&api.UploadFileResponse{}, nil).Times(2)
+
+	// Text is compressed, the call carries the compressor option
+	mockClient.EXPECT().UploadFile(gomock.Any(), gomock.Any()).Return(mockStream, nil)
+	_, err := client.UploadFile(context.Background(), "server.log", bytes.NewReader([]byte("level=info msg=started")))
+	assert.NoError(t, err)
+
+	// Already compressed content is not
+	mockClient.EXPECT().UploadFile(gomock.Any()).Return(mockStream, nil)
+	_, err = client.UploadFile(context.Background(), "photo.jpg", bytes.NewReader([]byte("\xff\xd8\xff")))
+	assert.NoError(t, err)
+}
+
+func TestFileTransferClient_FileManagement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+
+	client := &FileTransferClient{
+		client: mockClient,
+		logger: mockLogger,
+	}
+
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockClient.EXPECT().DeleteFile(gomock.Any(), &api.DeleteFileRequest{Filename: "dir", Recursive: true}).Return(&api.DeleteFileResponse{}, nil)
+	mockClient.EXPECT().RenameFile(gomock.Any(), &api.RenameFileRequest{Source: "a.txt", Destination: "b.txt"}).Return(nil, errors.New("mock error"))
+	mockClient.EXPECT().CopyFile(gomock.Any(), &api.CopyFileRequest{Source: "a.txt", Destination: "b.txt", Overwrite: true}).Return(&api.CopyFileResponse{Size: 7}, nil)
+	mockClient.EXPECT().MakeDirectory(gomock.Any(), &api.MakeDirectoryRequest{Path: "a/b", Parents: true}).Return(&api.MakeDirectoryResponse{}, nil)
+
+	assert.NoError(t, client.DeleteFile(context.Background(), "dir", true))
+	assert.Error(t, client.RenameFile(context.Background(), "a.txt", "b.txt", false))
+
+	resp, err := client.CopyFile(context.Background(), "a.txt", "b.txt", true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), resp.Size)
+
+	assert.NoError(t, client.MakeDirectory(context.Background(), "a/b", true))
+}
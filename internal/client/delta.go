@@ -0,0 +1,227 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"filetransfer/api"
+	"filetransfer/internal/delta"
+	"filetransfer/internal/ratelimit"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// MinDeltaSize is the smallest size of local and remote files downloaded as a delta. Smaller files are
+// downloaded completely, since the signatures and the extra round trip cost about as much as the file.
+const MinDeltaSize = 64 * 1024
+
+// signatureBatchSize is the maximum number of block signatures sent in a single message.
+const signatureBatchSize = 4096
+
+// DeltaReport describes a download by DeltaDownload.
+type DeltaReport struct {
+	// Size is the size of the downloaded file.
+	Size int64
+	// Delta is false if the file was downloaded completely instead of as a delta.
+	Delta bool
+	// ReusedBytes is the number of bytes copied from the local file, LiteralBytes the number of bytes downloaded.
+	ReusedBytes  int64
+	LiteralBytes int64
+	// SignatureBytes is the size of the block signatures of the local file sent to the server.
+	SignatureBytes int64
+}
+
+// BytesSaved returns how many bytes the delta download transferred less than a complete download.
+// It is negative if the signatures were larger than the content reused from the local file.
+func (r *DeltaReport) BytesSaved() int64 {
+	if !r.Delta {
+		return 0
+	}
+	return r.ReusedBytes - r.SignatureBytes
+}
+
+// Unprofitable reports whether a delta download transferred at least as many bytes as a complete download, because
+// the local file shares too little content with the remote file to outweigh its signatures. Such files are better
+// downloaded completely. It is false for complete downloads.
+func (r *DeltaReport) Unprofitable() bool {
+	return r.Delta && r.BytesSaved() <= 0
+}
+
+// DeltaDownload updates a local file to the content of a specific file on the gRPC server, downloading only
+// the parts of the file that are not found in the local file: the signatures of the blocks of the local file are
+// sent to the server, which answers with the blocks to copy from the local file and the remaining content.
+// Missing and small files are downloaded completely, as are files whose delta fails verification, since the local
+// file may have changed in the meantime. The new content is written to a temporary file that replaces the local
+// file once it is complete, so an interrupted download leaves the local file untouched.
+func (c *FileTransferClient) DeltaDownload(ctx context.Context, filename, localPath string) (*DeltaReport, error) {
+	localInfo, err := os.Stat(localPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c.fullDownload(ctx, filename, localPath, 0644)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !localInfo.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", localPath)
+	}
+	mode := localInfo.Mode().Perm()
+	if localInfo.Size() < MinDeltaSize {
+		return c.fullDownload(ctx, filename, localPath, mode)
+	}
+
+	info, err := c.GetFileInfo(ctx, filename, false)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size < MinDeltaSize {
+		return c.fullDownload(ctx, filename, localPath, mode)
+	}
+
+	report := &DeltaReport{Delta: true}
+	err = replaceFile(localPath, mode, func(w io.Writer) error {
+		return c.deltaDownload(ctx, filename, localPath, localInfo.Size(), w, report)
+	})
+	if errors.Is(err, ErrChecksumMismatch) {
+		return c.fullDownload(ctx, filename, localPath, mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// deltaDownload writes the content of the remote file to w, rebuilt from the first localSize bytes
+// of the local file and the delta sent by the server.
+func (c *FileTransferClient) deltaDownload(ctx context.Context, filename, localPath string, localSize int64, w io.Writer, report *DeltaReport) error {
+	ctx, cancel := withTimeout(ctx, c.transferTimeout)
+	defer cancel()
+
+	local, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer local.Close()
+
+	stream, err := c.client.DeltaDownload(ctx, c.compressionOptions()...)
+	if err != nil {
+		return err
+	}
+
+	// Send the metadata and the signatures of the local file, which the server needs before it sends the delta
+	blockSize := delta.BlockSize(localSize)
+	metadata := &api.DeltaDownloadRequest{
+		Payload: &api.DeltaDownloadRequest_Metadata{Metadata: &api.DeltaDownloadMetadata{
+			Filename:  filename,
+			BlockSize: uint32(blockSize),
+			Size:      uint64(localSize),
+		}},
+	}
+	if err := stream.Send(metadata); err != nil {
+		return err
+	}
+
+	batch := &api.BlockSignatures{}
+	sendBatch := func() error {
+		req := &api.DeltaDownloadRequest{Payload: &api.DeltaDownloadRequest_Signatures{Signatures: batch}}
+		report.SignatureBytes += int64(proto.Size(req))
+		batch = &api.BlockSignatures{}
+		return stream.Send(req)
+	}
+	err = delta.Sign(io.NewSectionReader(local, 0, localSize), blockSize, func(signature delta.Signature) error {
+		batch.Blocks = append(batch.Blocks, &api.BlockSignature{Weak: signature.Weak, Strong: signature.Strong[:]})
+		if len(batch.Blocks) == signatureBatchSize {
+			return sendBatch()
+		}
+		return nil
+	})
+	if err == nil && len(batch.Blocks) > 0 {
+		err = sendBatch()
+	}
+	// The server closed the stream, its status is returned by Recv
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	// Rebuild the file from the blocks of the local file and the literal content
+	hash := sha256.New()
+	patcher := delta.NewPatcher(local, localSize, blockSize, io.MultiWriter(w, hash))
+	var checksum string
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, instruction := range chunk.Instructions {
+			if blocks := instruction.GetCopy(); blocks != nil {
+				err = patcher.Apply(delta.Instruction{Index: blocks.Index, Count: blocks.Count})
+			} else {
+				literal := instruction.GetLiteral()
+				if err := ratelimit.WaitBytes(ctx, c.bandwidth, len(literal)); err != nil {
+					return err
+				}
+				err = patcher.Apply(delta.Instruction{Literal: literal})
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if chunk.Sha256 != "" {
+			checksum = chunk.Sha256
+		}
+	}
+
+	report.ReusedBytes = patcher.Reused
+	report.LiteralBytes = patcher.Literal
+	report.Size = patcher.Reused + patcher.Literal
+	return c.verify(filename, checksum, hash)
+}
+
+// fullDownload replaces the local file with the complete content of the remote file.
+func (c *FileTransferClient) fullDownload(ctx context.Context, filename, localPath string, mode fs.FileMode) (*DeltaReport, error) {
+	report := &DeltaReport{}
+	err := replaceFile(localPath, mode, func(w io.Writer) error {
+		n, err := c.DownloadFile(ctx, filename, w)
+		report.Size = n
+		report.LiteralBytes = n
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// replaceFile writes a temporary file next to the local file with write and renames it to the local file
+// with the given permissions once write succeeded.
+func replaceFile(localPath string, mode fs.FileMode, write func(io.Writer) error) error {
+	file, err := os.CreateTemp(filepath.Dir(localPath), "."+filepath.Base(localPath)+".delta-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), localPath)
+}
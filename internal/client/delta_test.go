@@ -0,0 +1,155 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"filetransfer/api"
+	"filetransfer/internal/logger"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newDeltaTestClient returns a client whose server holds a file of the given content,
+// together with the mock of the generated client.
+func newDeltaTestClient(t *testing.T, content []byte) (*FileTransferClient, *api.MockFileTransferClient) {
+	ctrl := gomock.NewController(t)
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockClient.EXPECT().GetFileInfo(gomock.Any(), gomock.Any()).Return(&api.FileInfoResponse{Filename: "file.bin", Size: uint64(len(content))}, nil).AnyTimes()
+	return &FileTransferClient{client: mockClient, logger: mockLogger}, mockClient
+}
+
+// expectDownload expects a complete download of the content.
+func expectDownload(ctrl *gomock.Controller, mockClient *api.MockFileTransferClient, content []byte) {
+	stream := api.NewMockFileTransfer_DownloadFileClient(ctrl)
+	gomock.InOrder(
+		stream.EXPECT().Recv().Return(&api.FileChunk{Content: content}, nil),
+		stream.EXPECT().Recv().Return(&api.FileChunk{Sha256: sha256Hex(string(content))}, nil),
+		stream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+	mockClient.EXPECT().DownloadFile(gomock.Any(), &api.DownloadFileRequest{Filename: "file.bin"}).Return(stream, nil)
+}
+
+func TestFileTransferClient_DeltaDownload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	oldContent := make([]byte, 100000)
+	rand.New(rand.NewSource(1)).Read(oldContent)
+	newContent := append(append([]byte(nil), oldContent[:98304]...), []byte("changed end")...)
+	localPath := filepath.Join(t.TempDir(), "file.bin")
+	require.NoError(t, os.WriteFile(localPath, oldContent, 0600))
+
+	client, mockClient := newDeltaTestClient(t, newContent)
+	stream := api.NewMockFileTransfer_DeltaDownloadClient(ctrl)
+	mockClient.EXPECT().DeltaDownload(gomock.Any()).Return(stream, nil)
+
+	var sent []*api.DeltaDownloadRequest
+	stream.EXPECT().Send(gomock.Any()).DoAndReturn(func(req *api.DeltaDownloadRequest) error {
+		sent = append(sent, req)
+		return nil
+	}).AnyTimes()
+	stream.EXPECT().CloseSend().Return(nil)
+	gomock.InOrder(
+		// The 2 KiB blocks of the unchanged start and the changed end
+		stream.EXPECT().Recv().Return(&api.DeltaChunk{Instructions: []*api.DeltaInstruction{
+			{Operation: &api.DeltaInstruction_Copy{Copy: &api.BlockRange{Index: 0, Count: 48}}},
+			{Operation: &api.DeltaInstruction_Literal{Literal: []byte("changed end")}},
+		}}, nil),
+		stream.EXPECT().Recv().Return(&api.DeltaChunk{Sha256: sha256Hex(string(newContent))}, nil),
+		stream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	report, err := client.DeltaDownload(context.Background(), "file.bin", localPath)
+	require.NoError(t, err)
+
+	// The metadata is followed by the signatures of all blocks
+	require.Len(t, sent, 2)
+	assert.Equal(t, &api.DeltaDownloadMetadata{Filename: "file.bin", BlockSize: 2048, Size: 100000}, sent[0].GetMetadata())
+	assert.Len(t, sent[1].GetSignatures().GetBlocks(), 49)
+
+	content, err := os.ReadFile(localPath)
+	require.NoError(t, err)
+	assert.Equal(t, newContent, content)
+	info, err := os.Stat(localPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	assert.True(t, report.Delta)
+	assert.Equal(t, int64(len(newContent)), report.Size)
+	assert.Equal(t, int64(98304), report.ReusedBytes)
+	assert.Equal(t, int64(11), report.LiteralBytes)
+	assert.Greater(t, report.SignatureBytes, int64(49*20))
+	assert.Equal(t, report.ReusedBytes-report.SignatureBytes, report.BytesSaved())
+}
+
+func TestFileTransferClient_DeltaDownload_Fallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	content := bytes.Repeat([]byte("new content "), 10000)
+	dir := t.TempDir()
+
+	// Missing and small local files are downloaded completely
+	client, mockClient := newDeltaTestClient(t, content)
+	expectDownload(ctrl, mockClient, content)
+	expectDownload(ctrl, mockClient, content)
+
+	missingPath := filepath.Join(dir, "missing.bin")
+	report, err := client.DeltaDownload(context.Background(), "file.bin", missingPath)
+	require.NoError(t, err)
+	assert.Equal(t, &DeltaReport{Size: int64(len(content)), LiteralBytes: int64(len(content))}, report)
+	assert.Zero(t, report.BytesSaved())
+	assert.False(t, report.Unprofitable())
+
+	smallPath := filepath.Join(dir, "small.bin")
+	require.NoError(t, os.WriteFile(smallPath, []byte("old content"), 0644))
+	report, err = client.DeltaDownload(context.Background(), "file.bin", smallPath)
+	require.NoError(t, err)
+	assert.False(t, report.Delta)
+
+	for _, localPath := range []string{missingPath, smallPath} {
+		downloaded, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Equal(t, content, downloaded)
+	}
+}
+
+func TestFileTransferClient_DeltaDownload_ChecksumMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	content := bytes.Repeat([]byte("new content "), 10000)
+	localPath := filepath.Join(t.TempDir(), "file.bin")
+	require.NoError(t, os.WriteFile(localPath, bytes.Repeat([]byte("old content "), 10000), 0644))
+
+	client, mockClient := newDeltaTestClient(t, content)
+	stream := api.NewMockFileTransfer_DeltaDownloadClient(ctrl)
+	mockClient.EXPECT().DeltaDownload(gomock.Any()).Return(stream, nil)
+	stream.EXPECT().Send(gomock.Any()).Return(nil).AnyTimes()
+	stream.EXPECT().CloseSend().Return(nil)
+	gomock.InOrder(
+		stream.EXPECT().Recv().Return(&api.DeltaChunk{
+			Instructions: []*api.DeltaInstruction{{Operation: &api.DeltaInstruction_Copy{Copy: &api.BlockRange{Index: 0, Count: 10}}}},
+			Sha256:       sha256Hex(string(content)),
+		}, nil),
+		stream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	// The delta does not rebuild the remote file, which is downloaded completely instead
+	expectDownload(ctrl, mockClient, content)
+
+	report, err := client.DeltaDownload(context.Background(), "file.bin", localPath)
+	require.NoError(t, err)
+	assert.False(t, report.Delta)
+	downloaded, err := os.ReadFile(localPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(filepath.Dir(localPath))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
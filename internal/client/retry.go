@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"filetransfer/api"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxRetryAttempts is the largest number of attempts gRPC makes for a call, higher values are reduced to it.
+const MaxRetryAttempts = 5
+
+// DefaultCallTimeout is the default time a call without file content transfer may take, including its retries.
+const DefaultCallTimeout = 5 * time.Second
+
+// DefaultRetryPolicy is the retry policy used unless WithRetryPolicy is provided.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:       4,
+	InitialBackoff:    100 * time.Millisecond,
+	MaxBackoff:        2 * time.Second,
+	BackoffMultiplier: 2,
+}
+
+// retriedMethods contains the unary methods that only read from the server, so repeating them has no side effects.
+// They are retried by the retry interceptor, which limits every attempt on its own.
+var retriedMethods = map[string]bool{
+	api.FileTransfer_GetFileList_FullMethodName:    true,
+	api.FileTransfer_GetFileInfo_FullMethodName:    true,
+	api.FileTransfer_GetFileContent_FullMethodName: true,
+}
+
+// retriedStreamMethods contains the streaming methods that only read from the server.
+// gRPC retries them according to the service config until the server has sent the first content.
+var retriedStreamMethods = []string{
+	api.FileTransfer_DownloadFile_FullMethodName,
+}
+
+// RetryPolicy configures how idempotent reads are retried.
+// Reads without file content transfer are retried when an attempt fails with Unavailable or DeadlineExceeded,
+// the latter either sent by the server or caused by an attempt exceeding PerAttemptTimeout.
+// Downloads are retried when they fail with Unavailable before the server has sent the first content.
+// The delay before the nth retry is chosen at random between zero and
+// min(InitialBackoff * BackoffMultiplier^(n-1), MaxBackoff), so clients failing together do not retry together.
+// No retry outlasts the deadline of the context of the call.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one, at most 5. Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the upper bound of the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff limits the growth of the delay between retries.
+	MaxBackoff time.Duration
+	// BackoffMultiplier is the factor the upper bound of the delay grows by with every retry.
+	BackoffMultiplier float64
+	// PerAttemptTimeout limits every attempt of a read without file content transfer, so an attempt
+	// the server does not answer in time is retried. Zero means the attempts are only limited by the call.
+	PerAttemptTimeout time.Duration
+}
+
+// Validate checks that the policy can be passed to gRPC.
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 2 {
+		return nil
+	}
+	switch {
+	case p.MaxAttempts > MaxRetryAttempts:
+		return fmt.Errorf("at most %d attempts are supported, got %d", MaxRetryAttempts, p.MaxAttempts)
+	case p.InitialBackoff <= 0:
+		return fmt.Errorf("initial retry backoff must be positive, got %v", p.InitialBackoff)
+	case p.MaxBackoff < p.InitialBackoff:
+		return fmt.Errorf("maximum retry backoff %v must not be shorter than the initial backoff %v", p.MaxBackoff, p.InitialBackoff)
+	case p.BackoffMultiplier < 1:
+		return fmt.Errorf("retry backoff multiplier must be at least 1, got %v", p.BackoffMultiplier)
+	case p.PerAttemptTimeout < 0:
+		return fmt.Errorf("per attempt timeout must not be negative, got %v", p.PerAttemptTimeout)
+	}
+	return nil
+}
+
+// unaryInterceptor returns a gRPC unary client interceptor retrying the idempotent reads according to the policy.
+// Every attempt runs with its own deadline of PerAttemptTimeout, so a slow attempt is retried while the call
+// has time left. Once the context of the call is done, its error is returned without further attempts.
+func (p RetryPolicy) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if !retriedMethods[method] {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		for attempt := 1; ; attempt++ {
+			err := p.invoke(ctx, method, req, reply, cc, invoker, opts...)
+			if attempt >= p.MaxAttempts || ctx.Err() != nil {
+				return err
+			}
+			if code := status.Code(err); code != codes.Unavailable && code != codes.DeadlineExceeded {
+				return err
+			}
+
+			// Wait for the backoff before the next attempt, unless the call ends first
+			timer := time.NewTimer(p.backoff(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// invoke makes a single attempt of a call, limited by PerAttemptTimeout if it is set.
+func (p RetryPolicy) invoke(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if p.PerAttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.PerAttemptTimeout)
+		defer cancel()
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// backoff returns the random delay before the nth retry.
+func (p RetryPolicy) backoff(n int) time.Duration {
+	limit := math.Min(float64(p.InitialBackoff)*math.Pow(p.BackoffMultiplier, float64(n-1)), float64(p.MaxBackoff))
+	return time.Duration(rand.Int63n(int64(limit) + 1))
+}
+
+// serviceConfig returns the gRPC service config applying the retry policy to the idempotent streaming reads.
+func (p RetryPolicy) serviceConfig() string {
+	type name struct {
+		Service string `json:"service"`
+		Method  string `json:"method"`
+	}
+	type retryPolicy struct {
+		MaxAttempts          int      `json:"maxAttempts"`
+		InitialBackoff       string   `json:"initialBackoff"`
+		MaxBackoff           string   `json:"maxBackoff"`
+		BackoffMultiplier    float64  `json:"backoffMultiplier"`
+		RetryableStatusCodes []string `json:"retryableStatusCodes"`
+	}
+	type methodConfig struct {
+		Name        []name       `json:"name"`
+		RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+	}
+
+	config := methodConfig{}
+	for _, method := range retriedStreamMethods {
+		service, method, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+		config.Name = append(config.Name, name{Service: service, Method: method})
+	}
+	if p.MaxAttempts >= 2 {
+		config.RetryPolicy = &retryPolicy{
+			MaxAttempts:          p.MaxAttempts,
+			InitialBackoff:       durationString(p.InitialBackoff),
+			MaxBackoff:           durationString(p.MaxBackoff),
+			BackoffMultiplier:    p.BackoffMultiplier,
+			RetryableStatusCodes: []string{"UNAVAILABLE"},
+		}
+	}
+
+	serviceConfig, _ := json.Marshal(map[string][]methodConfig{"methodConfig": {config}})
+	return string(serviceConfig)
+}
+
+// durationString formats d in the seconds notation of the JSON mapping of protobuf durations, for example "0.1s".
+func durationString(d time.Duration) string {
+	return fmt.Sprintf("%.9fs", d.Seconds())
+}
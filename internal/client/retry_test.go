@@ -0,0 +1,218 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"filetransfer/api"
+	"filetransfer/internal/logger"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flakyServer fails the first calls with a status, answers the first slow calls only when they are canceled
+// and counts all calls.
+type flakyServer struct {
+	api.UnimplementedFileTransferServer
+	failures atomic.Int32
+	slow     atomic.Int32
+	calls    atomic.Int32
+	code     codes.Code
+}
+
+// fail reports whether the call should fail and with which error.
+func (s *flakyServer) fail() error {
+	s.calls.Add(1)
+	if s.failures.Add(-1) >= 0 {
+		return status.Error(s.code, "temporary failure")
+	}
+	return nil
+}
+
+func (s *flakyServer) GetFileList(ctx context.Context, req *api.FileListRequest) (*api.FileListResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	if s.slow.Add(-1) >= 0 {
+		<-ctx.Done()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	return &api.FileListResponse{Entries: []*api.FileEntry{{Name: "file1.txt"}}}, nil
+}
+
+func (s *flakyServer) GetFileInfo(ctx context.Context, req *api.FileInfoRequest) (*api.FileInfoResponse, error) {
+	s.calls.Add(1)
+	// Never answers, so the call ends with its deadline
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *flakyServer) DownloadFile(req *api.DownloadFileRequest, stream api.FileTransfer_DownloadFileServer) error {
+	if err := s.fail(); err != nil {
+		return err
+	}
+	return stream.Send(&api.FileChunk{Content: []byte("file content"), Sha256: "e0ac3601005dfa1864f5392aabaf7d898b1b5bab854f1acb4491bcd806b76b0c"})
+}
+
+func (s *flakyServer) DeleteFile(ctx context.Context, req *api.DeleteFileRequest) (*api.DeleteFileResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return &api.DeleteFileResponse{}, nil
+}
+
+// startFlakyServer serves a flakyServer failing the first failures calls with code and returns a client connected to it.
+func startFlakyServer(t *testing.T, failures int32, code codes.Code, opts ...Option) (*flakyServer, *FileTransferClient) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	fake := &flakyServer{code: code}
+	fake.failures.Store(failures)
+	grpcServer := grpc.NewServer()
+	api.RegisterFileTransferServer(grpcServer, fake)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	log, err := logger.New(io.Discard, logger.FormatText, "error")
+	require.NoError(t, err)
+	fileTransferClient, err := NewFileTransferClient(listener.Addr().String(), log, opts...)
+	require.NoError(t, err)
+	t.Cleanup(fileTransferClient.Close)
+	return fake, fileTransferClient
+}
+
+// fastRetries retries quickly, so the tests do not wait for the default backoff.
+var fastRetries = WithRetryPolicy(RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, BackoffMultiplier: 2})
+
+func TestFileTransferClient_Retry(t *testing.T) {
+	for _, code := range []codes.Code{codes.Unavailable, codes.DeadlineExceeded} {
+		t.Run(code.String(), func(t *testing.T) {
+			fake, fileTransferClient := startFlakyServer(t, 2, code, fastRetries)
+
+			entries, err := fileTransferClient.GetFileList(context.Background(), "", false, 0)
+
+			require.NoError(t, err)
+			assert.Len(t, entries, 1)
+			assert.Equal(t, int32(3), fake.calls.Load())
+		})
+	}
+}
+
+func TestFileTransferClient_Retry_SlowAttempt(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, BackoffMultiplier: 2, PerAttemptTimeout: 50 * time.Millisecond}
+	fake, fileTransferClient := startFlakyServer(t, 0, codes.OK, WithRetryPolicy(policy), WithCallTimeout(5*time.Second))
+	fake.slow.Store(1)
+
+	// The first attempt exceeds its own timeout, the second one is answered
+	start := time.Now()
+	entries, err := fileTransferClient.GetFileList(context.Background(), "", false, 0)
+
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, int32(2), fake.calls.Load())
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestFileTransferClient_Retry_SlowAttempts_GivesUp(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, BackoffMultiplier: 2, PerAttemptTimeout: 20 * time.Millisecond}
+	fake, fileTransferClient := startFlakyServer(t, 0, codes.OK, WithRetryPolicy(policy))
+
+	_, err := fileTransferClient.GetFileInfo(context.Background(), "file1.txt", false)
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, int32(3), fake.calls.Load())
+}
+
+func TestFileTransferClient_Retry_CallTimeout(t *testing.T) {
+	// Without a per attempt timeout the first attempt takes the whole call, which is not retried
+	fake, fileTransferClient := startFlakyServer(t, 0, codes.OK, fastRetries, WithCallTimeout(50*time.Millisecond))
+
+	_, err := fileTransferClient.GetFileInfo(context.Background(), "file1.txt", false)
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, int32(1), fake.calls.Load())
+}
+
+func TestFileTransferClient_Retry_Download(t *testing.T) {
+	fake, fileTransferClient := startFlakyServer(t, 3, codes.Unavailable, fastRetries)
+
+	var buf bytes.Buffer
+	_, err := fileTransferClient.DownloadFile(context.Background(), "file1.txt", &buf)
+
+	require.NoError(t, err)
+	assert.Equal(t, "file content", buf.String())
+	assert.Equal(t, int32(4), fake.calls.Load())
+}
+
+func TestFileTransferClient_Retry_GivesUp(t *testing.T) {
+	fake, fileTransferClient := startFlakyServer(t, 10, codes.Unavailable, fastRetries)
+
+	_, err := fileTransferClient.GetFileList(context.Background(), "", false, 0)
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, int32(4), fake.calls.Load())
+}
+
+func TestFileTransferClient_Retry_OnlyIdempotentReads(t *testing.T) {
+	fake, fileTransferClient := startFlakyServer(t, 1, codes.Unavailable, fastRetries)
+
+	err := fileTransferClient.DeleteFile(context.Background(), "file1.txt", false)
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, int32(1), fake.calls.Load())
+}
+
+func TestFileTransferClient_Retry_OtherCodes(t *testing.T) {
+	fake, fileTransferClient := startFlakyServer(t, 1, codes.NotFound, fastRetries)
+
+	_, err := fileTransferClient.GetFileList(context.Background(), "", false, 0)
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, int32(1), fake.calls.Load())
+}
+
+func TestFileTransferClient_Retry_Disabled(t *testing.T) {
+	fake, fileTransferClient := startFlakyServer(t, 1, codes.Unavailable, WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+	_, err := fileTransferClient.GetFileList(context.Background(), "", false, 0)
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, int32(1), fake.calls.Load())
+}
+
+func TestFileTransferClient_CallTimeout(t *testing.T) {
+	_, fileTransferClient := startFlakyServer(t, 0, codes.OK, WithCallTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, err := fileTransferClient.GetFileInfo(context.Background(), "file1.txt", false)
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestFileTransferClient_ContextCanceled(t *testing.T) {
+	_, fileTransferClient := startFlakyServer(t, 0, codes.OK, WithCallTimeout(0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := fileTransferClient.GetFileInfo(ctx, "file1.txt", false)
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	assert.NoError(t, DefaultRetryPolicy.Validate())
+	assert.NoError(t, RetryPolicy{}.Validate())
+	assert.Error(t, RetryPolicy{MaxAttempts: 6, InitialBackoff: time.Second, MaxBackoff: time.Second, BackoffMultiplier: 2}.Validate())
+	assert.Error(t, RetryPolicy{MaxAttempts: 3, MaxBackoff: time.Second, BackoffMultiplier: 2}.Validate())
+	assert.Error(t, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Millisecond, BackoffMultiplier: 2}.Validate())
+	assert.Error(t, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Second, BackoffMultiplier: 0.5}.Validate())
+	assert.Error(t, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Second, BackoffMultiplier: 2, PerAttemptTimeout: -time.Second}.Validate())
+}
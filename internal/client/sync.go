@@ -0,0 +1,392 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"filetransfer/api"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SyncActionType describes what Sync does with an entry.
+type SyncActionType string
+
+const (
+	// SyncCreate is a file downloaded or a directory created because it is missing locally.
+	SyncCreate SyncActionType = "create"
+	// SyncUpdate is a file downloaded because its local content differs.
+	SyncUpdate SyncActionType = "update"
+	// SyncDelete is a local file or directory deleted because it is missing on the server.
+	SyncDelete SyncActionType = "delete"
+	// SyncSkip is a remote entry that cannot be synced, like a symbolic link.
+	SyncSkip SyncActionType = "skip"
+	// SyncFail is an entry that could not be synced because of Err.
+	SyncFail SyncActionType = "fail"
+)
+
+// SyncAction is a change Sync made or, in a dry run, would make.
+type SyncAction struct {
+	Type SyncActionType
+	// Path is the path of the entry relative to the synced directories, using forward slashes.
+	Path string
+	// Directory is set for directories.
+	Directory bool
+	// Size is the number of bytes downloaded for created and updated files.
+	Size int64
+	// Err is the reason of a failed action.
+	Err error
+}
+
+// SyncOptions configures how Sync mirrors a directory.
+type SyncOptions struct {
+	// Delete removes local files and directories that do not exist on the server.
+	Delete bool
+	// DryRun reports the changes without making them.
+	DryRun bool
+	// Checksum compares the hash of files whose size and modification time match as well,
+	// instead of considering them unchanged.
+	Checksum bool
+	// Delta downloads changed files as a delta against their local copy, see DeltaDownload.
+	Delta bool
+	// OnAction is called for every action when it has been made, it may be nil.
+	OnAction func(SyncAction)
+}
+
+// SyncReport summarizes the changes made by Sync.
+type SyncReport struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Deleted   int
+	Skipped   int
+	Failed    int
+	// BytesTransferred is the number of bytes downloaded, or that would be downloaded in a dry run.
+	BytesTransferred int64
+	// BytesSaved is the number of bytes delta downloads transferred less than complete downloads.
+	BytesSaved int64
+	// UnprofitableDeltas is the number of delta downloads that saved nothing, see DeltaReport.Unprofitable.
+	UnprofitableDeltas int
+}
+
+// ErrSyncIncomplete is returned by Sync if some entries could not be synced.
+var ErrSyncIncomplete = errors.New("sync incomplete")
+
+// ErrUnsafePath is the error of entries whose path lies outside of the synced directory.
+var ErrUnsafePath = errors.New("path is outside of the synced directory")
+
+// Sync mirrors the remote directory, an empty remoteDir being the root, into the local directory.
+// Only files that are missing locally or differ from the server are downloaded: files of the same size and
+// modification time are considered unchanged, files of the same size but another modification time are
+// compared by their SHA-256 hash. Downloaded files get the modification time of the server, so they are
+// recognized as unchanged by the next sync. Entries that fail to sync are reported and skipped, in which case
+// ErrSyncIncomplete is returned together with the report.
+func (c *FileTransferClient) Sync(ctx context.Context, remoteDir, localDir string, opts SyncOptions) (*SyncReport, error) {
+	entries, err := c.GetFileList(ctx, remoteDir, true, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &syncer{client: c, localDir: localDir, opts: opts, report: &SyncReport{}}
+	if !opts.DryRun {
+		if err := os.MkdirAll(localDir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	// Remote paths that exist locally after the sync, everything else is deleted
+	root := path.Clean("./" + remoteDir)
+	remote := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		relPath, ok := syncRelPath(root, entry.Path)
+		if !ok {
+			s.record(SyncAction{Type: SyncFail, Path: entry.Path, Directory: entry.Type == api.EntryType_ENTRY_TYPE_DIRECTORY,
+				Err: fmt.Errorf("%w: %s", ErrUnsafePath, entry.Path)})
+			continue
+		}
+		remote[relPath] = true
+
+		if err := s.syncEntry(ctx, entry, relPath); err != nil {
+			// A canceled sync is not continued
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return s.report, ctxErr
+			}
+			s.record(SyncAction{Type: SyncFail, Path: relPath, Directory: entry.Type == api.EntryType_ENTRY_TYPE_DIRECTORY, Err: err})
+		}
+	}
+
+	if opts.Delete {
+		if err := s.deleteExtra(remote); err != nil {
+			return s.report, err
+		}
+	}
+
+	if s.report.Failed > 0 {
+		return s.report, fmt.Errorf("%w: %d entries could not be synced", ErrSyncIncomplete, s.report.Failed)
+	}
+	return s.report, nil
+}
+
+// syncRelPath returns the path of a remote entry relative to the synced directory root. It reports false for paths
+// that are not below root or that would lead outside of the local directory, which only a faulty or hostile server
+// sends, so they are never written or deleted.
+func syncRelPath(root, entryPath string) (string, bool) {
+	relPath := entryPath
+	if root != "." {
+		var found bool
+		if relPath, found = strings.CutPrefix(entryPath, root+"/"); !found {
+			return "", false
+		}
+	}
+	return relPath, relPath == path.Clean(relPath) && filepath.IsLocal(filepath.FromSlash(relPath))
+}
+
+// syncer holds the state of a running Sync.
+type syncer struct {
+	client   *FileTransferClient
+	localDir string
+	opts     SyncOptions
+	report   *SyncReport
+}
+
+// record counts the action in the report and passes it to the OnAction callback.
+func (s *syncer) record(action SyncAction) {
+	switch action.Type {
+	case SyncCreate:
+		s.report.Created++
+	case SyncUpdate:
+		s.report.Updated++
+	case SyncDelete:
+		s.report.Deleted++
+	case SyncSkip:
+		s.report.Skipped++
+	case SyncFail:
+		s.report.Failed++
+	}
+	s.report.BytesTransferred += action.Size
+
+	if s.opts.OnAction != nil {
+		s.opts.OnAction(action)
+	}
+}
+
+// syncEntry brings the local counterpart of a remote entry up to date.
+func (s *syncer) syncEntry(ctx context.Context, entry *api.FileEntry, relPath string) error {
+	localPath := filepath.Join(s.localDir, filepath.FromSlash(relPath))
+	localInfo, err := os.Lstat(localPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	exists := err == nil
+
+	switch entry.Type {
+	case api.EntryType_ENTRY_TYPE_DIRECTORY:
+		if exists && localInfo.IsDir() {
+			return nil
+		}
+		if exists {
+			return fmt.Errorf("%s exists locally but is not a directory", localPath)
+		}
+		if !s.opts.DryRun {
+			if err := os.MkdirAll(localPath, 0755); err != nil {
+				return err
+			}
+		}
+		s.record(SyncAction{Type: SyncCreate, Path: relPath, Directory: true})
+		return nil
+
+	case api.EntryType_ENTRY_TYPE_FILE:
+		if exists && !localInfo.Mode().IsRegular() {
+			return fmt.Errorf("%s exists locally but is not a regular file", localPath)
+		}
+		return s.syncFile(ctx, entry, relPath, localPath, localInfo)
+	}
+
+	// Symbolic links and special files are not mirrored
+	s.record(SyncAction{Type: SyncSkip, Path: relPath})
+	return nil
+}
+
+// syncFile downloads a remote file if it is missing locally or its local content differs.
+// localInfo is nil if the file does not exist locally.
+func (s *syncer) syncFile(ctx context.Context, entry *api.FileEntry, relPath, localPath string, localInfo os.FileInfo) error {
+	info, err := s.client.GetFileInfo(ctx, entry.Path, false)
+	if err != nil {
+		return err
+	}
+	modTime := info.ModificationTime.AsTime()
+
+	actionType := SyncCreate
+	if localInfo != nil {
+		actionType = SyncUpdate
+		if localInfo.Size() == int64(info.Size) {
+			sameTime := localInfo.ModTime().Unix() == modTime.Unix()
+			unchanged, err := s.sameContent(ctx, entry.Path, localPath, sameTime)
+			if err != nil {
+				return err
+			}
+			if unchanged {
+				// Adopting the modification time of the server spares the hash comparison next time
+				if !sameTime && !s.opts.DryRun {
+					if err := os.Chtimes(localPath, modTime, modTime); err != nil {
+						return err
+					}
+				}
+				s.report.Unchanged++
+				return nil
+			}
+		}
+	}
+
+	size := int64(info.Size)
+	if !s.opts.DryRun {
+		if actionType == SyncUpdate && s.opts.Delta {
+			size, err = s.deltaDownload(ctx, entry.Path, localPath, info, modTime)
+		} else {
+			err = s.download(ctx, entry.Path, localPath, info, modTime)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	s.record(SyncAction{Type: actionType, Path: relPath, Size: size})
+	return nil
+}
+
+// sameContent reports whether a local file of the same size as the remote file has the same content.
+// Files with the same modification time are considered equal unless checksums are compared for all files.
+// The server only hashes the remote file when the hashes are compared.
+func (s *syncer) sameContent(ctx context.Context, remotePath, localPath string, sameTime bool) (bool, error) {
+	if sameTime && !s.opts.Checksum {
+		return true, nil
+	}
+
+	hashInfo, err := s.client.GetFileInfo(ctx, remotePath, true)
+	if err != nil {
+		return false, err
+	}
+
+	localHash, err := fileHash(localPath)
+	if err != nil {
+		return false, err
+	}
+	return localHash == hashInfo.Sha256, nil
+}
+
+// fileHash returns the hex encoded SHA-256 hash of the content of a local file.
+func fileHash(localPath string) (string, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// download replaces the local file with the content of the remote file, its permissions and modification time.
+// The content is written to a temporary file first, so an interrupted download leaves the local file untouched.
+func (s *syncer) download(ctx context.Context, remotePath, localPath string, info *api.FileInfoResponse, modTime time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(localPath), "."+filepath.Base(localPath)+".sync-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := s.client.DownloadFile(ctx, remotePath, file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(file.Name(), fileMode(info)); err != nil {
+		return err
+	}
+	if err := os.Chtimes(file.Name(), modTime, modTime); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), localPath)
+}
+
+// deltaDownload updates the local file to the content of the remote file with a delta download and sets
+// its permissions and modification time. It returns the number of bytes downloaded.
+func (s *syncer) deltaDownload(ctx context.Context, remotePath, localPath string, info *api.FileInfoResponse, modTime time.Time) (int64, error) {
+	report, err := s.client.DeltaDownload(ctx, remotePath, localPath)
+	if err != nil {
+		return 0, err
+	}
+	s.report.BytesSaved += report.BytesSaved()
+	if report.Unprofitable() {
+		s.report.UnprofitableDeltas++
+	}
+
+	if err := os.Chmod(localPath, fileMode(info)); err != nil {
+		return 0, err
+	}
+	if err := os.Chtimes(localPath, modTime, modTime); err != nil {
+		return 0, err
+	}
+	return report.LiteralBytes, nil
+}
+
+// fileMode returns the permissions of a downloaded file, which are those of the remote file if it reports them.
+func fileMode(info *api.FileInfoResponse) fs.FileMode {
+	if mode := fs.FileMode(info.Mode & 0777); mode != 0 {
+		return mode
+	}
+	return 0644
+}
+
+// deleteExtra deletes the local files and directories whose paths are not in remote.
+// Directories are deleted together with their content.
+func (s *syncer) deleteExtra(remote map[string]bool) error {
+	return filepath.WalkDir(s.localDir, func(localPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// The local directory does not exist in a dry run
+			if s.opts.DryRun && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if localPath == s.localDir {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(s.localDir, localPath)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if remote[relPath] {
+			return nil
+		}
+
+		if !s.opts.DryRun {
+			if err := os.RemoveAll(localPath); err != nil {
+				s.record(SyncAction{Type: SyncFail, Path: relPath, Directory: d.IsDir(), Err: err})
+				return nil
+			}
+		}
+		s.record(SyncAction{Type: SyncDelete, Path: relPath, Directory: d.IsDir()})
+		if d.IsDir() {
+			return fs.SkipDir
+		}
+		return nil
+	})
+}
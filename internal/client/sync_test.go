@@ -0,0 +1,281 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"filetransfer/api"
+	"filetransfer/internal/logger"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// remoteModTime is the modification time of every remote file.
+var remoteModTime = time.Date(2023, 10, 18, 12, 0, 0, 0, time.UTC)
+
+// newSyncTestClient returns a client whose server holds the files with the given content below the directory
+// "data", directories are given with a trailing slash. It counts the downloads by path.
+func newSyncTestClient(t *testing.T, files map[string]string) (*FileTransferClient, map[string]int) {
+	ctrl := gomock.NewController(t)
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	var entries []*api.FileEntry
+	for name, content := range files {
+		entry := &api.FileEntry{Name: path.Base(name), Path: path.Join("data", name), Type: api.EntryType_ENTRY_TYPE_FILE, Size: uint64(len(content))}
+		if name[len(name)-1] == '/' {
+			entry.Type, entry.Size = api.EntryType_ENTRY_TYPE_DIRECTORY, 0
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	mockClient.EXPECT().GetFileList(gomock.Any(), &api.FileListRequest{Path: "data", Recursive: true}).Return(&api.FileListResponse{Entries: entries}, nil).AnyTimes()
+
+	mockClient.EXPECT().GetFileInfo(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *api.FileInfoRequest, opts ...grpc.CallOption) (*api.FileInfoResponse, error) {
+			content, ok := files[req.Filename[len("data/"):]]
+			if !ok {
+				return nil, status.Error(codes.NotFound, "file not found")
+			}
+			resp := &api.FileInfoResponse{Filename: req.Filename, Size: uint64(len(content)), Mode: 0640, ModificationTime: timestamppb.New(remoteModTime)}
+			if req.Hash {
+				resp.Sha256 = sha256Hex(content)
+			}
+			return resp, nil
+		}).AnyTimes()
+
+	downloads := make(map[string]int)
+	mockClient.EXPECT().DownloadFile(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *api.DownloadFileRequest, opts ...grpc.CallOption) (api.FileTransfer_DownloadFileClient, error) {
+			name := req.Filename[len("data/"):]
+			downloads[name]++
+			stream := api.NewMockFileTransfer_DownloadFileClient(ctrl)
+			gomock.InOrder(
+				stream.EXPECT().Recv().Return(&api.FileChunk{Content: []byte(files[name])}, nil),
+				stream.EXPECT().Recv().Return(&api.FileChunk{Sha256: sha256Hex(files[name])}, nil),
+				stream.EXPECT().Recv().Return(nil, io.EOF),
+			)
+			return stream, nil
+		}).AnyTimes()
+
+	return &FileTransferClient{client: mockClient, logger: mockLogger}, downloads
+}
+
+// sha256Hex returns the hex encoded SHA-256 hash of the content.
+func sha256Hex(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+// writeLocalFile writes a local file with the content and modification time.
+func writeLocalFile(t *testing.T, localPath, content string, modTime time.Time) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(localPath), 0755))
+	require.NoError(t, os.WriteFile(localPath, []byte(content), 0644))
+	require.NoError(t, os.Chtimes(localPath, modTime, modTime))
+}
+
+// newSyncTestTree returns a local directory holding an outdated copy of the remote files of newSyncTestClient.
+func newSyncTestTree(t *testing.T) string {
+	localDir := t.TempDir()
+	writeLocalFile(t, filepath.Join(localDir, "unchanged.txt"), "unchanged", remoteModTime)
+	writeLocalFile(t, filepath.Join(localDir, "touched.txt"), "touched", remoteModTime.Add(-time.Hour))
+	writeLocalFile(t, filepath.Join(localDir, "changed.txt"), "old", remoteModTime.Add(-time.Hour))
+	writeLocalFile(t, filepath.Join(localDir, "resized.txt"), "old content", remoteModTime)
+	writeLocalFile(t, filepath.Join(localDir, "extra.txt"), "extra", remoteModTime)
+	writeLocalFile(t, filepath.Join(localDir, "extra", "file.txt"), "extra", remoteModTime)
+	return localDir
+}
+
+// syncTestFiles are the remote files synced into the tree of newSyncTestTree.
+var syncTestFiles = map[string]string{
+	"unchanged.txt":   "unchanged",
+	"touched.txt":     "touched",
+	"changed.txt":     "new",
+	"resized.txt":     "new content, longer",
+	"dir/":            "",
+	"dir/new.txt":     "new file",
+	"dir/empty/":      "",
+	"dir/empty2/":     "",
+	"dir/sub/":        "",
+	"dir/sub/new.txt": "nested",
+}
+
+func TestFileTransferClient_Sync(t *testing.T) {
+	client, downloads := newSyncTestClient(t, syncTestFiles)
+	localDir := newSyncTestTree(t)
+
+	var actions []SyncAction
+	report, err := client.Sync(context.Background(), "data", localDir, SyncOptions{
+		Delete:   true,
+		OnAction: func(action SyncAction) { actions = append(actions, action) },
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, &SyncReport{Created: 6, Updated: 2, Unchanged: 2, Deleted: 2, BytesTransferred: 3 + 19 + 8 + 6}, report)
+	assert.Contains(t, actions, SyncAction{Type: SyncUpdate, Path: "changed.txt", Size: 3})
+	assert.Contains(t, actions, SyncAction{Type: SyncCreate, Path: "dir/sub/new.txt", Size: 6})
+	assert.Contains(t, actions, SyncAction{Type: SyncCreate, Path: "dir/empty", Directory: true})
+	assert.Contains(t, actions, SyncAction{Type: SyncDelete, Path: "extra", Directory: true})
+
+	// Only new and changed files were downloaded
+	assert.Equal(t, map[string]int{"changed.txt": 1, "resized.txt": 1, "dir/new.txt": 1, "dir/sub/new.txt": 1}, downloads)
+
+	// The local tree mirrors the remote directory, including the modification times
+	for name, content := range syncTestFiles {
+		localPath := filepath.Join(localDir, filepath.FromSlash(name))
+		info, err := os.Stat(localPath)
+		require.NoError(t, err)
+		if info.IsDir() {
+			continue
+		}
+		local, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(local), name)
+		assert.True(t, info.ModTime().Equal(remoteModTime), name)
+	}
+	assert.NoFileExists(t, filepath.Join(localDir, "extra.txt"))
+	assert.NoDirExists(t, filepath.Join(localDir, "extra"))
+	info, err := os.Stat(filepath.Join(localDir, "dir", "new.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	// A second sync finds nothing to do
+	report, err = client.Sync(context.Background(), "data", localDir, SyncOptions{Delete: true})
+	require.NoError(t, err)
+	assert.Equal(t, &SyncReport{Unchanged: 6}, report)
+	assert.Len(t, downloads, 4)
+}
+
+func TestFileTransferClient_Sync_DryRun(t *testing.T) {
+	client, downloads := newSyncTestClient(t, syncTestFiles)
+	localDir := newSyncTestTree(t)
+
+	report, err := client.Sync(context.Background(), "data", localDir, SyncOptions{Delete: true, DryRun: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, &SyncReport{Created: 6, Updated: 2, Unchanged: 2, Deleted: 2, BytesTransferred: 3 + 19 + 8 + 6}, report)
+	assert.Empty(t, downloads)
+	assert.FileExists(t, filepath.Join(localDir, "extra.txt"))
+	assert.NoDirExists(t, filepath.Join(localDir, "dir"))
+	info, err := os.Stat(filepath.Join(localDir, "touched.txt"))
+	require.NoError(t, err)
+	assert.False(t, info.ModTime().Equal(remoteModTime))
+
+	// A dry run into a missing directory does not create it
+	missing := filepath.Join(t.TempDir(), "missing")
+	report, err = client.Sync(context.Background(), "data", missing, SyncOptions{Delete: true, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 10, report.Created)
+	assert.NoDirExists(t, missing)
+}
+
+func TestFileTransferClient_Sync_Checksum(t *testing.T) {
+	client, downloads := newSyncTestClient(t, map[string]string{"same.txt": "remote"})
+	localDir := t.TempDir()
+	writeLocalFile(t, filepath.Join(localDir, "same.txt"), "locale", remoteModTime)
+
+	// Size and modification time match, only the checksum reveals the difference
+	report, err := client.Sync(context.Background(), "data", localDir, SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Unchanged)
+
+	report, err = client.Sync(context.Background(), "data", localDir, SyncOptions{Checksum: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, map[string]int{"same.txt": 1}, downloads)
+}
+
+func TestFileTransferClient_Sync_ChecksumSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileClient(ctrl)
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	client := &FileTransferClient{client: mockClient, logger: mockLogger}
+
+	localDir := t.TempDir()
+	writeLocalFile(t, filepath.Join(localDir, "resized.txt"), "old", remoteModTime)
+
+	// A file of another size is downloaded without having the server hash it
+	mockClient.EXPECT().GetFileList(gomock.Any(), &api.FileListRequest{Path: "data", Recursive: true}).Return(&api.FileListResponse{Entries: []*api.FileEntry{
+		{Name: "resized.txt", Path: "data/resized.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 7},
+	}}, nil)
+	mockClient.EXPECT().GetFileInfo(gomock.Any(), &api.FileInfoRequest{Filename: "data/resized.txt"}).
+		Return(&api.FileInfoResponse{Filename: "data/resized.txt", Size: 7, Mode: 0644, ModificationTime: timestamppb.New(remoteModTime)}, nil)
+	mockClient.EXPECT().DownloadFile(gomock.Any(), &api.DownloadFileRequest{Filename: "data/resized.txt"}).Return(mockStream, nil)
+	gomock.InOrder(
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Content: []byte("resized")}, nil),
+		mockStream.EXPECT().Recv().Return(&api.FileChunk{Sha256: sha256Hex("resized")}, nil),
+		mockStream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	report, err := client.Sync(context.Background(), "data", localDir, SyncOptions{Checksum: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Updated)
+}
+
+func TestFileTransferClient_Sync_Failure(t *testing.T) {
+	client, _ := newSyncTestClient(t, map[string]string{"file.txt": "content", "other.txt": "other"})
+	localDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(localDir, "file.txt"), 0755))
+
+	// The conflicting entry is reported, the others are synced anyway
+	report, err := client.Sync(context.Background(), "data", localDir, SyncOptions{})
+
+	assert.ErrorIs(t, err, ErrSyncIncomplete)
+	assert.Equal(t, &SyncReport{Created: 1, Failed: 1, BytesTransferred: 5}, report)
+	assert.FileExists(t, filepath.Join(localDir, "other.txt"))
+}
+
+func TestFileTransferClient_Sync_UnsafePath(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockLogger := logger.NewMockClientLogger(ctrl)
+	mockClient := api.NewMockFileTransferClient(ctrl)
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	client := &FileTransferClient{client: mockClient, logger: mockLogger}
+
+	// A hostile server lists entries leading out of the synced directory
+	mockClient.EXPECT().GetFileList(gomock.Any(), &api.FileListRequest{Path: "data", Recursive: true}).Return(&api.FileListResponse{Entries: []*api.FileEntry{
+		{Name: "evil.txt", Path: "data/../../evil.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 4},
+		{Name: "evil.txt", Path: "data/sub/../../../evil.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 4},
+		{Name: "evil", Path: "data/..", Type: api.EntryType_ENTRY_TYPE_DIRECTORY},
+		{Name: "evil.txt", Path: "other/evil.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 4},
+		{Name: "evil.txt", Path: "/evil.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 4},
+	}}, nil)
+
+	parent := t.TempDir()
+	localDir := filepath.Join(parent, "local")
+	writeLocalFile(t, filepath.Join(parent, "evil.txt"), "safe", remoteModTime)
+
+	// Nothing is downloaded, written or deleted outside of the local directory
+	var failed []SyncAction
+	report, err := client.Sync(context.Background(), "data", localDir, SyncOptions{Delete: true, OnAction: func(action SyncAction) {
+		failed = append(failed, action)
+	}})
+
+	assert.ErrorIs(t, err, ErrSyncIncomplete)
+	assert.Equal(t, &SyncReport{Failed: 5}, report)
+	for _, action := range failed {
+		assert.ErrorIs(t, action.Err, ErrUnsafePath, action.Path)
+	}
+	content, err := os.ReadFile(filepath.Join(parent, "evil.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "safe", string(content))
+	entries, err := os.ReadDir(parent)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
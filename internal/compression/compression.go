@@ -0,0 +1,71 @@
+package compression
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	// Registers the gzip compressor with gRPC
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// Names of the compressors registered with gRPC, None disables compression.
+const (
+	None = "none"
+	Gzip = gzip.Name
+	Zstd = "zstd"
+)
+
+// Validate checks that name is None or the name of a registered compressor.
+func Validate(name string) error {
+	switch name {
+	case None, Gzip, Zstd:
+		return nil
+	}
+	return fmt.Errorf("unknown compression %q, expected none, gzip or zstd", name)
+}
+
+// compressedExtensions contains the extensions of file types whose content is already compressed.
+var compressedExtensions = map[string]bool{
+	".7z": true, ".br": true, ".bz2": true, ".gz": true, ".jar": true, ".lz4": true, ".rar": true, ".tgz": true,
+	".xz": true, ".zip": true, ".zst": true,
+	".avif": true, ".gif": true, ".heic": true, ".jpeg": true, ".jpg": true, ".png": true, ".webp": true,
+	".aac": true, ".flac": true, ".m4a": true, ".mp3": true, ".ogg": true, ".opus": true,
+	".avi": true, ".m4v": true, ".mkv": true, ".mov": true, ".mp4": true, ".webm": true,
+	".docx": true, ".pptx": true, ".xlsx": true, ".odt": true, ".epub": true,
+}
+
+// compressedSignatures contains the leading bytes of compressed formats not recognized by http.DetectContentType.
+var compressedSignatures = [][]byte{
+	{0x28, 0xb5, 0x2f, 0xfd},           // zstd
+	{0xfd, '7', 'z', 'X', 'Z', 0x00},   // xz
+	{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c}, // 7z
+	{'B', 'Z', 'h'},                    // bzip2
+	{0x04, 0x22, 0x4d, 0x18},           // lz4
+}
+
+// IsCompressed reports whether the file is already compressed, so compressing it again would only cost time.
+// The decision is based on the extension of filename and, if that is not conclusive, on the leading bytes
+// of the content.
+func IsCompressed(filename string, content []byte) bool {
+	if compressedExtensions[strings.ToLower(path.Ext(filename))] {
+		return true
+	}
+
+	for _, signature := range compressedSignatures {
+		if bytes.HasPrefix(content, signature) {
+			return true
+		}
+	}
+
+	switch contentType := http.DetectContentType(content); {
+	case strings.HasPrefix(contentType, "image/"), strings.HasPrefix(contentType, "audio/"), strings.HasPrefix(contentType, "video/"):
+		// Bitmaps are not compressed, unlike the other detected image formats
+		return contentType != "image/bmp" && contentType != "image/x-icon"
+	case contentType == "application/zip", contentType == "application/x-gzip", contentType == "application/x-rar-compressed":
+		return true
+	}
+	return false
+}
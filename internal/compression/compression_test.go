@@ -0,0 +1,77 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestValidate(t *testing.T) {
+	for _, name := range []string{None, Gzip, Zstd} {
+		assert.NoError(t, Validate(name))
+	}
+	assert.Error(t, Validate("brotli"))
+	assert.Error(t, Validate(""))
+}
+
+func TestIsCompressed(t *testing.T) {
+	var gzipped bytes.Buffer
+	w := gzip.NewWriter(&gzipped)
+	w.Write([]byte("content"))
+	w.Close()
+
+	tests := []struct {
+		name     string
+		filename string
+		content  []byte
+		expected bool
+	}{
+		{"text", "server.log", []byte("level=info msg=started\n"), false},
+		{"csv", "data.csv", []byte("a,b,c\n1,2,3\n"), false},
+		{"empty", "empty", nil, false},
+		{"zip extension", "archive.ZIP", nil, true},
+		{"jpeg extension", "photo.jpg", nil, true},
+		{"mp4 extension", "movie.mp4", nil, true},
+		{"gzip content", "backup", gzipped.Bytes(), true},
+		{"zstd content", "backup", []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00}, true},
+		{"zip content", "report", []byte("PK\x03\x04rest"), true},
+		{"png content", "image", []byte("\x89PNG\x0D\x0A\x1A\x0Arest"), true},
+		{"bmp content", "image", []byte("BMrest"), false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, IsCompressed(tt.filename, tt.content), tt.name)
+	}
+}
+
+func TestZstdCompressor(t *testing.T) {
+	compressor := encoding.GetCompressor(Zstd)
+	require.NotNil(t, compressor)
+	content := []byte(strings.Repeat("time=2023-10-18 level=info msg=\"gRPC call finished\"\n", 1000))
+
+	// Encoders and decoders are reused from the pool in the second round
+	for i := 0; i < 2; i++ {
+		var compressed bytes.Buffer
+		w, err := compressor.Compress(&compressed)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		assert.Less(t, compressed.Len(), len(content)/10)
+
+		r, err := compressor.Decompress(&compressed)
+		require.NoError(t, err)
+		decompressed, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, content, decompressed)
+	}
+}
+
+func TestGzipCompressorRegistered(t *testing.T) {
+	assert.NotNil(t, encoding.GetCompressor(Gzip))
+}
@@ -0,0 +1,81 @@
+package compression
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc/stats"
+)
+
+// Stats holds the size of the messages of a call before and after compression.
+type Stats struct {
+	// Bytes is the size of the messages before compression.
+	Bytes int64
+	// CompressedBytes is the size of the messages as sent over the wire, without framing.
+	CompressedBytes int64
+}
+
+// BytesSaved returns the number of bytes compression saved, which is negative if compression made the messages larger.
+func (s Stats) BytesSaved() int64 {
+	return s.Bytes - s.CompressedBytes
+}
+
+// ReportFunc receives the compression stats of a finished call of the full method.
+type ReportFunc func(ctx context.Context, method string, stats Stats)
+
+// statsHandler is a gRPC stats handler adding up the payload sizes of every call.
+type statsHandler struct {
+	report ReportFunc
+}
+
+// callStats accumulates the payload sizes of a call while it runs.
+type callStats struct {
+	method          string
+	bytes           atomic.Int64
+	compressedBytes atomic.Int64
+}
+
+// callStatsKey is the context key of the callStats of a call.
+type callStatsKey struct{}
+
+// NewStatsHandler returns a gRPC stats handler calling report when a call has finished,
+// if at least one of its messages was compressed.
+func NewStatsHandler(report ReportFunc) stats.Handler {
+	return &statsHandler{report: report}
+}
+
+// TagRPC attaches the stats of the call to its context.
+func (h *statsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, callStatsKey{}, &callStats{method: info.FullMethodName})
+}
+
+// HandleRPC adds up the payload sizes and reports them at the end of the call.
+func (h *statsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	call, ok := ctx.Value(callStatsKey{}).(*callStats)
+	if !ok {
+		return
+	}
+
+	switch s := s.(type) {
+	case *stats.InPayload:
+		call.bytes.Add(int64(s.Length))
+		call.compressedBytes.Add(int64(s.CompressedLength))
+	case *stats.OutPayload:
+		call.bytes.Add(int64(s.Length))
+		call.compressedBytes.Add(int64(s.CompressedLength))
+	case *stats.End:
+		// Uncompressed payloads have the same size before and after compression
+		result := Stats{Bytes: call.bytes.Load(), CompressedBytes: call.compressedBytes.Load()}
+		if result.BytesSaved() != 0 {
+			h.report(ctx, call.method, result)
+		}
+	}
+}
+
+// TagConn returns the context unchanged, connections are not tracked.
+func (h *statsHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn ignores connection events.
+func (h *statsHandler) HandleConn(ctx context.Context, s stats.ConnStats) {}
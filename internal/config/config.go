@@ -0,0 +1,559 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"filetransfer/internal/ratelimit"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of the environment variables configuring the server.
+const EnvPrefix = "FILETRANSFER_"
+
+// Storage backends selected by the scheme of the storage root.
+const (
+	// StorageLocal serves the files of a directory as they are, it is used for storage roots without a scheme.
+	StorageLocal = "local"
+	// StorageDedup stores the files in a directory as content-addressed blobs, storing identical content once.
+	StorageDedup = "dedup"
+	// StorageS3 serves the objects of an S3 bucket, the location is the bucket followed by an optional key prefix.
+	StorageS3 = "s3"
+	// StorageMemory keeps the files in memory until the server exits, the location must be empty.
+	StorageMemory = "mem"
+)
+
+// logLevels contains the accepted log levels.
+var logLevels = map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
+
+// ServerConfig holds the settings of the file transfer server.
+type ServerConfig struct {
+	// ListenAddress is the host and port the gRPC server listens on.
+	ListenAddress string `yaml:"listen_address" toml:"listen_address"`
+	// StorageRoot is the directory served by the server. A dedup:// prefix stores the files of the directory
+	// deduplicated by content instead of as plain files, s3://bucket/prefix serves the objects of an S3 bucket
+	// and mem:// keeps the files in memory.
+	StorageRoot string `yaml:"storage_root" toml:"storage_root"`
+	// S3 holds the settings of the S3 service of s3:// storage roots.
+	S3 S3Config `yaml:"s3" toml:"s3"`
+	// Memory holds the size limits of mem:// storage roots.
+	Memory MemoryConfig `yaml:"memory" toml:"memory"`
+	// LogLevel is the minimum level of logged messages: debug, info, warn or error.
+	LogLevel string `yaml:"log_level" toml:"log_level"`
+	// LogFormat is the format of logged messages: text or json.
+	LogFormat string `yaml:"log_format" toml:"log_format"`
+	// TLS holds the TLS settings, the server accepts plaintext connections without a certificate.
+	TLS TLSConfig `yaml:"tls" toml:"tls"`
+	// AuthzPolicy is the path of a YAML authorization policy, every caller may do everything without one.
+	AuthzPolicy string `yaml:"authz_policy" toml:"authz_policy"`
+	// MetricsAddress is the host and port of the HTTP server serving Prometheus metrics, metrics are disabled if empty.
+	MetricsAddress string `yaml:"metrics_address" toml:"metrics_address"`
+	// Tracing holds the OpenTelemetry tracing settings.
+	Tracing TracingConfig `yaml:"tracing" toml:"tracing"`
+	// Limits holds limits protecting the server from excessive use.
+	Limits LimitsConfig `yaml:"limits" toml:"limits"`
+	// RateLimit holds the request rate and bandwidth limits of clients.
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit"`
+}
+
+// S3Config holds the settings of the S3-compatible service serving the bucket of an s3:// storage root.
+type S3Config struct {
+	// Endpoint is the URL of an S3-compatible service, AWS S3 is used if empty.
+	Endpoint string `yaml:"endpoint" toml:"endpoint"`
+	// Region is the region of the bucket, it is taken from the AWS environment if empty.
+	Region string `yaml:"region" toml:"region"`
+	// AccessKeyID and SecretAccessKey are static credentials, the default AWS credential chain is used without them.
+	AccessKeyID     string `yaml:"access_key_id" toml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key" toml:"secret_access_key"`
+	// PathStyle addresses the bucket in the URL path instead of the host name, as most S3-compatible services require.
+	PathStyle bool `yaml:"path_style" toml:"path_style"`
+}
+
+// MemoryConfig holds the size limits of the in-memory storage of a mem:// storage root, zero values disable
+// the respective limit.
+type MemoryConfig struct {
+	// MaxFileSize is the maximum size of a single file in bytes.
+	MaxFileSize int64 `yaml:"max_file_size" toml:"max_file_size"`
+	// MaxSize is the maximum size of all files together in bytes.
+	MaxSize int64 `yaml:"max_size" toml:"max_size"`
+}
+
+// TLSConfig holds the paths of the PEM encoded files used for TLS.
+type TLSConfig struct {
+	// CertFile is the server certificate.
+	CertFile string `yaml:"cert_file" toml:"cert_file"`
+	// KeyFile is the private key of the server certificate.
+	KeyFile string `yaml:"key_file" toml:"key_file"`
+	// ClientCAFile is a CA bundle, clients must present a certificate signed by it if set (mutual TLS).
+	ClientCAFile string `yaml:"client_ca_file" toml:"client_ca_file"`
+}
+
+// TracingConfig holds the settings of the exporter of OpenTelemetry traces.
+type TracingConfig struct {
+	// Exporter is where finished spans are exported: none, stdout or otlp.
+	Exporter string `yaml:"exporter" toml:"exporter"`
+	// OTLPEndpoint is the address of the OpenTelemetry collector used by the otlp exporter,
+	// an http:// prefix disables TLS. The standard OTEL_EXPORTER_OTLP_* variables apply if empty.
+	OTLPEndpoint string `yaml:"otlp_endpoint" toml:"otlp_endpoint"`
+}
+
+// LimitsConfig holds limits of the gRPC server, zero values keep the gRPC defaults.
+type LimitsConfig struct {
+	// MaxRecvMessageSize is the maximum size of a received message in bytes.
+	MaxRecvMessageSize int `yaml:"max_recv_message_size" toml:"max_recv_message_size"`
+	// MaxConcurrentStreams is the maximum number of concurrent calls per client connection.
+	MaxConcurrentStreams uint32 `yaml:"max_concurrent_streams" toml:"max_concurrent_streams"`
+	// MaxConcurrentCalls is the maximum number of calls the server runs at once, 0 means no limit.
+	MaxConcurrentCalls int64 `yaml:"max_concurrent_calls" toml:"max_concurrent_calls"`
+	// MaxConcurrentTransfers is the maximum number of file transfers the server runs at once, 0 means no limit.
+	MaxConcurrentTransfers int64 `yaml:"max_concurrent_transfers" toml:"max_concurrent_transfers"`
+	// MaxBufferedBytes is the maximum number of bytes the running calls hold in memory, 0 means no limit.
+	MaxBufferedBytes int64 `yaml:"max_buffered_bytes" toml:"max_buffered_bytes"`
+	// MaxQueueLength is the maximum number of calls waiting for admission, 0 means no limit.
+	MaxQueueLength int `yaml:"max_queue_length" toml:"max_queue_length"`
+	// QueueTimeout is how long a call waits for admission before it is rejected.
+	QueueTimeout Duration `yaml:"queue_timeout" toml:"queue_timeout"`
+}
+
+// Duration is a time.Duration read from config files as a string like "5s".
+type Duration time.Duration
+
+// UnmarshalText parses a duration like "5s" or "1m30s".
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// String returns the duration formatted like "5s".
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// RateLimitConfig holds the request rate and bandwidth limits, zero values disable the respective limit.
+type RateLimitConfig struct {
+	// RequestsPerSecond is the rate at which a client may send requests.
+	RequestsPerSecond float64 `yaml:"requests_per_second" toml:"requests_per_second"`
+	// RequestBurst is the number of requests a client may send at once, it defaults to the requests per second.
+	RequestBurst int `yaml:"request_burst" toml:"request_burst"`
+	// ClientBandwidth is the number of bytes per second a client may download and upload.
+	ClientBandwidth int64 `yaml:"client_bandwidth" toml:"client_bandwidth"`
+	// GlobalBandwidth is the number of bytes per second all clients together may download and upload.
+	GlobalBandwidth int64 `yaml:"global_bandwidth" toml:"global_bandwidth"`
+}
+
+// Default returns the configuration used for settings that are not configured otherwise.
+func Default() ServerConfig {
+	return ServerConfig{
+		ListenAddress: ":50051",
+		StorageRoot:   "/",
+		LogLevel:      "info",
+		LogFormat:     "text",
+		Tracing:       TracingConfig{Exporter: "none"},
+		Limits:        LimitsConfig{QueueTimeout: Duration(5 * time.Second)},
+	}
+}
+
+// setting describes a setting that can be configured by a flag and an environment variable.
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	set   func(c *ServerConfig, value string) error
+}
+
+// booleanSettings contains the flags of settings that may be given without a value, which means true.
+var booleanSettings = map[string]bool{"s3-path-style": true}
+
+// settingFlag collects the value of a setting given on the command line.
+type settingFlag struct {
+	values map[string]string
+	name   string
+}
+
+// String implements flag.Value.
+func (f settingFlag) String() string {
+	return ""
+}
+
+// Set implements flag.Value.
+func (f settingFlag) Set(value string) error {
+	f.values[f.name] = value
+	return nil
+}
+
+// IsBoolFlag lets the flag package accept boolean settings without a value.
+func (f settingFlag) IsBoolFlag() bool {
+	return booleanSettings[f.name]
+}
+
+// settings contains every setting that can be configured by flags and environment variables.
+var settings = []setting{
+	{"listen", "LISTEN_ADDRESS", "Address the gRPC server listens on", func(c *ServerConfig, v string) error {
+		c.ListenAddress = v
+		return nil
+	}},
+	{"root", "STORAGE_ROOT", "Directory served by the server, a dedup:// prefix stores its files deduplicated by content, s3://bucket/prefix serves an S3 bucket, mem:// keeps the files in memory", func(c *ServerConfig, v string) error {
+		c.StorageRoot = v
+		return nil
+	}},
+	{"s3-endpoint", "S3_ENDPOINT", "URL of the S3-compatible service of an s3:// storage root, AWS S3 if empty", func(c *ServerConfig, v string) error {
+		c.S3.Endpoint = v
+		return nil
+	}},
+	{"s3-region", "S3_REGION", "Region of the bucket of an s3:// storage root, taken from the AWS environment if empty", func(c *ServerConfig, v string) error {
+		c.S3.Region = v
+		return nil
+	}},
+	{"s3-access-key-id", "S3_ACCESS_KEY_ID", "Access key ID of the S3 service, the default AWS credential chain is used if empty", func(c *ServerConfig, v string) error {
+		c.S3.AccessKeyID = v
+		return nil
+	}},
+	{"s3-secret-access-key", "S3_SECRET_ACCESS_KEY", "Secret access key of the S3 service", func(c *ServerConfig, v string) error {
+		c.S3.SecretAccessKey = v
+		return nil
+	}},
+	{"s3-path-style", "S3_PATH_STYLE", "Address the bucket in the URL path instead of the host name, as most S3-compatible services require", func(c *ServerConfig, v string) error {
+		pathStyle, err := strconv.ParseBool(v)
+		c.S3.PathStyle = pathStyle
+		return err
+	}},
+	{"mem-max-file-size", "MEM_MAX_FILE_SIZE", "Maximum size of a file of a mem:// storage root, with an optional K, M or G suffix, 0 means no limit", func(c *ServerConfig, v string) error {
+		size, err := ratelimit.ParseBytes(v)
+		c.Memory.MaxFileSize = size
+		return err
+	}},
+	{"mem-max-size", "MEM_MAX_SIZE", "Maximum size of all files of a mem:// storage root, with an optional K, M or G suffix, 0 means no limit", func(c *ServerConfig, v string) error {
+		size, err := ratelimit.ParseBytes(v)
+		c.Memory.MaxSize = size
+		return err
+	}},
+	{"log-level", "LOG_LEVEL", "Minimum level of logged messages: debug, info, warn or error", func(c *ServerConfig, v string) error {
+		c.LogLevel = v
+		return nil
+	}},
+	{"log-format", "LOG_FORMAT", "Format of logged messages: text or json", func(c *ServerConfig, v string) error {
+		c.LogFormat = v
+		return nil
+	}},
+	{"tls-cert", "TLS_CERT", "Path to the PEM encoded server certificate", func(c *ServerConfig, v string) error {
+		c.TLS.CertFile = v
+		return nil
+	}},
+	{"tls-key", "TLS_KEY", "Path to the PEM encoded server private key", func(c *ServerConfig, v string) error {
+		c.TLS.KeyFile = v
+		return nil
+	}},
+	{"tls-client-ca", "TLS_CLIENT_CA", "Path to a PEM encoded CA bundle, requires clients to present a certificate signed by it (mutual TLS)", func(c *ServerConfig, v string) error {
+		c.TLS.ClientCAFile = v
+		return nil
+	}},
+	{"authz-policy", "AUTHZ_POLICY", "Path to a YAML authorization policy, every caller may perform every operation without one", func(c *ServerConfig, v string) error {
+		c.AuthzPolicy = v
+		return nil
+	}},
+	{"metrics-listen", "METRICS_ADDRESS", "Address of the HTTP server serving Prometheus metrics at /metrics, disabled if empty", func(c *ServerConfig, v string) error {
+		c.MetricsAddress = v
+		return nil
+	}},
+	{"trace-exporter", "TRACE_EXPORTER", "Exporter of OpenTelemetry traces: none, stdout or otlp", func(c *ServerConfig, v string) error {
+		c.Tracing.Exporter = v
+		return nil
+	}},
+	{"otlp-endpoint", "OTLP_ENDPOINT", "Address of the OpenTelemetry collector for the otlp exporter, an http:// prefix disables TLS", func(c *ServerConfig, v string) error {
+		c.Tracing.OTLPEndpoint = v
+		return nil
+	}},
+	{"max-recv-message-size", "MAX_RECV_MESSAGE_SIZE", "Maximum size of a received message in bytes, 0 keeps the gRPC default", func(c *ServerConfig, v string) error {
+		size, err := strconv.Atoi(v)
+		c.Limits.MaxRecvMessageSize = size
+		return err
+	}},
+	{"max-concurrent-streams", "MAX_CONCURRENT_STREAMS", "Maximum number of concurrent calls per client connection, 0 means no limit", func(c *ServerConfig, v string) error {
+		streams, err := strconv.ParseUint(v, 10, 32)
+		c.Limits.MaxConcurrentStreams = uint32(streams)
+		return err
+	}},
+	{"max-concurrent-calls", "MAX_CONCURRENT_CALLS", "Maximum number of calls running at once, further calls are queued, 0 means no limit", func(c *ServerConfig, v string) error {
+		calls, err := strconv.ParseInt(v, 10, 64)
+		c.Limits.MaxConcurrentCalls = calls
+		return err
+	}},
+	{"max-concurrent-transfers", "MAX_CONCURRENT_TRANSFERS", "Maximum number of file transfers running at once, further transfers are queued, 0 means no limit", func(c *ServerConfig, v string) error {
+		transfers, err := strconv.ParseInt(v, 10, 64)
+		c.Limits.MaxConcurrentTransfers = transfers
+		return err
+	}},
+	{"max-buffered-bytes", "MAX_BUFFERED_BYTES", "Maximum number of bytes held in memory by running calls, with an optional K, M or G suffix, 0 means no limit", func(c *ServerConfig, v string) error {
+		size, err := ratelimit.ParseBytes(v)
+		c.Limits.MaxBufferedBytes = size
+		return err
+	}},
+	{"max-queue-length", "MAX_QUEUE_LENGTH", "Maximum number of calls waiting for admission, further calls are rejected, 0 means no limit", func(c *ServerConfig, v string) error {
+		length, err := strconv.Atoi(v)
+		c.Limits.MaxQueueLength = length
+		return err
+	}},
+	{"queue-timeout", "QUEUE_TIMEOUT", "Time a call waits for admission before it is rejected, 5s by default", func(c *ServerConfig, v string) error {
+		return c.Limits.QueueTimeout.UnmarshalText([]byte(v))
+	}},
+	{"requests-per-second", "REQUESTS_PER_SECOND", "Number of requests per second a client may send, identified by its certificate or IP address, 0 means no limit", func(c *ServerConfig, v string) error {
+		rps, err := strconv.ParseFloat(v, 64)
+		c.RateLimit.RequestsPerSecond = rps
+		return err
+	}},
+	{"request-burst", "REQUEST_BURST", "Number of requests a client may send at once, defaults to the requests per second", func(c *ServerConfig, v string) error {
+		burst, err := strconv.Atoi(v)
+		c.RateLimit.RequestBurst = burst
+		return err
+	}},
+	{"client-bandwidth", "CLIENT_BANDWIDTH", "Bytes per second a client may download and upload, with an optional K, M or G suffix, 0 means no limit", func(c *ServerConfig, v string) error {
+		bandwidth, err := ratelimit.ParseBytes(v)
+		c.RateLimit.ClientBandwidth = bandwidth
+		return err
+	}},
+	{"global-bandwidth", "GLOBAL_BANDWIDTH", "Bytes per second all clients together may download and upload, with an optional K, M or G suffix, 0 means no limit", func(c *ServerConfig, v string) error {
+		bandwidth, err := ratelimit.ParseBytes(v)
+		c.RateLimit.GlobalBandwidth = bandwidth
+		return err
+	}},
+}
+
+// Load builds the server configuration from the command-line arguments, the environment and a config file.
+// Settings are taken from the first source defining them in the order: flags, environment variables,
+// config file, defaults. The config file is given by the -config flag or the FILETRANSFER_CONFIG variable
+// and is parsed as TOML if its name ends in .toml and as YAML otherwise.
+// The resulting configuration is validated, every problem found is reported in the returned error.
+func Load(args []string, getenv func(string) string) (*ServerConfig, error) {
+	// Collect the flags first, they are applied last to take precedence
+	flagValues := make(map[string]string)
+	flags := flag.NewFlagSet("server", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	configFile := flags.String("config", "", "Path to a YAML or TOML config file (env "+EnvPrefix+"CONFIG)")
+	for _, s := range settings {
+		flags.Var(settingFlag{values: flagValues, name: s.flag}, s.flag, fmt.Sprintf("%s (env %s%s)", s.usage, EnvPrefix, s.env))
+	}
+	flags.Usage = func() {}
+	if err := flags.Parse(args); err != nil {
+		var usage strings.Builder
+		flags.SetOutput(&usage)
+		flags.PrintDefaults()
+		return nil, fmt.Errorf("%w\n\nFlags:\n%s\nFlags take precedence over environment variables, "+
+			"which take precedence over the config file.", err, usage.String())
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	config := Default()
+
+	if *configFile == "" {
+		*configFile = getenv(EnvPrefix + "CONFIG")
+	}
+	if *configFile != "" {
+		if err := loadFile(*configFile, &config); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if value := getenv(EnvPrefix + s.env); value != "" {
+			if err := s.set(&config, value); err != nil {
+				return nil, fmt.Errorf("invalid value %q for %s%s: %w", value, EnvPrefix, s.env, err)
+			}
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := flagValues[s.flag]; ok {
+			if err := s.set(&config, value); err != nil {
+				return nil, fmt.Errorf("invalid value %q for -%s: %w", value, s.flag, err)
+			}
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// loadFile reads the config file at path into config, rejecting unknown settings.
+func loadFile(path string, config *ServerConfig) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		decoder := toml.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(config)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(config)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate checks the configuration and returns an error describing every invalid setting.
+func (c *ServerConfig) Validate() error {
+	var errs []error
+
+	if err := validateAddress(c.ListenAddress); err != nil {
+		errs = append(errs, fmt.Errorf("listen address %w", err))
+	}
+	if c.MetricsAddress != "" {
+		if err := validateAddress(c.MetricsAddress); err != nil {
+			errs = append(errs, fmt.Errorf("metrics address %w", err))
+		}
+	}
+
+	if c.StorageRoot == "" {
+		errs = append(errs, errors.New("storage root must not be empty"))
+	} else if err := c.validateStorage(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if !logLevels[c.LogLevel] {
+		errs = append(errs, fmt.Errorf("log level %q must be one of debug, info, warn or error", c.LogLevel))
+	}
+
+	if c.LogFormat != "text" && c.LogFormat != "json" {
+		errs = append(errs, fmt.Errorf("log format %q must be text or json", c.LogFormat))
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		errs = append(errs, fmt.Errorf("trace exporter %q must be one of none, stdout or otlp", c.Tracing.Exporter))
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("TLS certificate and key must be configured together"))
+	}
+	if c.TLS.ClientCAFile != "" && c.TLS.CertFile == "" {
+		errs = append(errs, errors.New("TLS client CA requires a TLS certificate and key"))
+	}
+
+	if c.Limits.MaxRecvMessageSize < 0 {
+		errs = append(errs, fmt.Errorf("max receive message size %d must not be negative", c.Limits.MaxRecvMessageSize))
+	}
+	if c.Limits.MaxConcurrentCalls < 0 {
+		errs = append(errs, fmt.Errorf("max concurrent calls %d must not be negative", c.Limits.MaxConcurrentCalls))
+	}
+	if c.Limits.MaxConcurrentTransfers < 0 {
+		errs = append(errs, fmt.Errorf("max concurrent transfers %d must not be negative", c.Limits.MaxConcurrentTransfers))
+	}
+	if c.Limits.MaxBufferedBytes < 0 {
+		errs = append(errs, fmt.Errorf("max buffered bytes %d must not be negative", c.Limits.MaxBufferedBytes))
+	}
+	if c.Limits.MaxQueueLength < 0 {
+		errs = append(errs, fmt.Errorf("max queue length %d must not be negative", c.Limits.MaxQueueLength))
+	}
+	if c.Limits.QueueTimeout < 0 {
+		errs = append(errs, fmt.Errorf("queue timeout %v must not be negative", c.Limits.QueueTimeout))
+	}
+	if c.RateLimit.RequestsPerSecond < 0 {
+		errs = append(errs, fmt.Errorf("requests per second %v must not be negative", c.RateLimit.RequestsPerSecond))
+	}
+	if c.RateLimit.RequestBurst < 0 {
+		errs = append(errs, fmt.Errorf("request burst %d must not be negative", c.RateLimit.RequestBurst))
+	}
+	if c.RateLimit.ClientBandwidth < 0 {
+		errs = append(errs, fmt.Errorf("client bandwidth %d must not be negative", c.RateLimit.ClientBandwidth))
+	}
+	if c.RateLimit.GlobalBandwidth < 0 {
+		errs = append(errs, fmt.Errorf("global bandwidth %d must not be negative", c.RateLimit.GlobalBandwidth))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// StorageBackend returns the storage backend selected by the scheme of the storage root
+// and the location of the storage following the scheme.
+func (c *ServerConfig) StorageBackend() (backend, location string) {
+	scheme, location, found := strings.Cut(c.StorageRoot, "://")
+	if !found {
+		return StorageLocal, c.StorageRoot
+	}
+	return scheme, location
+}
+
+// validateStorage checks that the storage backend is known and its location exists.
+func (c *ServerConfig) validateStorage() error {
+	backend, location := c.StorageBackend()
+	switch backend {
+	case StorageLocal, StorageDedup:
+		if fileInfo, err := os.Stat(location); err != nil {
+			return fmt.Errorf("storage root: %w", err)
+		} else if !fileInfo.IsDir() {
+			return fmt.Errorf("storage root %s is not a directory", location)
+		}
+		return nil
+	case StorageS3:
+		var errs []error
+		if bucket, _, _ := strings.Cut(location, "/"); bucket == "" {
+			errs = append(errs, fmt.Errorf("storage root %s must name a bucket", c.StorageRoot))
+		}
+		if c.S3.Endpoint != "" {
+			if endpoint, err := url.Parse(c.S3.Endpoint); err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+				errs = append(errs, fmt.Errorf("S3 endpoint %q must be an http or https URL", c.S3.Endpoint))
+			}
+		}
+		if (c.S3.AccessKeyID == "") != (c.S3.SecretAccessKey == "") {
+			errs = append(errs, errors.New("S3 access key ID and secret access key must be configured together"))
+		}
+		return errors.Join(errs...)
+	case StorageMemory:
+		var errs []error
+		if location != "" {
+			errs = append(errs, fmt.Errorf("storage root %s must not have a location", c.StorageRoot))
+		}
+		if c.Memory.MaxFileSize < 0 {
+			errs = append(errs, fmt.Errorf("memory max file size %d must not be negative", c.Memory.MaxFileSize))
+		}
+		if c.Memory.MaxSize < 0 {
+			errs = append(errs, fmt.Errorf("memory max size %d must not be negative", c.Memory.MaxSize))
+		}
+		return errors.Join(errs...)
+	default:
+		return fmt.Errorf("storage root %s: unknown storage backend %q", c.StorageRoot, backend)
+	}
+}
+
+// validateAddress checks that address consists of an optional host and a numeric port.
+func validateAddress(address string) error {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%q: %w", address, err)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return fmt.Errorf("%q: invalid port %q", address, port)
+	}
+	return nil
+}
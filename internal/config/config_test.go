@@ -0,0 +1,268 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// env returns a getenv function serving the provided variables.
+func env(vars map[string]string) func(string) string {
+	return func(name string) string {
+		return vars[name]
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	config, err := Load(nil, env(nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, Default(), *config)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	root := t.TempDir()
+	configFile := writeFile(t, "server.yaml", `
+listen_address: 127.0.0.1:7000
+metrics_address: 127.0.0.1:9090
+storage_root: /
+log_level: debug
+log_format: json
+limits:
+  max_recv_message_size: 1048576
+  max_concurrent_streams: 10
+`)
+
+	// The config file overrides the defaults
+	config, err := Load([]string{"-config", configFile}, env(nil))
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:7000", config.ListenAddress)
+	assert.Equal(t, "debug", config.LogLevel)
+	assert.Equal(t, "json", config.LogFormat)
+	assert.Equal(t, "127.0.0.1:9090", config.MetricsAddress)
+	assert.Equal(t, 1048576, config.Limits.MaxRecvMessageSize)
+	assert.Equal(t, uint32(10), config.Limits.MaxConcurrentStreams)
+
+	// Environment variables override the config file
+	vars := map[string]string{
+		"FILETRANSFER_CONFIG":                 configFile,
+		"FILETRANSFER_STORAGE_ROOT":           root,
+		"FILETRANSFER_LOG_LEVEL":              "warn",
+		"FILETRANSFER_MAX_CONCURRENT_STREAMS": "20",
+	}
+	config, err = Load(nil, env(vars))
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:7000", config.ListenAddress)
+	assert.Equal(t, root, config.StorageRoot)
+	assert.Equal(t, "warn", config.LogLevel)
+	assert.Equal(t, uint32(20), config.Limits.MaxConcurrentStreams)
+
+	// Flags override environment variables
+	config, err = Load([]string{"-log-level", "error", "-listen", ":8000"}, env(vars))
+	require.NoError(t, err)
+	assert.Equal(t, ":8000", config.ListenAddress)
+	assert.Equal(t, root, config.StorageRoot)
+	assert.Equal(t, "error", config.LogLevel)
+}
+
+func TestLoad_TOML(t *testing.T) {
+	configFile := writeFile(t, "server.toml", `
+listen_address = ":9000"
+authz_policy = "/etc/filetransfer/policy.yaml"
+
+[tracing]
+exporter = "otlp"
+otlp_endpoint = "http://collector:4317"
+
+[tls]
+cert_file = "server.pem"
+key_file = "server-key.pem"
+
+[limits]
+max_concurrent_transfers = 8
+queue_timeout = "2s"
+`)
+
+	config, err := Load([]string{"-config", configFile}, env(nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, ":9000", config.ListenAddress)
+	assert.Equal(t, "/etc/filetransfer/policy.yaml", config.AuthzPolicy)
+	assert.Equal(t, TLSConfig{CertFile: "server.pem", KeyFile: "server-key.pem"}, config.TLS)
+	assert.Equal(t, TracingConfig{Exporter: "otlp", OTLPEndpoint: "http://collector:4317"}, config.Tracing)
+	assert.Equal(t, LimitsConfig{MaxConcurrentTransfers: 8, QueueTimeout: Duration(2 * time.Second)}, config.Limits)
+}
+
+func TestLoad_Admission(t *testing.T) {
+	configFile := writeFile(t, "server.yaml", `
+limits:
+  max_concurrent_calls: 100
+  max_buffered_bytes: 1073741824
+  queue_timeout: 1m
+`)
+
+	config, err := Load([]string{"-config", configFile, "-max-buffered-bytes", "512M", "-max-queue-length", "50"}, env(map[string]string{"FILETRANSFER_QUEUE_TIMEOUT": "10s"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, LimitsConfig{
+		MaxConcurrentCalls: 100,
+		MaxBufferedBytes:   512 << 20,
+		MaxQueueLength:     50,
+		QueueTimeout:       Duration(10 * time.Second),
+	}, config.Limits)
+}
+
+func TestLoad_RateLimit(t *testing.T) {
+	configFile := writeFile(t, "server.yaml", `
+rate_limit:
+  requests_per_second: 2.5
+  request_burst: 5
+  global_bandwidth: 104857600
+`)
+
+	config, err := Load([]string{"-config", configFile, "-client-bandwidth", "10M"}, env(map[string]string{"FILETRANSFER_REQUEST_BURST": "10"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, RateLimitConfig{
+		RequestsPerSecond: 2.5,
+		RequestBurst:      10,
+		ClientBandwidth:   10 << 20,
+		GlobalBandwidth:   100 << 20,
+	}, config.RateLimit)
+}
+
+func TestLoad_StorageBackend(t *testing.T) {
+	root := t.TempDir()
+
+	// Storage roots without a scheme are served as they are
+	config, err := Load([]string{"-root", root}, env(nil))
+	require.NoError(t, err)
+	backend, location := config.StorageBackend()
+	assert.Equal(t, StorageLocal, backend)
+	assert.Equal(t, root, location)
+
+	config, err = Load(nil, env(map[string]string{"FILETRANSFER_STORAGE_ROOT": "dedup://" + root}))
+	require.NoError(t, err)
+	backend, location = config.StorageBackend()
+	assert.Equal(t, StorageDedup, backend)
+	assert.Equal(t, root, location)
+
+	// S3 buckets are not checked on startup
+	configFile := writeFile(t, "s3.yaml", `
+storage_root: s3://files/prefix
+s3:
+  endpoint: http://127.0.0.1:9000
+  region: eu-central-1
+  access_key_id: access-key
+  path_style: true
+`)
+	config, err = Load([]string{"-config", configFile}, env(map[string]string{"FILETRANSFER_S3_SECRET_ACCESS_KEY": "secret-key"}))
+	require.NoError(t, err)
+	backend, location = config.StorageBackend()
+	assert.Equal(t, StorageS3, backend)
+	assert.Equal(t, "files/prefix", location)
+	assert.Equal(t, S3Config{
+		Endpoint:        "http://127.0.0.1:9000",
+		Region:          "eu-central-1",
+		AccessKeyID:     "access-key",
+		SecretAccessKey: "secret-key",
+		PathStyle:       true,
+	}, config.S3)
+
+	// Boolean flags may be given without a value
+	config, err = Load([]string{"-root", "s3://files", "-s3-path-style", "-s3-region", "eu-central-1"}, env(nil))
+	require.NoError(t, err)
+	assert.True(t, config.S3.PathStyle)
+	assert.Equal(t, "eu-central-1", config.S3.Region)
+	config, err = Load([]string{"-root", "s3://files", "-s3-path-style=false"}, env(map[string]string{"FILETRANSFER_S3_PATH_STYLE": "true"}))
+	require.NoError(t, err)
+	assert.False(t, config.S3.PathStyle)
+
+	// In-memory storage has no location and optional size limits
+	configFile = writeFile(t, "mem.toml", `
+storage_root = "mem://"
+
+[memory]
+max_size = 1073741824
+`)
+	config, err = Load([]string{"-config", configFile, "-mem-max-file-size", "10M"}, env(nil))
+	require.NoError(t, err)
+	backend, location = config.StorageBackend()
+	assert.Equal(t, StorageMemory, backend)
+	assert.Empty(t, location)
+	assert.Equal(t, MemoryConfig{MaxFileSize: 10 << 20, MaxSize: 1 << 30}, config.Memory)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	file := writeFile(t, "file.txt", "content")
+
+	for name, tc := range map[string]struct {
+		args []string
+		vars map[string]string
+	}{
+		"unknown flag":            {args: []string{"-port", "1"}},
+		"unexpected argument":     {args: []string{"serve"}},
+		"invalid number":          {args: []string{"-max-concurrent-streams", "many"}},
+		"invalid env number":      {vars: map[string]string{"FILETRANSFER_MAX_RECV_MESSAGE_SIZE": "1MB"}},
+		"missing config file":     {args: []string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}},
+		"unknown config key":      {args: []string{"-config", writeFile(t, "server.yaml", "port: 50051\n")}},
+		"unknown TOML key":        {args: []string{"-config", writeFile(t, "server.toml", "port = 50051\n")}},
+		"invalid address":         {args: []string{"-listen", "localhost"}},
+		"invalid port":            {args: []string{"-listen", ":http-alt"}},
+		"invalid metrics address": {args: []string{"-metrics-listen", "9090"}},
+		"missing root":            {args: []string{"-root", filepath.Join(t.TempDir(), "missing")}},
+		"root is a file":          {args: []string{"-root", file}},
+		"missing dedup root":      {args: []string{"-root", "dedup://" + filepath.Join(t.TempDir(), "missing")}},
+		"unknown storage backend": {args: []string{"-root", "ftp://" + t.TempDir()}},
+		"missing bucket":          {args: []string{"-root", "s3:///prefix"}},
+		"invalid S3 endpoint":     {args: []string{"-root", "s3://files", "-s3-endpoint", "127.0.0.1:9000"}},
+		"S3 access key only":      {args: []string{"-root", "s3://files", "-s3-access-key-id", "access-key"}},
+		"invalid S3 path style":   {args: []string{"-root", "s3://files", "-s3-path-style=sometimes"}},
+		"memory location":         {args: []string{"-root", "mem:///srv/files"}},
+		"invalid memory size":     {args: []string{"-root", "mem://", "-mem-max-size", "1T"}},
+		"negative memory size":    {args: []string{"-root", "mem://", "-config", writeFile(t, "mem.yaml", "memory:\n  max_file_size: -1\n")}},
+		"invalid log level":       {args: []string{"-log-level", "verbose"}},
+		"invalid trace exporter":  {args: []string{"-trace-exporter", "jaeger"}},
+		"invalid log format":      {args: []string{"-log-format", "xml"}},
+		"certificate only":        {args: []string{"-tls-cert", "server.pem"}},
+		"client CA only":          {args: []string{"-tls-client-ca", "ca.pem"}},
+		"negative message size":   {args: []string{"-max-recv-message-size", "-1"}},
+		"negative call limit":     {args: []string{"-max-concurrent-calls", "-1"}},
+		"invalid queue timeout":   {args: []string{"-queue-timeout", "5"}},
+		"invalid file timeout":    {args: []string{"-config", writeFile(t, "timeout.yaml", "limits:\n  queue_timeout: soon\n")}},
+		"negative queue timeout":  {vars: map[string]string{"FILETRANSFER_QUEUE_TIMEOUT": "-1s"}},
+		"negative request rate":   {args: []string{"-requests-per-second", "-1"}},
+		"negative request burst":  {args: []string{"-request-burst", "-5"}},
+		"invalid bandwidth":       {args: []string{"-client-bandwidth", "10MB/s"}},
+		"negative bandwidth":      {args: []string{"-config", writeFile(t, "limits.yaml", "rate_limit:\n  global_bandwidth: -1\n")}},
+	} {
+		_, err := Load(tc.args, env(tc.vars))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestLoad_ReportsEveryProblem(t *testing.T) {
+	_, err := Load([]string{"-listen", "localhost", "-log-level", "verbose"}, env(nil))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "listen address")
+	assert.Contains(t, err.Error(), "log level")
+}
+
+func TestLoad_Help(t *testing.T) {
+	_, err := Load([]string{"-h"}, env(nil))
+
+	assert.ErrorIs(t, err, flag.ErrHelp)
+	assert.Contains(t, err.Error(), "FILETRANSFER_STORAGE_ROOT")
+}
@@ -0,0 +1,92 @@
+// Package delta implements rsync-style delta transfer: the receiver signs the blocks of its old copy of a file,
+// the sender finds these blocks in the new file with a rolling checksum and describes the new file as copies
+// of old blocks and literal data, from which the receiver rebuilds the new file.
+package delta
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math"
+)
+
+const (
+	// StrongSize is the number of bytes of the SHA-256 hash of a block kept in its signature.
+	StrongSize = 16
+	// MinBlockSize and MaxBlockSize bound the size of the signed blocks.
+	MinBlockSize = 512
+	MaxBlockSize = 8 << 20
+	// MaxBlocks is the largest number of blocks a copy may be split into, so the signatures fit into memory.
+	MaxBlocks = 1 << 20
+)
+
+// ErrInvalidInstruction is returned when an instruction refers to blocks outside of the old file.
+var ErrInvalidInstruction = errors.New("instruction refers to blocks outside of the old file")
+
+// Signature identifies a block of the old file.
+type Signature struct {
+	// Weak is the rolling checksum of the block, cheap to compute at every position of the new file.
+	Weak uint32
+	// Strong is the start of the SHA-256 hash of the block, confirming a match of the weak checksum.
+	Strong [StrongSize]byte
+}
+
+// Instruction is a step of rebuilding the new file: either a run of blocks copied from the old file,
+// if Count is positive, or literal data.
+type Instruction struct {
+	// Index is the first of Count consecutive blocks of the old file.
+	Index uint64
+	Count uint64
+	// Literal is content that is not found in the old file.
+	Literal []byte
+}
+
+// BlockSize returns the block size used to sign a file of the given size: about the square root of the size,
+// which balances the size of the signatures against the size of the literal data sent for changed blocks.
+func BlockSize(size int64) int {
+	blockSize := int(math.Sqrt(float64(size))) &^ 1023
+	switch {
+	case blockSize < 2048:
+		blockSize = 2048
+	case blockSize > 128<<10:
+		blockSize = 128 << 10
+	}
+	for BlockCount(size, blockSize) > MaxBlocks && blockSize < MaxBlockSize {
+		blockSize *= 2
+	}
+	return blockSize
+}
+
+// BlockCount returns the number of blocks a file of the given size is split into, the last one may be shorter.
+func BlockCount(size int64, blockSize int) int64 {
+	return (size + int64(blockSize) - 1) / int64(blockSize)
+}
+
+// strongHash returns the strong hash of a block.
+func strongHash(block []byte) [StrongSize]byte {
+	sum := sha256.Sum256(block)
+	var strong [StrongSize]byte
+	copy(strong[:], sum[:])
+	return strong
+}
+
+// Sign reads the old file from r and calls emit with the signature of every block in order.
+func Sign(r io.Reader, blockSize int, emit func(Signature) error) error {
+	block := make([]byte, blockSize)
+	for {
+		n, err := io.ReadFull(r, block)
+		if n > 0 {
+			var rolling rollingChecksum
+			rolling.init(block[:n])
+			if err := emit(Signature{Weak: rolling.sum(), Strong: strongHash(block[:n])}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
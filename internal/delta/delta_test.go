@@ -0,0 +1,169 @@
+package delta
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sign returns the signatures of the blocks of content.
+func sign(t *testing.T, content []byte, blockSize int) []Signature {
+	var signatures []Signature
+	require.NoError(t, Sign(bytes.NewReader(content), blockSize, func(signature Signature) error {
+		signatures = append(signatures, signature)
+		return nil
+	}))
+	return signatures
+}
+
+// roundTrip rebuilds newContent from oldContent with a delta and returns the instructions and statistics.
+func roundTrip(t *testing.T, oldContent, newContent []byte, blockSize int) ([]Instruction, DiffStats) {
+	index, err := NewIndex(blockSize, int64(len(oldContent)), sign(t, oldContent, blockSize))
+	require.NoError(t, err)
+
+	var rebuilt bytes.Buffer
+	var instructions []Instruction
+	patcher := NewPatcher(bytes.NewReader(oldContent), int64(len(oldContent)), blockSize, &rebuilt)
+	stats, err := Diff(bytes.NewReader(newContent), index, func(instruction Instruction) error {
+		instruction.Literal = append([]byte(nil), instruction.Literal...)
+		instructions = append(instructions, instruction)
+		return patcher.Apply(instruction)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, newContent, rebuilt.Bytes())
+	assert.Equal(t, int64(len(newContent)), stats.Reused+stats.Literal)
+	assert.Equal(t, stats.Reused, patcher.Reused)
+	assert.Equal(t, stats.Literal, patcher.Literal)
+	return instructions, stats
+}
+
+func randomContent(random *rand.Rand, size int) []byte {
+	content := make([]byte, size)
+	random.Read(content)
+	return content
+}
+
+func TestRollingChecksum(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	content := randomContent(random, 4096)
+	const window = 700
+
+	var rolling rollingChecksum
+	rolling.init(content[:window])
+	for i := 1; i+window <= len(content); i++ {
+		rolling.roll(content[i-1], content[i+window-1])
+		var expected rollingChecksum
+		expected.init(content[i : i+window])
+		require.Equal(t, expected.sum(), rolling.sum(), "position %d", i)
+	}
+
+	// Shrinking the window at the end of the content
+	start := len(content) - window
+	for i := start + 1; i < len(content); i++ {
+		rolling.remove(content[i-1])
+		var expected rollingChecksum
+		expected.init(content[i:])
+		require.Equal(t, expected.sum(), rolling.sum(), "position %d", i)
+	}
+}
+
+func TestBlockSize(t *testing.T) {
+	assert.Equal(t, 2048, BlockSize(0))
+	assert.Equal(t, 2048, BlockSize(100<<10))
+	assert.Equal(t, 3072, BlockSize(10<<20))
+	assert.Equal(t, 32<<10, BlockSize(1<<30))
+	assert.Equal(t, 128<<10, BlockSize(100<<30))
+
+	// Huge files get larger blocks to bound the number of signatures
+	size := int64(1) << 40
+	assert.Equal(t, 1<<20, BlockSize(size))
+	assert.LessOrEqual(t, BlockCount(size, BlockSize(size)), int64(MaxBlocks))
+}
+
+func TestSign(t *testing.T) {
+	content := randomContent(rand.New(rand.NewSource(2)), 2500)
+	signatures := sign(t, content, 1024)
+	require.Len(t, signatures, 3)
+	assert.Equal(t, strongHash(content[2048:]), signatures[2].Strong)
+	assert.Empty(t, sign(t, nil, 1024))
+}
+
+func TestDiff_Unchanged(t *testing.T) {
+	content := randomContent(rand.New(rand.NewSource(3)), 10000)
+	instructions, stats := roundTrip(t, content, content, 1024)
+
+	// Consecutive blocks, including the short last block, are copied at once
+	assert.Equal(t, []Instruction{{Index: 0, Count: 10}}, instructions)
+	assert.Equal(t, int64(10000), stats.Reused)
+	assert.Zero(t, stats.Literal)
+}
+
+func TestDiff_Changes(t *testing.T) {
+	random := rand.New(rand.NewSource(4))
+	oldContent := randomContent(random, 200000)
+
+	var newContent []byte
+	newContent = append(newContent, []byte("inserted at the start")...)
+	newContent = append(newContent, oldContent[:50000]...)
+	newContent = append(newContent, randomContent(random, 3000)...)
+	newContent = append(newContent, oldContent[60000:150000]...)
+	newContent = append(newContent, oldContent[10000:20000]...)
+	newContent = append(newContent, oldContent[190000:]...)
+
+	_, stats := roundTrip(t, oldContent, newContent, 1024)
+	assert.Greater(t, stats.Reused, int64(len(newContent)-6*1024))
+	assert.Less(t, stats.Literal, int64(6*1024))
+}
+
+func TestDiff_Unrelated(t *testing.T) {
+	random := rand.New(rand.NewSource(5))
+	oldContent := randomContent(random, 5000)
+	newContent := randomContent(random, 300000)
+
+	instructions, stats := roundTrip(t, oldContent, newContent, 512)
+	assert.Zero(t, stats.Reused)
+
+	// Literals are split to bound the memory
+	for _, instruction := range instructions {
+		assert.LessOrEqual(t, len(instruction.Literal), maxLiteral)
+	}
+}
+
+func TestDiff_EmptyFiles(t *testing.T) {
+	content := randomContent(rand.New(rand.NewSource(6)), 3000)
+	instructions, _ := roundTrip(t, nil, content, 1024)
+	assert.Len(t, instructions, 1)
+
+	instructions, _ = roundTrip(t, content, nil, 1024)
+	assert.Empty(t, instructions)
+}
+
+func TestDiff_RepeatedBlocks(t *testing.T) {
+	oldContent := make([]byte, 8192)
+	newContent := make([]byte, 20000)
+
+	instructions, stats := roundTrip(t, oldContent, newContent, 1024)
+	assert.Equal(t, int64(19456), stats.Reused)
+	assert.Len(t, instructions, 4)
+}
+
+func TestNewIndex_InvalidSignatures(t *testing.T) {
+	signatures := sign(t, make([]byte, 3000), 1024)
+	_, err := NewIndex(1024, 5000, signatures)
+	assert.Error(t, err)
+	_, err = NewIndex(100, 300, signatures)
+	assert.Error(t, err)
+}
+
+func TestPatcher_InvalidInstruction(t *testing.T) {
+	var rebuilt bytes.Buffer
+	patcher := NewPatcher(bytes.NewReader(make([]byte, 3000)), 3000, 1024, &rebuilt)
+	assert.ErrorIs(t, patcher.Apply(Instruction{Index: 3, Count: 1}), ErrInvalidInstruction)
+	assert.ErrorIs(t, patcher.Apply(Instruction{Index: 1, Count: 3}), ErrInvalidInstruction)
+	assert.NoError(t, patcher.Apply(Instruction{Index: 1, Count: 2}))
+	assert.Equal(t, 3000-1024, rebuilt.Len())
+}
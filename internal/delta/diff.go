@@ -0,0 +1,249 @@
+package delta
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// maxLiteral is the largest literal emitted at once, bounding the memory used by Diff.
+const maxLiteral = 64 << 10
+
+// Index looks up the blocks of the old file by their signatures.
+type Index struct {
+	blockSize int
+	// lastSize is the size of the last block, which is shorter than the others if the size of the old file
+	// is not a multiple of the block size.
+	lastSize int
+	// filter has a bit set for the 16 bit hashes of the weak checksums, to skip the map lookup for most positions.
+	filter  []uint64
+	weak    map[uint32][]int
+	strongs [][StrongSize]byte
+}
+
+// NewIndex indexes the signatures of the blocks of an old file of the given size.
+func NewIndex(blockSize int, size int64, signatures []Signature) (*Index, error) {
+	if blockSize < MinBlockSize || blockSize > MaxBlockSize {
+		return nil, errors.New("invalid block size")
+	}
+	if int64(len(signatures)) != BlockCount(size, blockSize) {
+		return nil, errors.New("number of signatures does not match the size of the file")
+	}
+
+	index := &Index{
+		blockSize: blockSize,
+		lastSize:  int(size - int64(len(signatures)-1)*int64(blockSize)),
+		filter:    make([]uint64, 1<<16/64),
+		weak:      make(map[uint32][]int, len(signatures)),
+		strongs:   make([][StrongSize]byte, len(signatures)),
+	}
+	for i, signature := range signatures {
+		key := filterKey(signature.Weak)
+		index.filter[key/64] |= 1 << (key % 64)
+		index.weak[signature.Weak] = append(index.weak[signature.Weak], i)
+		index.strongs[i] = signature.Strong
+	}
+	return index, nil
+}
+
+// filterKey hashes a weak checksum to 16 bits.
+func filterKey(weak uint32) uint32 {
+	return (weak ^ weak>>16) & 0xffff
+}
+
+// find returns the index of a block of the old file with the content of window, preferring the block
+// following the previous match, so that runs of copied blocks are kept together.
+func (index *Index) find(weak uint32, window []byte, next int) (int, bool) {
+	key := filterKey(weak)
+	if index.filter[key/64]&(1<<(key%64)) == 0 {
+		return 0, false
+	}
+	candidates := index.weak[weak]
+	if len(candidates) == 0 {
+		return 0, false
+	}
+
+	last := len(index.strongs) - 1
+	var strong [StrongSize]byte
+	computed := false
+	found := -1
+	for _, candidate := range candidates {
+		// Only the last block may be shorter
+		size := index.blockSize
+		if candidate == last {
+			size = index.lastSize
+		}
+		if len(window) != size {
+			continue
+		}
+		if !computed {
+			strong, computed = strongHash(window), true
+		}
+		if !bytes.Equal(strong[:], index.strongs[candidate][:]) {
+			continue
+		}
+		if candidate == next {
+			return candidate, true
+		}
+		if found < 0 {
+			found = candidate
+		}
+	}
+	return found, found >= 0
+}
+
+// DiffStats counts the bytes of the new file described by Diff.
+type DiffStats struct {
+	// Reused is the number of bytes copied from the old file.
+	Reused int64
+	// Literal is the number of bytes sent as literal data.
+	Literal int64
+}
+
+// Diff reads the new file from r and calls emit with the instructions rebuilding it from the old file described by index.
+// The literal data of an instruction is only valid until emit returns.
+func Diff(r io.Reader, index *Index, emit func(Instruction) error) (DiffStats, error) {
+	d := &differ{
+		r:     r,
+		index: index,
+		emit:  emit,
+		buf:   make([]byte, 0, maxLiteral+2*index.blockSize+1),
+	}
+	err := d.run()
+	return d.stats, err
+}
+
+// differ holds the state of a running Diff.
+type differ struct {
+	r     io.Reader
+	index *Index
+	emit  func(Instruction) error
+	stats DiffStats
+
+	// buf holds the content of the new file from the start of the pending literal at literal,
+	// through the window at pos, up to the data read ahead.
+	buf     []byte
+	literal int
+	pos     int
+	eof     bool
+
+	// copy is a pending run of copied blocks, extended while consecutive blocks match.
+	copy Instruction
+}
+
+// run slides the window over the new file.
+func (d *differ) run() error {
+	blockSize := d.index.blockSize
+	var rolling rollingChecksum
+	valid := false
+	for {
+		// The window and the byte after it are needed to roll the checksum
+		if len(d.buf)-d.pos <= blockSize && !d.eof {
+			if err := d.fill(); err != nil {
+				return err
+			}
+		}
+		n := len(d.buf) - d.pos
+		if n > blockSize {
+			n = blockSize
+		}
+		if n == 0 {
+			break
+		}
+		window := d.buf[d.pos : d.pos+n]
+		if !valid {
+			rolling.init(window)
+			valid = true
+		}
+
+		if block, ok := d.index.find(rolling.sum(), window, int(d.copy.Index+d.copy.Count)); ok {
+			if err := d.flushLiteral(); err != nil {
+				return err
+			}
+			if err := d.addCopy(block); err != nil {
+				return err
+			}
+			d.pos += n
+			d.literal = d.pos
+			d.stats.Reused += int64(n)
+			valid = false
+			continue
+		}
+
+		// The byte at the start of the window becomes literal data
+		if d.pos+n < len(d.buf) {
+			rolling.roll(d.buf[d.pos], d.buf[d.pos+n])
+		} else {
+			rolling.remove(d.buf[d.pos])
+		}
+		d.pos++
+		if d.pos-d.literal >= maxLiteral {
+			if err := d.flushLiteral(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := d.flushLiteral(); err != nil {
+		return err
+	}
+	return d.flushCopy()
+}
+
+// fill discards the data before the pending literal and reads ahead.
+func (d *differ) fill() error {
+	if d.literal > 0 {
+		d.buf = d.buf[:copy(d.buf, d.buf[d.literal:])]
+		d.pos -= d.literal
+		d.literal = 0
+	}
+	for len(d.buf) < cap(d.buf) {
+		n, err := d.r.Read(d.buf[len(d.buf):cap(d.buf)])
+		d.buf = d.buf[:len(d.buf)+n]
+		if err == io.EOF {
+			d.eof = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addCopy adds a matched block to the pending run of copied blocks.
+func (d *differ) addCopy(block int) error {
+	if d.copy.Count > 0 && uint64(block) == d.copy.Index+d.copy.Count {
+		d.copy.Count++
+		return nil
+	}
+	if err := d.flushCopy(); err != nil {
+		return err
+	}
+	d.copy = Instruction{Index: uint64(block), Count: 1}
+	return nil
+}
+
+// flushCopy emits the pending run of copied blocks.
+func (d *differ) flushCopy() error {
+	if d.copy.Count == 0 {
+		return nil
+	}
+	copied := d.copy
+	d.copy = Instruction{}
+	return d.emit(copied)
+}
+
+// flushLiteral emits the data before the window that did not match a block.
+func (d *differ) flushLiteral() error {
+	if d.pos == d.literal {
+		return nil
+	}
+	if err := d.flushCopy(); err != nil {
+		return err
+	}
+	literal := d.buf[d.literal:d.pos]
+	d.literal = d.pos
+	d.stats.Literal += int64(len(literal))
+	return d.emit(Instruction{Literal: literal})
+}
@@ -0,0 +1,50 @@
+package delta
+
+import (
+	"io"
+)
+
+// Patcher rebuilds the new file from instructions, reading the copied blocks from the old file.
+type Patcher struct {
+	base      io.ReaderAt
+	baseSize  int64
+	blockSize int
+	w         io.Writer
+
+	// Reused is the number of bytes copied from the old file so far.
+	Reused int64
+	// Literal is the number of bytes of literal data written so far.
+	Literal int64
+}
+
+// NewPatcher returns a Patcher writing the new file to w, copying blocks of the given size from the old file base.
+func NewPatcher(base io.ReaderAt, baseSize int64, blockSize int, w io.Writer) *Patcher {
+	return &Patcher{base: base, baseSize: baseSize, blockSize: blockSize, w: w}
+}
+
+// Apply writes the content described by an instruction.
+func (p *Patcher) Apply(instruction Instruction) error {
+	if instruction.Count == 0 {
+		n, err := p.w.Write(instruction.Literal)
+		p.Literal += int64(n)
+		return err
+	}
+
+	// The last block of the old file may be shorter than the block size
+	blockCount := uint64(BlockCount(p.baseSize, p.blockSize))
+	if instruction.Index >= blockCount || instruction.Count > blockCount-instruction.Index {
+		return ErrInvalidInstruction
+	}
+	offset := int64(instruction.Index) * int64(p.blockSize)
+	length := int64(instruction.Count) * int64(p.blockSize)
+	if offset+length > p.baseSize {
+		length = p.baseSize - offset
+	}
+
+	n, err := io.Copy(p.w, io.NewSectionReader(p.base, offset, length))
+	p.Reused += n
+	if err == nil && n < length {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
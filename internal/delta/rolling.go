@@ -0,0 +1,35 @@
+package delta
+
+// rollingChecksum is the weak checksum of rsync over a window of bytes. Moving the window by a byte
+// updates it in constant time, so it can be computed at every position of a file.
+type rollingChecksum struct {
+	a, b uint32
+	n    uint32
+}
+
+// init computes the checksum of the window.
+func (r *rollingChecksum) init(window []byte) {
+	r.a, r.b, r.n = 0, 0, uint32(len(window))
+	for i, x := range window {
+		r.a += uint32(x)
+		r.b += uint32(len(window)-i) * uint32(x)
+	}
+}
+
+// roll moves the window by a byte, removing out at its start and adding in at its end.
+func (r *rollingChecksum) roll(out, in byte) {
+	r.a += uint32(in) - uint32(out)
+	r.b += r.a - r.n*uint32(out)
+}
+
+// remove shrinks the window by removing out at its start, which happens at the end of a file.
+func (r *rollingChecksum) remove(out byte) {
+	r.a -= uint32(out)
+	r.b -= r.n * uint32(out)
+	r.n--
+}
+
+// sum returns the checksum of the window.
+func (r *rollingChecksum) sum() uint32 {
+	return r.a&0xffff | r.b<<16
+}
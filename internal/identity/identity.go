@@ -0,0 +1,49 @@
+package identity
+
+import (
+	"context"
+	"crypto/x509/pkix"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Identity describes the caller of a gRPC method.
+// The zero value is the anonymous identity of callers that did not authenticate.
+type Identity struct {
+	// User is the name of the caller.
+	User string
+	// Groups are the names of the groups the caller belongs to.
+	Groups []string
+}
+
+// FromContext returns the identity of the gRPC peer of the context.
+// The user is the common name and the groups are the organizational units of the verified client certificate.
+// Callers without a verified client certificate get the anonymous identity.
+func FromContext(ctx context.Context) Identity {
+	subject, ok := ClientCertificateSubject(ctx)
+	if !ok {
+		return Identity{}
+	}
+
+	return Identity{
+		User:   subject.CommonName,
+		Groups: subject.OrganizationalUnit,
+	}
+}
+
+// ClientCertificateSubject returns the subject of the verified client certificate presented by the gRPC peer
+// of the context. The second return value is false if the peer did not authenticate with a client certificate.
+func ClientCertificateSubject(ctx context.Context) (pkix.Name, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return pkix.Name{}, false
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return pkix.Name{}, false
+	}
+
+	return tlsInfo.State.VerifiedChains[0][0].Subject, true
+}
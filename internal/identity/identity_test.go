@@ -0,0 +1,49 @@
+package identity
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientCertificateSubject(t *testing.T) {
+	subject := pkix.Name{CommonName: "alice", OrganizationalUnit: []string{"developers"}}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: subject}}},
+		}},
+	})
+
+	got, ok := ClientCertificateSubject(ctx)
+
+	assert.True(t, ok)
+	assert.Equal(t, subject, got)
+}
+
+func TestClientCertificateSubject_Unauthenticated(t *testing.T) {
+	_, ok := ClientCertificateSubject(context.Background())
+	assert.False(t, ok)
+
+	// TLS connection without a verified client certificate
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{}})
+	_, ok = ClientCertificateSubject(ctx)
+	assert.False(t, ok)
+}
+
+func TestFromContext(t *testing.T) {
+	subject := pkix.Name{CommonName: "alice", OrganizationalUnit: []string{"developers", "ci"}}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: subject}}},
+		}},
+	})
+
+	assert.Equal(t, Identity{User: "alice", Groups: []string{"developers", "ci"}}, FromContext(ctx))
+	assert.Equal(t, Identity{}, FromContext(context.Background()))
+}
@@ -1,7 +1,13 @@
 package logger
 
+import (
+	"context"
+	"log/slog"
+)
+
 // ClientLogger is an interface for logging client-related messages.
 type ClientLogger interface {
-	// Printf logs a formatted message with optional parameters.
-	Printf(format string, v ...interface{})
+	// Log logs a message at the given level with alternating key-value attributes.
+	// Loggers created with New add the request ID carried by ctx to the message.
+	Log(ctx context.Context, level slog.Level, msg string, args ...any)
 }
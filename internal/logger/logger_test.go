@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatJSON, "info")
+	require.NoError(t, err)
+
+	ctx := ContextWithRequestID(context.Background(), "abc123")
+	logger.Log(ctx, slog.LevelInfo, "gRPC call finished", "method", "/api.FileTransfer/GetFileInfo", "bytes", 42)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "gRPC call finished", record["msg"])
+	assert.Equal(t, "/api.FileTransfer/GetFileInfo", record["method"])
+	assert.Equal(t, float64(42), record["bytes"])
+	assert.Equal(t, "abc123", record["request_id"])
+}
+
+func TestNew_Text(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatText, "warn")
+	require.NoError(t, err)
+
+	// Messages below the level are dropped
+	logger.Log(context.Background(), slog.LevelInfo, "dropped")
+	logger.With("component", "server").Log(ContextWithRequestID(context.Background(), "abc123"), slog.LevelWarn, "Access denied", "user", "bob")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `level=WARN msg="Access denied" component=server user=bob request_id=abc123`)
+}
+
+func TestNew_Invalid(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, "xml", "info")
+	assert.Error(t, err)
+
+	_, err = New(&bytes.Buffer{}, FormatText, "verbose")
+	assert.Error(t, err)
+}
+
+func TestRequestID(t *testing.T) {
+	assert.Empty(t, RequestID(context.Background()))
+
+	id := NewRequestID()
+	assert.Len(t, id, 32)
+	assert.NotEqual(t, id, NewRequestID())
+	assert.Equal(t, id, RequestID(ContextWithRequestID(context.Background(), id)))
+}
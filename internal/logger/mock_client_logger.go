@@ -9,6 +9,8 @@
 package logger
 
 import (
+	context "context"
+	slog "log/slog"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -37,19 +39,19 @@ func (m *MockClientLogger) EXPECT() *MockClientLoggerMockRecorder {
 	return m.recorder
 }
 
-// Printf mocks base method.
-func (m *MockClientLogger) Printf(format string, v ...any) {
+// Log mocks base method.
+func (m *MockClientLogger) Log(ctx context.Context, level slog.Level, msg string, args ...any) {
 	m.ctrl.T.Helper()
-	varargs := []any{format}
-	for _, a := range v {
+	varargs := []any{ctx, level, msg}
+	for _, a := range args {
 		varargs = append(varargs, a)
 	}
-	m.ctrl.Call(m, "Printf", varargs...)
+	m.ctrl.Call(m, "Log", varargs...)
 }
 
-// Printf indicates an expected call of Printf.
-func (mr *MockClientLoggerMockRecorder) Printf(format any, v ...any) *gomock.Call {
+// Log indicates an expected call of Log.
+func (mr *MockClientLoggerMockRecorder) Log(ctx, level, msg any, args ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{format}, v...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Printf", reflect.TypeOf((*MockClientLogger)(nil).Printf), varargs...)
+	varargs := append([]any{ctx, level, msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Log", reflect.TypeOf((*MockClientLogger)(nil).Log), varargs...)
 }
@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+)
+
+// namespace prefixes the names of all metrics of the file transfer server.
+const namespace = "filetransfer"
+
+// ServerMetrics collects the metrics of the file transfer server and serves them in the Prometheus text format.
+type ServerMetrics struct {
+	registry         *prometheus.Registry
+	requests         *prometheus.CounterVec
+	duration         *prometheus.HistogramVec
+	bytesSent        *prometheus.CounterVec
+	bytesReceived    *prometheus.CounterVec
+	transfers        *prometheus.GaugeVec
+	repositoryErrors *prometheus.CounterVec
+	bytesSaved       *prometheus.CounterVec
+	deltaSaved       prometheus.Counter
+	queued           *prometheus.GaugeVec
+	rejections       *prometheus.CounterVec
+}
+
+// NewServerMetrics creates the metrics of the file transfer server in a new registry,
+// together with the Go runtime and process metrics.
+func NewServerMetrics() *ServerMetrics {
+	m := &ServerMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "Number of finished gRPC calls by method and status code.",
+		}, []string{"method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Duration of finished gRPC calls by method.",
+			// Transfers of large files take minutes, so the buckets range from 1ms to about 4 minutes
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+		}, []string{"method"}),
+		bytesSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_sent_bytes_total",
+			Help:      "Size of the messages sent to clients by method.",
+		}, []string{"method"}),
+		bytesReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_received_bytes_total",
+			Help:      "Size of the messages received from clients by method.",
+		}, []string{"method"}),
+		transfers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "transfers_in_flight",
+			Help:      "Number of streaming transfers in progress by method.",
+		}, []string{"method"}),
+		repositoryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repository_errors_total",
+			Help:      "Number of errors returned by the file repository by operation.",
+		}, []string{"operation"}),
+		bytesSaved: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "compression_saved_bytes_total",
+			Help:      "Number of message bytes saved by compression by method.",
+		}, []string{"method"}),
+		deltaSaved: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "delta_saved_bytes_total",
+			Help:      "Number of file bytes not sent because delta downloads reused them from the copy of the client.",
+		}),
+		queued: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "admission_queue_length",
+			Help:      "Number of calls waiting for admission by limited resource.",
+		}, []string{"resource"}),
+		rejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "admission_rejections_total",
+			Help:      "Number of calls rejected by admission control by limited resource and reason.",
+		}, []string{"resource", "reason"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.duration,
+		m.bytesSent,
+		m.bytesReceived,
+		m.transfers,
+		m.repositoryErrors,
+		m.bytesSaved,
+		m.deltaSaved,
+		m.queued,
+		m.rejections,
+	)
+	return m
+}
+
+// Handler returns an HTTP handler serving the collected metrics in the Prometheus text format.
+func (m *ServerMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Registry returns the registry holding the collected metrics.
+func (m *ServerMetrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// ObserveCall records a finished call of the gRPC method with its status code and duration.
+func (m *ServerMetrics) ObserveCall(method string, code codes.Code, duration time.Duration) {
+	m.requests.WithLabelValues(method, code.String()).Inc()
+	m.duration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+// AddBytesSent records n bytes sent to a client by the gRPC method.
+func (m *ServerMetrics) AddBytesSent(method string, n int) {
+	m.bytesSent.WithLabelValues(method).Add(float64(n))
+}
+
+// AddBytesReceived records n bytes received from a client by the gRPC method.
+func (m *ServerMetrics) AddBytesReceived(method string, n int) {
+	m.bytesReceived.WithLabelValues(method).Add(float64(n))
+}
+
+// TransferStarted records the start of a streaming transfer of the gRPC method.
+func (m *ServerMetrics) TransferStarted(method string) {
+	m.transfers.WithLabelValues(method).Inc()
+}
+
+// TransferFinished records the end of a streaming transfer of the gRPC method.
+func (m *ServerMetrics) TransferFinished(method string) {
+	m.transfers.WithLabelValues(method).Dec()
+}
+
+// AddBytesSaved records n message bytes saved by compressing the messages of the gRPC method.
+// Negative savings of messages made larger by compression cannot be added to a counter and are ignored.
+func (m *ServerMetrics) AddBytesSaved(method string, n int64) {
+	if n > 0 {
+		m.bytesSaved.WithLabelValues(method).Add(float64(n))
+	}
+}
+
+// AddDeltaBytesSaved records n file bytes a delta download reused from the copy of the client instead of sending them.
+func (m *ServerMetrics) AddDeltaBytesSaved(n int64) {
+	m.deltaSaved.Add(float64(n))
+}
+
+// RepositoryError records an error returned by the file repository operation.
+func (m *ServerMetrics) RepositoryError(operation string) {
+	m.repositoryErrors.WithLabelValues(operation).Inc()
+}
+
+// AdmissionQueued records a call starting to wait for the limited resource.
+func (m *ServerMetrics) AdmissionQueued(resource string) {
+	m.queued.WithLabelValues(resource).Inc()
+}
+
+// AdmissionDequeued records a call no longer waiting for the limited resource, admitted or not.
+func (m *ServerMetrics) AdmissionDequeued(resource string) {
+	m.queued.WithLabelValues(resource).Dec()
+}
+
+// AdmissionRejected records a call rejected because the limited resource was not available for the reason.
+func (m *ServerMetrics) AdmissionRejected(resource, reason string) {
+	m.rejections.WithLabelValues(resource, reason).Inc()
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"filetransfer/internal/repository"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+)
+
+func TestServerMetrics(t *testing.T) {
+	m := NewServerMetrics()
+	method := "/api.FileTransfer/DownloadFile"
+
+	m.TransferStarted(method)
+	m.AddBytesReceived(method, 12)
+	m.AddBytesSent(method, 1024)
+	m.AddBytesSent(method, 40)
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.transfers.WithLabelValues(method)))
+
+	m.TransferFinished(method)
+	m.ObserveCall(method, codes.OK, 2*time.Second)
+	m.ObserveCall(method, codes.NotFound, time.Millisecond)
+
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.transfers.WithLabelValues(method)))
+	assert.Equal(t, float64(12), testutil.ToFloat64(m.bytesReceived.WithLabelValues(method)))
+	assert.Equal(t, float64(1064), testutil.ToFloat64(m.bytesSent.WithLabelValues(method)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues(method, "OK")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues(method, "NotFound")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.duration))
+
+	m.AddDeltaBytesSaved(4096)
+	assert.Equal(t, float64(4096), testutil.ToFloat64(m.deltaSaved))
+}
+
+func TestServerMetrics_Handler(t *testing.T) {
+	m := NewServerMetrics()
+	m.ObserveCall("/api.FileTransfer/GetFileInfo", codes.OK, time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	require.NoError(t, err)
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, string(body), `filetransfer_grpc_requests_total{code="OK",method="/api.FileTransfer/GetFileInfo"} 1`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
+
+func TestInstrumentRepository(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	m := NewServerMetrics()
+	fileRepository := m.InstrumentRepository(mockRepo)
+
+	mockRepo.EXPECT().GetFileContent(gomock.Any(), "file.txt").Return([]byte("content"), nil)
+	mockRepo.EXPECT().GetFileContent(gomock.Any(), "missing.txt").Return(nil, errors.New("file not found"))
+	mockRepo.EXPECT().CopyFile(gomock.Any(), "a", "b", false).Return(int64(0), errors.New("file exists"))
+	mockRepo.EXPECT().GetFileList(gomock.Any(), "", 0).Return(nil, context.Canceled)
+
+	content, err := fileRepository.GetFileContent(context.Background(), "file.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("content"), content)
+
+	_, err = fileRepository.GetFileContent(context.Background(), "missing.txt")
+	assert.Error(t, err)
+	_, err = fileRepository.CopyFile(context.Background(), "a", "b", false)
+	assert.Error(t, err)
+
+	// Canceled calls are no storage errors
+	_, err = fileRepository.GetFileList(context.Background(), "", 0)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The write buffer size of a repository not reporting one is zero
+	assert.Equal(t, int64(0), fileRepository.(repository.WriteBufferSizer).WriteBufferSize())
+
+	expected := `
+# HELP filetransfer_repository_errors_total Number of errors returned by the file repository by operation.
+# TYPE filetransfer_repository_errors_total counter
+filetransfer_repository_errors_total{operation="CopyFile"} 1
+filetransfer_repository_errors_total{operation="GetFileContent"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(m.Registry(), strings.NewReader(expected), "filetransfer_repository_errors_total"))
+}
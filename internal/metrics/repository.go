@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"filetransfer/api"
+	"filetransfer/internal/repository"
+	"io"
+)
+
+// instrumentedRepository wraps a repository.FileRepository and counts the errors it returns.
+type instrumentedRepository struct {
+	repository repository.FileRepository
+	metrics    *ServerMetrics
+}
+
+// InstrumentRepository returns a repository.FileRepository delegating to fileRepository
+// and counting the errors it returns by operation.
+func (m *ServerMetrics) InstrumentRepository(fileRepository repository.FileRepository) repository.FileRepository {
+	return &instrumentedRepository{repository: fileRepository, metrics: m}
+}
+
+// observe counts err for the operation if it is not nil and returns it.
+// Canceled and timed out operations are not counted, since they are no failures of the storage.
+func (r *instrumentedRepository) observe(operation string, err error) error {
+	if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		r.metrics.RepositoryError(operation)
+	}
+	return err
+}
+
+func (r *instrumentedRepository) GetFileList(ctx context.Context, dir string, maxDepth int) ([]*api.FileEntry, error) {
+	entries, err := r.repository.GetFileList(ctx, dir, maxDepth)
+	return entries, r.observe("GetFileList", err)
+}
+
+func (r *instrumentedRepository) GetFileInfo(ctx context.Context, filename string) (interface{}, error) {
+	info, err := r.repository.GetFileInfo(ctx, filename)
+	return info, r.observe("GetFileInfo", err)
+}
+
+func (r *instrumentedRepository) GetFileContent(ctx context.Context, filename string) ([]byte, error) {
+	content, err := r.repository.GetFileContent(ctx, filename)
+	return content, r.observe("GetFileContent", err)
+}
+
+func (r *instrumentedRepository) GetFileReader(ctx context.Context, filename string, offset, length int64) (io.ReadCloser, error) {
+	reader, err := r.repository.GetFileReader(ctx, filename, offset, length)
+	return reader, r.observe("GetFileReader", err)
+}
+
+func (r *instrumentedRepository) GetFileWriter(ctx context.Context, filename string) (repository.FileWriter, error) {
+	writer, err := r.repository.GetFileWriter(ctx, filename)
+	return writer, r.observe("GetFileWriter", err)
+}
+
+func (r *instrumentedRepository) DeleteFile(ctx context.Context, filename string, recursive bool) error {
+	return r.observe("DeleteFile", r.repository.DeleteFile(ctx, filename, recursive))
+}
+
+func (r *instrumentedRepository) RenameFile(ctx context.Context, source, destination string, overwrite bool) error {
+	return r.observe("RenameFile", r.repository.RenameFile(ctx, source, destination, overwrite))
+}
+
+func (r *instrumentedRepository) CopyFile(ctx context.Context, source, destination string, overwrite bool) (int64, error) {
+	written, err := r.repository.CopyFile(ctx, source, destination, overwrite)
+	return written, r.observe("CopyFile", err)
+}
+
+func (r *instrumentedRepository) MakeDirectory(ctx context.Context, path string, parents bool) error {
+	return r.observe("MakeDirectory", r.repository.MakeDirectory(ctx, path, parents))
+}
+
+func (r *instrumentedRepository) Probe(ctx context.Context) error {
+	return r.observe("Probe", r.repository.Probe(ctx))
+}
+
+// ResolveLinks resolves the symbolic links on a path with the wrapped repository,
+// a repository without symbolic links returns the path unchanged.
+func (r *instrumentedRepository) ResolveLinks(ctx context.Context, name string) (string, error) {
+	if resolver, ok := r.repository.(repository.LinkResolver); ok {
+		resolved, err := resolver.ResolveLinks(ctx, name)
+		return resolved, r.observe("ResolveLinks", err)
+	}
+	return name, nil
+}
+
+// WriteBufferSize returns the write buffer size of the wrapped repository, zero if it does not report one.
+func (r *instrumentedRepository) WriteBufferSize() int64 {
+	if sizer, ok := r.repository.(repository.WriteBufferSizer); ok {
+		return sizer.WriteBufferSize()
+	}
+	return 0
+}
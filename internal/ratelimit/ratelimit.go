@@ -0,0 +1,195 @@
+package ratelimit
+
+import (
+	"context"
+	"filetransfer/internal/identity"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/peer"
+)
+
+// idleTimeout is how long the limits of a client are kept after its last request.
+// A client returning later starts with full buckets again.
+const idleTimeout = 10 * time.Minute
+
+// Config holds the limits enforced by a Limiter, zero values disable the respective limit.
+type Config struct {
+	// RequestsPerSecond is the rate at which a client may send requests.
+	RequestsPerSecond float64
+	// RequestBurst is the number of requests a client may send at once, it defaults to the requests per second.
+	RequestBurst int
+	// ClientBytesPerSecond is the bandwidth available to the transfers of a client.
+	ClientBytesPerSecond int64
+	// GlobalBytesPerSecond is the bandwidth shared by the transfers of all clients.
+	GlobalBytesPerSecond int64
+}
+
+// Limiter limits the request rate and the transfer bandwidth of every client with token buckets,
+// and the bandwidth of all clients together. Clients are told apart by ClientKey.
+// It is safe for concurrent use.
+type Limiter struct {
+	config Config
+	global *rate.Limiter
+	now    func() time.Time
+
+	mu        sync.Mutex
+	clients   map[string]*clientLimits
+	lastPrune time.Time
+}
+
+// clientLimits holds the token buckets of a single client.
+type clientLimits struct {
+	requests  *rate.Limiter
+	bandwidth *rate.Limiter
+	lastSeen  time.Time
+}
+
+// New creates a Limiter enforcing the provided limits.
+func New(config Config) *Limiter {
+	if config.RequestsPerSecond > 0 && config.RequestBurst <= 0 {
+		config.RequestBurst = int(math.Max(1, math.Ceil(config.RequestsPerSecond)))
+	}
+
+	return &Limiter{
+		config:  config,
+		global:  NewBandwidthLimiter(config.GlobalBytesPerSecond),
+		now:     time.Now,
+		clients: make(map[string]*clientLimits),
+	}
+}
+
+// AllowRequest takes a token from the request bucket of the client. If the bucket is empty,
+// it returns false and the time after which the request would be allowed.
+func (l *Limiter) AllowRequest(key string) (bool, time.Duration) {
+	if l.config.RequestsPerSecond <= 0 {
+		return true, 0
+	}
+
+	now := l.now()
+	reservation := l.client(key, now).requests.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// Rejected requests must not use up the tokens of later requests
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// WaitBytes blocks until the client may transfer n more bytes within its own and the global bandwidth.
+// It returns an error if ctx is done first.
+func (l *Limiter) WaitBytes(ctx context.Context, key string, n int) error {
+	if l.config.ClientBytesPerSecond > 0 {
+		if err := WaitBytes(ctx, l.client(key, l.now()).bandwidth, n); err != nil {
+			return err
+		}
+	}
+	return WaitBytes(ctx, l.global, n)
+}
+
+// client returns the limits of the client, creating them on first use.
+// Limits of clients that have been idle for a while are dropped, so the map does not grow without bounds.
+func (l *Limiter) client(key string, now time.Time) *clientLimits {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastPrune) > idleTimeout {
+		for k, c := range l.clients {
+			if now.Sub(c.lastSeen) > idleTimeout {
+				delete(l.clients, k)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &clientLimits{
+			requests:  rate.NewLimiter(rate.Limit(l.config.RequestsPerSecond), l.config.RequestBurst),
+			bandwidth: NewBandwidthLimiter(l.config.ClientBytesPerSecond),
+		}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	return c
+}
+
+// ClientKey returns the key a client is limited by: the user of its verified client certificate,
+// or its IP address if it did not authenticate. All connections of a client share its limits.
+func ClientKey(ctx context.Context) string {
+	if id := identity.FromContext(ctx); id.User != "" {
+		return "user:" + id.User
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	return "ip:" + host
+}
+
+// NewBandwidthLimiter returns a token bucket allowing bytesPerSecond bytes per second with a burst of one second,
+// or nil if bytesPerSecond is not positive.
+func NewBandwidthLimiter(bytesPerSecond int64) *rate.Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	burst := bytesPerSecond
+	if burst > math.MaxInt32 {
+		burst = math.MaxInt32
+	}
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), int(burst))
+}
+
+// WaitBytes blocks until the bandwidth limiter allows n bytes, waiting for at most a burst at a time.
+// A nil limiter allows everything. It returns an error if ctx is done first.
+func WaitBytes(ctx context.Context, limiter *rate.Limiter, n int) error {
+	if limiter == nil {
+		return nil
+	}
+
+	for n > 0 {
+		take := n
+		if burst := limiter.Burst(); take > burst {
+			take = burst
+		}
+		if err := limiter.WaitN(ctx, take); err != nil {
+			return err
+		}
+		n -= take
+	}
+	return nil
+}
+
+// byteUnits contains the accepted unit suffixes of ParseBytes and their multipliers.
+var byteUnits = map[string]int64{"": 1, "K": 1 << 10, "M": 1 << 20, "G": 1 << 30}
+
+// ParseBytes parses a number of bytes with an optional K, M or G suffix for KiB, MiB and GiB, for example "512K".
+func ParseBytes(s string) (int64, error) {
+	number := strings.TrimSpace(s)
+	unit := ""
+	if n := len(number); n > 0 {
+		if last := strings.ToUpper(number[n-1:]); byteUnits[last] > 1 {
+			number, unit = number[:n-1], last
+		}
+	}
+
+	value, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid number of bytes %q, expected a number with an optional K, M or G suffix", s)
+	}
+	if value > math.MaxInt64/byteUnits[unit] {
+		return 0, fmt.Errorf("number of bytes %q is too large", s)
+	}
+	return value * byteUnits[unit], nil
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/peer"
+)
+
+func TestLimiter_AllowRequest(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := New(Config{RequestsPerSecond: 2, RequestBurst: 3})
+	limiter.now = func() time.Time { return now }
+
+	// The burst is allowed at once
+	for i := 0; i < 3; i++ {
+		allowed, _ := limiter.AllowRequest("ip:10.0.0.1")
+		assert.True(t, allowed)
+	}
+	allowed, retryAfter := limiter.AllowRequest("ip:10.0.0.1")
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// Other clients have their own bucket
+	allowed, _ = limiter.AllowRequest("ip:10.0.0.2")
+	assert.True(t, allowed)
+
+	// Rejected requests do not use up tokens, one token is back after half a second
+	now = now.Add(500 * time.Millisecond)
+	allowed, _ = limiter.AllowRequest("ip:10.0.0.1")
+	assert.True(t, allowed)
+	allowed, _ = limiter.AllowRequest("ip:10.0.0.1")
+	assert.False(t, allowed)
+}
+
+func TestLimiter_AllowRequestUnlimited(t *testing.T) {
+	limiter := New(Config{})
+	for i := 0; i < 1000; i++ {
+		allowed, _ := limiter.AllowRequest("ip:10.0.0.1")
+		require.True(t, allowed)
+	}
+}
+
+func TestLimiter_DropsIdleClients(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := New(Config{RequestsPerSecond: 1})
+	limiter.now = func() time.Time { return now }
+
+	limiter.AllowRequest("ip:10.0.0.1")
+	now = now.Add(2 * idleTimeout)
+	limiter.AllowRequest("ip:10.0.0.2")
+
+	assert.Len(t, limiter.clients, 1)
+	assert.Contains(t, limiter.clients, "ip:10.0.0.2")
+}
+
+func TestLimiter_WaitBytes(t *testing.T) {
+	limiter := New(Config{ClientBytesPerSecond: 1000, GlobalBytesPerSecond: 1 << 20})
+
+	// The first second is available at once, the next 500 bytes take half a second
+	start := time.Now()
+	require.NoError(t, limiter.WaitBytes(context.Background(), "ip:10.0.0.1", 1000))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	require.NoError(t, limiter.WaitBytes(context.Background(), "ip:10.0.0.1", 500))
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+
+	// Waiting stops when the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, limiter.WaitBytes(ctx, "ip:10.0.0.1", 2000))
+}
+
+func TestWaitBytes_LargerThanBurst(t *testing.T) {
+	limiter := NewBandwidthLimiter(100 * 1000)
+
+	// 150 KB are taken in bursts of 100 KB
+	start := time.Now()
+	require.NoError(t, WaitBytes(context.Background(), limiter, 150*1000))
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+
+	assert.NoError(t, WaitBytes(context.Background(), nil, 1<<30))
+}
+
+func TestClientKey(t *testing.T) {
+	assert.Equal(t, "unknown", ClientKey(context.Background()))
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 40000}})
+	assert.Equal(t, "ip:192.0.2.1", ClientKey(ctx))
+}
+
+func TestParseBytes(t *testing.T) {
+	tests := map[string]int64{
+		"0":    0,
+		"1000": 1000,
+		"512K": 512 << 10,
+		"10m":  10 << 20,
+		"2G":   2 << 30,
+		" 1M ": 1 << 20,
+	}
+	for input, expected := range tests {
+		value, err := ParseBytes(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, value, input)
+	}
+
+	for _, input := range []string{"", "K", "-1", "1.5M", "1T", "9999999999999G"} {
+		_, err := ParseBytes(input)
+		assert.Error(t, err, input)
+	}
+}
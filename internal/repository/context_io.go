@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"io"
+)
+
+// contextReader is a reader that fails with the error of its context once the context is done,
+// so reading a large file stops soon after the call it belongs to was canceled.
+type contextReader struct {
+	ctx context.Context
+	io.Reader
+}
+
+// Read reads from the underlying reader unless the context is done.
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.Reader.Read(p)
+}
@@ -0,0 +1,735 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"filetransfer/api"
+	"hash"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Directories of the storage of a DedupFileRepository.
+const (
+	dedupIndexDir = "index"
+	dedupBlobsDir = "blobs"
+	dedupTempDir  = "tmp"
+)
+
+// DedupFileRepository is a content-addressed implementation of the FileRepository interface that stores identical
+// content only once. The content of every file is stored as a blob named by its SHA-256 hash in a directory sharded
+// by the first two bytes of the hash, the directory tree of the files is an index of small entry files mapping every
+// file to the hash of its content. Copies only add an index entry.
+//
+// Blobs are reference-counted by the index entries pointing to them and deleted as soon as the last entry is gone.
+// The reference counts are rebuilt from the index when the repository is opened, which also collects blobs left
+// unreferenced by an interrupted write. Modifications of the index are serialized, so a blob is never collected
+// while an entry pointing to it is written.
+type DedupFileRepository struct {
+	root     string
+	resolver *PathResolver
+
+	// mu serializes the modifications of the index and the blobs.
+	mu sync.Mutex
+	// refs counts the index entries pointing to every blob.
+	refs map[string]int
+}
+
+// indexEntry is the content of the index entry file of a stored file.
+type indexEntry struct {
+	// Hash is the hex encoded SHA-256 hash of the content, naming its blob.
+	Hash string `json:"sha256"`
+	Size int64  `json:"size"`
+	// ModTime is when the file was written.
+	ModTime time.Time `json:"mod_time"`
+}
+
+// GarbageStats describes the blobs removed by a garbage collection.
+type GarbageStats struct {
+	Blobs int
+	Bytes int64
+}
+
+// NewDedupFileRepository opens the deduplicating storage in the directory storagePath, creating its layout if needed.
+// Temporary files of interrupted writes and blobs no index entry points to are removed.
+func NewDedupFileRepository(storagePath string) (*DedupFileRepository, error) {
+	// Blobs are located by absolute paths, like the index entries resolved by the PathResolver
+	if absPath, err := filepath.Abs(storagePath); err == nil {
+		storagePath = absPath
+	}
+	for _, dir := range []string{dedupIndexDir, dedupBlobsDir, dedupTempDir} {
+		if err := os.MkdirAll(filepath.Join(storagePath, dir), 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &DedupFileRepository{
+		root:     storagePath,
+		resolver: NewPathResolver(filepath.Join(storagePath, dedupIndexDir)),
+		refs:     make(map[string]int),
+	}
+
+	// No write is in progress yet, so every temporary file is left over
+	tempFiles, err := os.ReadDir(filepath.Join(storagePath, dedupTempDir))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range tempFiles {
+		if err := os.RemoveAll(filepath.Join(storagePath, dedupTempDir, file.Name())); err != nil {
+			return nil, err
+		}
+	}
+
+	// Count the references of the index entries
+	err = filepath.WalkDir(filepath.Join(storagePath, dedupIndexDir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		entry, err := readIndexEntry(path)
+		if err != nil {
+			return err
+		}
+		r.refs[entry.Hash]++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.CollectGarbage(context.Background()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// readIndexEntry reads the index entry file at path.
+func readIndexEntry(path string) (*indexEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entry := &indexEntry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, &fs.PathError{Op: "read index entry", Path: path, Err: err}
+	}
+	return entry, nil
+}
+
+// blobPath returns the path of the blob with the given hash.
+func (r *DedupFileRepository) blobPath(hash string) string {
+	return filepath.Join(r.root, dedupBlobsDir, hash[:2], hash[2:4], hash)
+}
+
+// hideLocation replaces the error at err, if any, with one naming files relative to the index, or to the storage
+// root for the blobs, so the location of the storage is only found in the server log.
+func (r *DedupFileRepository) hideLocation(err *error) {
+	if *err != nil {
+		*err = relativeError(*err, append(r.resolver.roots(), NewPathResolver(r.root).roots()...))
+	}
+}
+
+// resolveModifiable resolves a specific file of the index that is about to be modified.
+// ErrStorageRoot is returned for the storage root itself.
+func (r *DedupFileRepository) resolveModifiable(filename string) (string, error) {
+	cleaned, err := CleanPath(filename)
+	if err != nil {
+		return "", err
+	}
+	if cleaned == "." {
+		return "", ErrStorageRoot
+	}
+	return r.resolver.Resolve(cleaned)
+}
+
+// lookup returns the index entry of a specific file, ErrIsDirectory is returned for directories.
+func (r *DedupFileRepository) lookup(filename string) (*indexEntry, error) {
+	entryPath, err := r.resolver.Resolve(filename)
+	if err != nil {
+		return nil, err
+	}
+	fileInfo, err := os.Stat(entryPath)
+	if err != nil {
+		return nil, err
+	}
+	if fileInfo.IsDir() {
+		return nil, &fs.PathError{Op: "open", Path: filename, Err: ErrIsDirectory}
+	}
+	return readIndexEntry(entryPath)
+}
+
+// GetFileList retrieves the entries below a specific directory of the index.
+func (r *DedupFileRepository) GetFileList(ctx context.Context, dir string, maxDepth int) (_ []*api.FileEntry, err error) {
+	defer r.hideLocation(&err)
+
+	relDir, err := CleanPath(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	dirPath, err := r.resolver.Resolve(relDir)
+	if err != nil {
+		return nil, err
+	}
+
+	fileList := []*api.FileEntry{}
+	if err := listIndex(ctx, dirPath, relDir, 1, maxDepth, &fileList); err != nil {
+		return nil, err
+	}
+
+	return fileList, nil
+}
+
+// listIndex appends the entries of the index directory at dirPath to fileList like listDirectory,
+// reporting the size of the files recorded in their index entries.
+func listIndex(ctx context.Context, dirPath, relDir string, depth, maxDepth int, fileList *[]*api.FileEntry) error {
+	files, err := os.ReadDir(dirPath)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		entry := &api.FileEntry{
+			Name: file.Name(),
+			Path: filepath.ToSlash(filepath.Join(relDir, file.Name())),
+			Type: entryType(file.Type()),
+		}
+		if entry.Type == api.EntryType_ENTRY_TYPE_FILE {
+			indexEntry, err := readIndexEntry(filepath.Join(dirPath, file.Name()))
+			if err != nil {
+				// The entry was removed after the directory was read
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				return err
+			}
+			entry.Size = uint64(indexEntry.Size)
+		}
+		*fileList = append(*fileList, entry)
+
+		if entry.Type == api.EntryType_ENTRY_TYPE_DIRECTORY && (maxDepth == 0 || depth < maxDepth) {
+			if err := listIndex(ctx, filepath.Join(dirPath, file.Name()), filepath.Join(relDir, file.Name()), depth+1, maxDepth, fileList); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetFileInfo retrieves metadata information about a specific file or directory from the index.
+// Files are reported with the permissions 0644 and the owner of their index entry, the MIME type is derived
+// from the extension or, for unknown extensions, from the first bytes of the content.
+func (r *DedupFileRepository) GetFileInfo(ctx context.Context, filename string) (_ interface{}, err error) {
+	defer r.hideLocation(&err)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	entryPath, err := r.resolver.Resolve(filename)
+	if err != nil {
+		return nil, err
+	}
+	fileInfo, err := os.Stat(entryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	fileMetadata := &api.FileInfoResponse{
+		Filename:         filename,
+		Size:             uint64(fileInfo.Size()),
+		ModificationTime: timestamppb.New(fileInfo.ModTime()),
+		Mode:             unixMode(fileInfo.Mode()),
+		Type:             entryType(fileInfo.Mode()),
+	}
+	fileMetadata.Uid, fileMetadata.Gid = fileOwner(fileInfo)
+	if fileInfo.IsDir() {
+		return fileMetadata, nil
+	}
+
+	entry, err := readIndexEntry(entryPath)
+	if err != nil {
+		return nil, err
+	}
+	fileMetadata.Size = uint64(entry.Size)
+	fileMetadata.ModificationTime = timestamppb.New(entry.ModTime)
+	fileMetadata.Mode = 0644
+
+	// Blobs have no extension, so detectMIMEType derives the type from the content
+	fileMetadata.MimeType = mime.TypeByExtension(filepath.Ext(entryPath))
+	if fileMetadata.MimeType == "" {
+		if fileMetadata.MimeType, err = detectMIMEType(r.blobPath(entry.Hash)); err != nil {
+			return nil, err
+		}
+	}
+	return fileMetadata, nil
+}
+
+// GetFileContent retrieves the content of a specific file from its blob.
+// Reading stops with the error of ctx once it is done.
+func (r *DedupFileRepository) GetFileContent(ctx context.Context, filename string) ([]byte, error) {
+	reader, err := r.GetFileReader(ctx, filename, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// GetFileReader opens the blob of a specific file for sequential reading of the byte range starting at offset.
+// A length of zero reads until the end of the file. The reader fails with the error of ctx once it is done.
+// A blob stays readable until the reader is closed, even if the file is deleted meanwhile.
+func (r *DedupFileRepository) GetFileReader(ctx context.Context, filename string, offset, length int64) (_ io.ReadCloser, err error) {
+	defer r.hideLocation(&err)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	entry, err := r.lookup(filename)
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 || length < 0 || offset > entry.Size {
+		return nil, ErrInvalidRange
+	}
+
+	blob, err := os.Open(r.blobPath(entry.Hash))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := blob.Seek(offset, io.SeekStart); err != nil {
+		blob.Close()
+		return nil, err
+	}
+
+	var reader io.Reader = blob
+	if length > 0 {
+		reader = io.LimitReader(blob, length)
+	}
+	return &namedReader{
+		ReadCloser: &readCloser{Reader: &contextReader{ctx: ctx, Reader: reader}, Closer: blob},
+		name:       filename,
+	}, nil
+}
+
+// GetFileWriter creates or replaces a specific file. The content is hashed while it is written to a temporary file,
+// which becomes the blob of the file when the writer is closed, unless a blob with the same content exists already.
+// ErrIsDirectory is returned if the file is a directory. Once ctx is done, writing fails and closing discards
+// the temporary file.
+func (r *DedupFileRepository) GetFileWriter(ctx context.Context, filename string) (_ FileWriter, err error) {
+	defer r.hideLocation(&err)
+
+	entryPath, err := r.resolveModifiable(filename)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNotDirectory(entryPath, filename); err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp(filepath.Join(r.root, dedupTempDir), "upload-*")
+	if err != nil {
+		return nil, err
+	}
+	return &dedupFileWriter{File: file, ctx: ctx, repository: r, entryPath: entryPath, name: filename, hash: sha256.New()}, nil
+}
+
+// dedupFileWriter is a FileWriter that writes to a temporary file and stores it as a blob on Close.
+type dedupFileWriter struct {
+	*os.File
+	ctx        context.Context
+	repository *DedupFileRepository
+	entryPath  string
+	// name is the file as requested by the client.
+	name string
+	hash hash.Hash
+	size int64
+}
+
+// Write writes to the temporary file and the hash unless the context of the writer is done.
+func (w *dedupFileWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := w.File.Write(p)
+	w.hash.Write(p[:n])
+	w.size += int64(n)
+	return n, namedError(err, w.name)
+}
+
+// ReadFrom copies from the reader through Write, so the content is hashed and the copy stops
+// once the context of the writer is done.
+func (w *dedupFileWriter) ReadFrom(reader io.Reader) (int64, error) {
+	return io.Copy(struct{ io.Writer }{w}, reader)
+}
+
+// Close stores the written content as a blob and points the index entry of the file to it,
+// unless the context of the writer is done. Errors name the file as requested, not the blob.
+func (w *dedupFileWriter) Close() error {
+	if err := w.ctx.Err(); err != nil {
+		w.Abort()
+		return err
+	}
+	if err := w.File.Close(); err != nil {
+		os.Remove(w.File.Name())
+		return namedError(err, w.name)
+	}
+
+	entry := &indexEntry{Hash: hex.EncodeToString(w.hash.Sum(nil)), Size: w.size, ModTime: time.Now()}
+	r := w.repository
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.storeBlob(w.File.Name(), entry.Hash); err != nil {
+		return namedError(err, w.name)
+	}
+	return namedError(r.writeEntry(w.entryPath, entry), w.name)
+}
+
+// Abort discards the temporary file.
+func (w *dedupFileWriter) Abort() error {
+	w.File.Close()
+	return namedError(os.Remove(w.File.Name()), w.name)
+}
+
+// storeBlob moves the temporary file into place as the blob with the given hash, or removes it if the blob exists.
+// The caller must hold r.mu.
+func (r *DedupFileRepository) storeBlob(tempPath, hash string) error {
+	blobPath := r.blobPath(hash)
+	if r.refs[hash] > 0 {
+		if _, err := os.Stat(blobPath); err == nil {
+			return os.Remove(tempPath)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(blobPath), 0755); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	// Blobs are shared by files, so they are never modified
+	if err := os.Chmod(tempPath, 0444); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	if err := os.Rename(tempPath, blobPath); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
+
+// writeEntry points the index entry at entryPath to the blob of entry, releasing the blob of a replaced entry.
+// A blob without references, because writing the entry failed, is removed. The caller must hold r.mu.
+func (r *DedupFileRepository) writeEntry(entryPath string, entry *indexEntry) error {
+	previous, err := readIndexEntry(entryPath)
+	if err != nil {
+		previous = nil
+	}
+
+	err = r.writeEntryFile(entryPath, entry)
+	if err != nil {
+		if r.refs[entry.Hash] == 0 {
+			os.Remove(r.blobPath(entry.Hash))
+		}
+		return err
+	}
+
+	r.refs[entry.Hash]++
+	if previous != nil {
+		r.release(previous.Hash)
+	}
+	return nil
+}
+
+// writeEntryFile atomically writes the index entry file at entryPath, creating missing parent directories.
+func (r *DedupFileRepository) writeEntryFile(entryPath string, entry *indexEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(entryPath), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Join(r.root, dedupTempDir), "entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), entryPath)
+}
+
+// release drops a reference to the blob with the given hash and removes the blob with its last reference.
+// The caller must hold r.mu.
+func (r *DedupFileRepository) release(hash string) {
+	r.refs[hash]--
+	if r.refs[hash] > 0 {
+		return
+	}
+	delete(r.refs, hash)
+	// A blob that cannot be removed now is collected later
+	os.Remove(r.blobPath(hash))
+}
+
+// releaseTree drops the references of all index entries below entryPath. The caller must hold r.mu.
+func (r *DedupFileRepository) releaseTree(entryPath string) error {
+	var hashes []string
+	err := filepath.WalkDir(entryPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		entry, err := readIndexEntry(path)
+		if err != nil {
+			return err
+		}
+		hashes = append(hashes, entry.Hash)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(entryPath); err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		r.release(hash)
+	}
+	return nil
+}
+
+// DeleteFile deletes a specific file or directory from the index, together with the blobs no other file refers to.
+func (r *DedupFileRepository) DeleteFile(ctx context.Context, filename string, recursive bool) (err error) {
+	defer r.hideLocation(&err)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	entryPath, err := r.resolveModifiable(filename)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fileInfo, err := os.Lstat(entryPath)
+	if err != nil {
+		return err
+	}
+
+	if fileInfo.IsDir() {
+		if recursive {
+			return r.releaseTree(entryPath)
+		}
+
+		entries, err := os.ReadDir(entryPath)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			return ErrDirectoryNotEmpty
+		}
+		return os.Remove(entryPath)
+	}
+
+	entry, err := readIndexEntry(entryPath)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(entryPath); err != nil {
+		return err
+	}
+	r.release(entry.Hash)
+	return nil
+}
+
+// RenameFile renames or moves a file or directory within the index, the blobs are left untouched.
+// Missing parent directories of the destination are created, renaming a file to itself has no effect.
+func (r *DedupFileRepository) RenameFile(ctx context.Context, source, destination string, overwrite bool) (err error) {
+	defer r.hideLocation(&err)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	sourcePath, err := r.resolveModifiable(source)
+	if err != nil {
+		return err
+	}
+	destinationPath, err := r.resolveModifiable(destination)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := os.Lstat(sourcePath); err != nil {
+		return err
+	}
+	if sourcePath == destinationPath {
+		return nil
+	}
+	if err := checkDestination(destinationPath, destination, overwrite); err != nil {
+		return err
+	}
+
+	// A replaced file releases its blob
+	replaced, err := readIndexEntry(destinationPath)
+	if err != nil {
+		replaced = nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destinationPath), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(sourcePath, destinationPath); err != nil {
+		return err
+	}
+	if replaced != nil {
+		r.release(replaced.Hash)
+	}
+	return nil
+}
+
+// CopyFile copies a file by adding an index entry pointing to the blob of the source, no content is copied.
+func (r *DedupFileRepository) CopyFile(ctx context.Context, source, destination string, overwrite bool) (_ int64, err error) {
+	defer r.hideLocation(&err)
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	destinationPath, err := r.resolveModifiable(destination)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, err := r.lookup(source)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkDestination(destinationPath, destination, overwrite); err != nil {
+		return 0, err
+	}
+
+	copied := &indexEntry{Hash: entry.Hash, Size: entry.Size, ModTime: time.Now()}
+	if err := r.writeEntry(destinationPath, copied); err != nil {
+		return 0, err
+	}
+	return copied.Size, nil
+}
+
+// MakeDirectory creates a directory in the index.
+func (r *DedupFileRepository) MakeDirectory(ctx context.Context, path string, parents bool) (err error) {
+	defer r.hideLocation(&err)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	dirPath, err := r.resolver.Resolve(path)
+	if err != nil {
+		return err
+	}
+
+	if parents {
+		return os.MkdirAll(dirPath, 0755)
+	}
+	return os.Mkdir(dirPath, 0755)
+}
+
+// ResolveLinks returns the path relative to the storage root that a path of the index refers to
+// once every symbolic link on it is followed.
+func (r *DedupFileRepository) ResolveLinks(ctx context.Context, name string) (_ string, err error) {
+	defer r.hideLocation(&err)
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return r.resolver.ResolveRelative(name)
+}
+
+// Probe checks that the index and the blobs of the storage are readable directories.
+func (r *DedupFileRepository) Probe(ctx context.Context) error {
+	for _, dir := range []string{dedupIndexDir, dedupBlobsDir} {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		dirFile, err := os.Open(filepath.Join(r.root, dir))
+		if err != nil {
+			return err
+		}
+		_, err = dirFile.ReadDir(1)
+		dirFile.Close()
+		if err != nil && err != io.EOF {
+			return err
+		}
+	}
+	return nil
+}
+
+// CollectGarbage removes the blobs no index entry refers to, which are left behind when a write is interrupted
+// between storing the blob and writing its index entry, or when removing a released blob failed.
+// It holds the lock of the index, so no blob is removed while an entry pointing to it is written.
+func (r *DedupFileRepository) CollectGarbage(ctx context.Context) (GarbageStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var stats GarbageStats
+	err := filepath.WalkDir(filepath.Join(r.root, dedupBlobsDir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || r.refs[d.Name()] > 0 {
+			return nil
+		}
+
+		fileInfo, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		stats.Blobs++
+		stats.Bytes += fileInfo.Size()
+		return nil
+	})
+	return stats, err
+}
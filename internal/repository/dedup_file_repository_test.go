@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDedupTestRepository opens a deduplicating repository in a temporary directory.
+func newDedupTestRepository(t *testing.T) (*DedupFileRepository, string) {
+	root := t.TempDir()
+	repo, err := NewDedupFileRepository(root)
+	require.NoError(t, err)
+	return repo, root
+}
+
+// blobs returns the names of the blobs stored in root.
+func blobs(t *testing.T, root string) []string {
+	var names []string
+	err := filepath.WalkDir(filepath.Join(root, dedupBlobsDir), func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			names = append(names, d.Name())
+		}
+		return err
+	})
+	require.NoError(t, err)
+	return names
+}
+
+// contentHash returns the hex encoded SHA-256 hash of content.
+func contentHash(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestDedupFileRepository_Behavior(t *testing.T) {
+	testFileRepository(t, func(t *testing.T) FileRepository {
+		repo, _ := newDedupTestRepository(t)
+		return repo
+	})
+}
+
+func TestDedupFileRepository_IdenticalContent(t *testing.T) {
+	repo, root := newDedupTestRepository(t)
+
+	writeFile(t, repo, "a.txt", "shared content")
+	writeFile(t, repo, "dir/b.txt", "shared content")
+	writeFile(t, repo, "c.txt", "other content")
+	_, err := repo.CopyFile(context.Background(), "a.txt", "copy.txt", false)
+	require.NoError(t, err)
+
+	// Identical content is stored once, in a directory sharded by the hash
+	hash := contentHash("shared content")
+	assert.ElementsMatch(t, []string{hash, contentHash("other content")}, blobs(t, root))
+	assert.FileExists(t, filepath.Join(root, dedupBlobsDir, hash[:2], hash[2:4], hash))
+	assert.Equal(t, 3, repo.refs[hash])
+
+	for _, name := range []string{"a.txt", "dir/b.txt", "copy.txt"} {
+		assert.Equal(t, "shared content", readFile(t, repo, name))
+	}
+}
+
+func TestDedupFileRepository_ReleaseBlobs(t *testing.T) {
+	repo, root := newDedupTestRepository(t)
+
+	writeFile(t, repo, "a.txt", "shared content")
+	writeFile(t, repo, "dir/b.txt", "shared content")
+	writeFile(t, repo, "dir/c.txt", "other content")
+
+	// A blob is removed with the last file referring to it
+	require.NoError(t, repo.DeleteFile(context.Background(), "a.txt", false))
+	assert.Len(t, blobs(t, root), 2)
+	require.NoError(t, repo.DeleteFile(context.Background(), "dir", true))
+	assert.Empty(t, blobs(t, root))
+	assert.Empty(t, repo.refs)
+
+	// So is the blob of replaced content
+	writeFile(t, repo, "a.txt", "first")
+	writeFile(t, repo, "a.txt", "second")
+	assert.Equal(t, []string{contentHash("second")}, blobs(t, root))
+
+	writeFile(t, repo, "b.txt", "third")
+	require.NoError(t, repo.RenameFile(context.Background(), "b.txt", "a.txt", true))
+	assert.Equal(t, []string{contentHash("third")}, blobs(t, root))
+
+	_, err := repo.CopyFile(context.Background(), "a.txt", "copy.txt", false)
+	require.NoError(t, err)
+	writeFile(t, repo, "a.txt", "fourth")
+	assert.ElementsMatch(t, []string{contentHash("third"), contentHash("fourth")}, blobs(t, root))
+
+	// Aborted writes leave no blob behind
+	writer, err := repo.GetFileWriter(context.Background(), "aborted.txt")
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("aborted"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Abort())
+	assert.Len(t, blobs(t, root), 2)
+	entries, err := os.ReadDir(filepath.Join(root, dedupTempDir))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDedupFileRepository_Reopen(t *testing.T) {
+	repo, root := newDedupTestRepository(t)
+	writeFile(t, repo, "a.txt", "shared content")
+	writeFile(t, repo, "b.txt", "shared content")
+
+	// An interrupted write leaves a temporary file and an unreferenced blob
+	orphan := contentHash("orphan")
+	orphanPath := filepath.Join(root, dedupBlobsDir, orphan[:2], orphan[2:4], orphan)
+	require.NoError(t, os.MkdirAll(filepath.Dir(orphanPath), 0755))
+	require.NoError(t, os.WriteFile(orphanPath, []byte("orphan"), 0444))
+	require.NoError(t, os.WriteFile(filepath.Join(root, dedupTempDir, "blob-1"), []byte("partial"), 0644))
+
+	stats, err := repo.CollectGarbage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, GarbageStats{Blobs: 1, Bytes: 6}, stats)
+	require.NoError(t, os.WriteFile(orphanPath, []byte("orphan"), 0444))
+
+	// Reopening rebuilds the reference counts from the index and cleans up
+	repo, err = NewDedupFileRepository(root)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{contentHash("shared content"): 2}, repo.refs)
+	assert.Equal(t, []string{contentHash("shared content")}, blobs(t, root))
+	entries, err := os.ReadDir(filepath.Join(root, dedupTempDir))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.Equal(t, "shared content", readFile(t, repo, "b.txt"))
+	require.NoError(t, repo.DeleteFile(context.Background(), "a.txt", false))
+	require.NoError(t, repo.DeleteFile(context.Background(), "b.txt", false))
+	assert.Empty(t, blobs(t, root))
+}
+
+func TestDedupFileRepository_ConcurrentWrites(t *testing.T) {
+	repo, root := newDedupTestRepository(t)
+
+	// Files with the same content written and deleted concurrently share a single blob
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("file%d.txt", i)
+			writeFile(t, repo, name, "shared content")
+			if i%2 == 0 {
+				assert.NoError(t, repo.DeleteFile(context.Background(), name, false))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{contentHash("shared content")}, blobs(t, root))
+	assert.Equal(t, 8, repo.refs[contentHash("shared content")])
+	fileList, err := repo.GetFileList(context.Background(), "", 0)
+	require.NoError(t, err)
+	assert.Len(t, fileList, 8)
+}
+
+func TestDedupFileRepository_Errors(t *testing.T) {
+	repo, root := newDedupTestRepository(t)
+	writeFile(t, repo, "file.txt", "content")
+
+	// A corrupt index entry is reported instead of being served
+	require.NoError(t, os.WriteFile(filepath.Join(root, dedupIndexDir, "corrupt.txt"), []byte("{"), 0644))
+	_, err := repo.GetFileContent(context.Background(), "corrupt.txt")
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), root)
+
+	// Errors name files relative to the index
+	_, err = repo.GetFileInfo(context.Background(), "missing/file.txt")
+	assert.EqualError(t, err, "stat missing/file.txt: no such file or directory")
+	_, err = NewDedupFileRepository(root)
+	assert.Error(t, err)
+
+	_, err = NewDedupFileRepository(filepath.Join(root, dedupIndexDir, "file.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package repository
+
+import "os"
+
+// fileOwner returns zero IDs on platforms without Unix file ownership.
+func fileOwner(fileInfo os.FileInfo) (uint32, uint32) {
+	return 0, 0
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package repository
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the user and group ID owning the file.
+func fileOwner(fileInfo os.FileInfo) (uint32, uint32) {
+	stat, ok := fileInfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0
+	}
+	return stat.Uid, stat.Gid
+}
@@ -1,14 +1,93 @@
 package repository
 
+import (
+	"context"
+	"errors"
+	"filetransfer/api"
+	"io"
+)
+
+var (
+	// ErrInvalidRange is returned when a requested byte range lies outside of a file.
+	ErrInvalidRange = errors.New("requested range is outside of the file")
+	// ErrDirectoryNotEmpty is returned when a directory with content is deleted without deleting its content.
+	ErrDirectoryNotEmpty = errors.New("directory is not empty")
+	// ErrIsDirectory is returned when an operation that requires a file is applied to a directory.
+	ErrIsDirectory = errors.New("is a directory")
+	// ErrStorageRoot is returned when an operation is applied to the storage root itself, which must not be modified.
+	ErrStorageRoot = errors.New("the storage root cannot be modified")
+	// ErrStorageFull is returned when storing content would exceed a size limit of the storage.
+	ErrStorageFull = errors.New("storage size limit exceeded")
+)
+
 // FileRepository is an interface defining methods for interacting with file-related operations.
+// Every method stops its work when its context is done and returns the error of the context, readers and writers
+// obtained from a method stop when the context passed to that method is done.
 type FileRepository interface {
-	// GetFileList returns a list of file names available in the repository.
-	GetFileList() ([]string, error)
+	// GetFileList returns the entries below the directory identified by dir, an empty dir being the root.
+	// Subdirectories are descended into up to maxDepth levels, a maxDepth of 1 returns only the direct
+	// children and 0 returns the whole subtree. Entries are returned in depth-first order sorted by name
+	// within each directory, symbolic links are reported but not followed.
+	GetFileList(ctx context.Context, dir string, maxDepth int) ([]*api.FileEntry, error)
 
 	// GetFileInfo retrieves metadata information about a specific file identified by its filename.
 	// The returned metadata type should encapsulate details like filename, size, etc.
-	GetFileInfo(filename string) (interface{}, error)
+	GetFileInfo(ctx context.Context, filename string) (interface{}, error)
 
 	// GetFileContent retrieves the content of a specific file identified by its filename.
-	GetFileContent(filename string) ([]byte, error)
+	GetFileContent(ctx context.Context, filename string) ([]byte, error)
+
+	// GetFileReader opens a specific file identified by its filename for sequential reading
+	// of the byte range starting at offset. A length of zero reads until the end of the file.
+	// ErrInvalidRange is returned if the offset lies beyond the end of the file.
+	// The caller is responsible for closing the returned reader.
+	GetFileReader(ctx context.Context, filename string, offset, length int64) (io.ReadCloser, error)
+
+	// GetFileWriter creates or replaces a specific file identified by its filename.
+	// The written content becomes visible only after the returned writer is closed successfully.
+	GetFileWriter(ctx context.Context, filename string) (FileWriter, error)
+
+	// DeleteFile deletes a specific file or directory identified by its filename.
+	// Directories with content are only deleted if recursive is set, otherwise ErrDirectoryNotEmpty is returned.
+	DeleteFile(ctx context.Context, filename string, recursive bool) error
+
+	// RenameFile renames or moves a file or directory from source to destination.
+	// An existing destination is only replaced if overwrite is set, otherwise an error matching fs.ErrExist is returned.
+	// Renaming a file or directory to itself has no effect, whether or not overwrite is set.
+	RenameFile(ctx context.Context, source, destination string, overwrite bool) error
+
+	// CopyFile copies a file from source to destination and returns the number of bytes copied.
+	// An existing destination is only replaced if overwrite is set, otherwise an error matching fs.ErrExist is returned.
+	// ErrIsDirectory is returned if the source is a directory.
+	CopyFile(ctx context.Context, source, destination string, overwrite bool) (int64, error)
+
+	// MakeDirectory creates a directory identified by its path. If parents is set, missing parent directories
+	// are created as well and an existing directory is not an error.
+	MakeDirectory(ctx context.Context, path string, parents bool) error
+
+	// Probe checks that the storage is available and returns an error describing the problem if it is not,
+	// for example because the storage root is missing or cannot be read.
+	Probe(ctx context.Context) error
+}
+
+// FileWriter is a writer for a file being stored in a FileRepository.
+type FileWriter interface {
+	io.WriteCloser
+
+	// Abort discards everything written so far and leaves any existing file untouched.
+	Abort() error
+}
+
+// LinkResolver is implemented by repositories whose paths may lead through symbolic links.
+type LinkResolver interface {
+	// ResolveLinks returns the path relative to the storage root that name refers to once every symbolic link on it
+	// is followed, using forward slashes. A path that does not exist yet is resolved up to its deepest existing
+	// parent directory.
+	ResolveLinks(ctx context.Context, name string) (string, error)
+}
+
+// WriteBufferSizer is implemented by repositories whose writers buffer content in memory before storing it.
+type WriteBufferSizer interface {
+	// WriteBufferSize returns the number of bytes every writer of the repository buffers in memory.
+	WriteBufferSize() int64
 }
@@ -0,0 +1,485 @@
+package repository
+
+import (
+	"context"
+	"filetransfer/api"
+	"io"
+	"io/fs"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFileRepository runs the behaviour tests every FileRepository implementation must pass.
+// newRepository returns an empty repository, files are set up and checked through the repository itself.
+func testFileRepository(t *testing.T, newRepository func(t *testing.T) FileRepository) {
+	tests := map[string]func(t *testing.T, repo FileRepository){
+		"GetFileList":              testGetFileList,
+		"GetFileList_Errors":       testGetFileListErrors,
+		"GetFileInfo":              testGetFileInfo,
+		"GetFileContent":           testGetFileContent,
+		"GetFileReader_Range":      testGetFileReaderRange,
+		"GetFileWriter":            testGetFileWriter,
+		"GetFileWriter_Abort":      testGetFileWriterAbort,
+		"GetFileWriter_Conflicts":  testGetFileWriterConflicts,
+		"PathOutsideRoot":          testPathOutsideRoot,
+		"DeleteFile":               testDeleteFile,
+		"RenameFile":               testRenameFile,
+		"CopyFile":                 testCopyFile,
+		"MakeDirectory":            testMakeDirectory,
+		"Probe":                    testProbe,
+		"Canceled":                 testCanceled,
+		"GetFileReader_Canceled":   testGetFileReaderCanceled,
+		"GetFileWriter_Canceled":   testGetFileWriterCanceled,
+		"GetFileWriter_Concurrent": testGetFileWriterConcurrent,
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, newRepository(t))
+		})
+	}
+}
+
+// writeFile stores a file with the given content in the repository.
+func writeFile(t *testing.T, repo FileRepository, filename, content string) {
+	t.Helper()
+	writer, err := repo.GetFileWriter(context.Background(), filename)
+	require.NoError(t, err)
+	_, err = writer.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+}
+
+// storeFile stores a file like writeFile, but returns the error of opening, writing or closing the writer.
+func storeFile(repo FileRepository, filename, content string) error {
+	writer, err := repo.GetFileWriter(context.Background(), filename)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write([]byte(content)); err != nil {
+		writer.Abort()
+		return err
+	}
+	return writer.Close()
+}
+
+// readFile returns the content of a file in the repository.
+func readFile(t *testing.T, repo FileRepository, filename string) string {
+	t.Helper()
+	content, err := repo.GetFileContent(context.Background(), filename)
+	require.NoError(t, err)
+	return string(content)
+}
+
+// assertNotExist asserts that a file does not exist in the repository.
+func assertNotExist(t *testing.T, repo FileRepository, filename string) {
+	t.Helper()
+	_, err := repo.GetFileInfo(context.Background(), filename)
+	assert.ErrorIs(t, err, fs.ErrNotExist, filename)
+}
+
+func testGetFileList(t *testing.T, repo FileRepository) {
+	writeFile(t, repo, "dir/a.txt", "a")
+	writeFile(t, repo, "dir/sub/b.txt", "bb")
+	writeFile(t, repo, "dir/sub/deep/c.txt", "ccc")
+	writeFile(t, repo, "file.txt", "content")
+
+	dir := &api.FileEntry{Name: "dir", Path: "dir", Type: api.EntryType_ENTRY_TYPE_DIRECTORY}
+	a := &api.FileEntry{Name: "a.txt", Path: "dir/a.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 1}
+	sub := &api.FileEntry{Name: "sub", Path: "dir/sub", Type: api.EntryType_ENTRY_TYPE_DIRECTORY}
+	b := &api.FileEntry{Name: "b.txt", Path: "dir/sub/b.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 2}
+	deep := &api.FileEntry{Name: "deep", Path: "dir/sub/deep", Type: api.EntryType_ENTRY_TYPE_DIRECTORY}
+	c := &api.FileEntry{Name: "c.txt", Path: "dir/sub/deep/c.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 3}
+	file := &api.FileEntry{Name: "file.txt", Path: "file.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 7}
+
+	fileList, err := repo.GetFileList(context.Background(), "", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{dir, file}, fileList)
+
+	fileList, err = repo.GetFileList(context.Background(), "dir", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{a, sub, b, deep}, fileList)
+
+	fileList, err = repo.GetFileList(context.Background(), "./dir/", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{a, sub, b, deep, c}, fileList)
+
+	fileList, err = repo.GetFileList(context.Background(), "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{dir, a, sub, b, deep, c, file}, fileList)
+
+	fileList, err = repo.GetFileList(context.Background(), "dir/sub/deep/", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{c}, fileList)
+}
+
+func testGetFileListErrors(t *testing.T, repo FileRepository) {
+	writeFile(t, repo, "file.txt", "content")
+
+	_, err := repo.GetFileList(context.Background(), "missing", 1)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = repo.GetFileList(context.Background(), "file.txt", 1)
+	assert.Error(t, err)
+
+	_, err = repo.GetFileList(context.Background(), "../", 1)
+	assert.ErrorIs(t, err, ErrPathOutsideRoot)
+}
+
+func testGetFileInfo(t *testing.T, repo FileRepository) {
+	before := time.Now().Add(-time.Second)
+	writeFile(t, repo, "dir/data.json", "{}")
+	writeFile(t, repo, "image", "\x89PNG\r\n\x1a\n")
+	writeFile(t, repo, "file.txt", "content")
+
+	fileMetadata, err := repo.GetFileInfo(context.Background(), "file.txt")
+	require.NoError(t, err)
+	fileInfo := fileMetadata.(*api.FileInfoResponse)
+	assert.Equal(t, "file.txt", fileInfo.Filename)
+	assert.Equal(t, uint64(7), fileInfo.Size)
+	assert.Equal(t, api.EntryType_ENTRY_TYPE_FILE, fileInfo.Type)
+	assert.Equal(t, uint32(0644), fileInfo.Mode)
+	assert.Equal(t, "text/plain; charset=utf-8", fileInfo.MimeType)
+	assert.WithinRange(t, fileInfo.ModificationTime.AsTime(), before, time.Now().Add(time.Second))
+
+	// MIME types are derived from the extension or, without a known extension, from the content
+	fileMetadata, err = repo.GetFileInfo(context.Background(), "dir/data.json")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", fileMetadata.(*api.FileInfoResponse).MimeType)
+
+	fileMetadata, err = repo.GetFileInfo(context.Background(), "image")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", fileMetadata.(*api.FileInfoResponse).MimeType)
+
+	fileMetadata, err = repo.GetFileInfo(context.Background(), "dir")
+	require.NoError(t, err)
+	assert.Equal(t, api.EntryType_ENTRY_TYPE_DIRECTORY, fileMetadata.(*api.FileInfoResponse).Type)
+	assert.Empty(t, fileMetadata.(*api.FileInfoResponse).MimeType)
+
+	assertNotExist(t, repo, "missing.txt")
+}
+
+func testGetFileContent(t *testing.T, repo FileRepository) {
+	writeFile(t, repo, "file.txt", "content")
+	writeFile(t, repo, "empty.txt", "")
+	require.NoError(t, repo.MakeDirectory(context.Background(), "dir", false))
+
+	assert.Equal(t, "content", readFile(t, repo, "file.txt"))
+	assert.Equal(t, "", readFile(t, repo, "empty.txt"))
+
+	_, err := repo.GetFileContent(context.Background(), "missing.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = repo.GetFileContent(context.Background(), "dir")
+	assert.Error(t, err)
+}
+
+func testGetFileReaderRange(t *testing.T, repo FileRepository) {
+	writeFile(t, repo, "file.txt", "0123456789")
+
+	for _, tt := range []struct {
+		offset, length int64
+		expected       string
+	}{
+		{0, 0, "0123456789"},
+		{3, 4, "3456"},
+		{7, 0, "789"},
+		{8, 10, "89"},
+		{10, 0, ""},
+	} {
+		reader, err := repo.GetFileReader(context.Background(), "file.txt", tt.offset, tt.length)
+		require.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.NoError(t, reader.Close())
+		assert.Equal(t, tt.expected, string(content), "offset %d length %d", tt.offset, tt.length)
+	}
+
+	_, err := repo.GetFileReader(context.Background(), "file.txt", 11, 0)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = repo.GetFileReader(context.Background(), "file.txt", -1, 0)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = repo.GetFileReader(context.Background(), "missing.txt", 0, 0)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func testGetFileWriter(t *testing.T, repo FileRepository) {
+	writer, err := repo.GetFileWriter(context.Background(), "dir/file.txt")
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("content"))
+	assert.NoError(t, err)
+
+	// The file must not be visible before the writer is closed
+	assertNotExist(t, repo, "dir/file.txt")
+
+	assert.NoError(t, writer.Close())
+	assert.Equal(t, "content", readFile(t, repo, "dir/file.txt"))
+
+	// An existing file is replaced
+	writeFile(t, repo, "dir/file.txt", "replaced")
+	assert.Equal(t, "replaced", readFile(t, repo, "dir/file.txt"))
+
+	fileList, err := repo.GetFileList(context.Background(), "dir", 1)
+	assert.NoError(t, err)
+	assert.Len(t, fileList, 1)
+}
+
+func testGetFileWriterAbort(t *testing.T, repo FileRepository) {
+	writeFile(t, repo, "file.txt", "original")
+
+	writer, err := repo.GetFileWriter(context.Background(), "file.txt")
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("partial"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Abort())
+
+	assert.Equal(t, "original", readFile(t, repo, "file.txt"))
+	fileList, err := repo.GetFileList(context.Background(), "", 0)
+	assert.NoError(t, err)
+	assert.Len(t, fileList, 1)
+}
+
+func testGetFileWriterConflicts(t *testing.T, repo FileRepository) {
+	writeFile(t, repo, "dir/a.txt", "a")
+	writeFile(t, repo, "b.txt", "b")
+
+	// A file is neither written over a directory nor below another file
+	assert.ErrorIs(t, storeFile(repo, "dir", "content"), ErrIsDirectory)
+	assert.ErrorIs(t, storeFile(repo, "dir/a.txt/b", "content"), syscall.ENOTDIR)
+	assert.ErrorIs(t, repo.MakeDirectory(context.Background(), "dir/a.txt/sub", true), syscall.ENOTDIR)
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "b.txt", "dir/a.txt/b", false), syscall.ENOTDIR)
+	_, err := repo.CopyFile(context.Background(), "b.txt", "dir/a.txt/b", true)
+	assert.ErrorIs(t, err, syscall.ENOTDIR)
+
+	// Every entry keeps its single type
+	fileList, err := repo.GetFileList(context.Background(), "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{
+		{Name: "b.txt", Path: "b.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 1},
+		{Name: "dir", Path: "dir", Type: api.EntryType_ENTRY_TYPE_DIRECTORY},
+		{Name: "a.txt", Path: "dir/a.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 1},
+	}, fileList)
+	assert.Equal(t, "a", readFile(t, repo, "dir/a.txt"))
+}
+
+func testPathOutsideRoot(t *testing.T, repo FileRepository) {
+	for _, name := range []string{"../secret.txt", "dir/../../secret.txt"} {
+		_, err := repo.GetFileInfo(context.Background(), name)
+		assert.ErrorIs(t, err, ErrPathOutsideRoot, name)
+
+		_, err = repo.GetFileContent(context.Background(), name)
+		assert.ErrorIs(t, err, ErrPathOutsideRoot, name)
+
+		_, err = repo.GetFileReader(context.Background(), name, 0, 0)
+		assert.ErrorIs(t, err, ErrPathOutsideRoot, name)
+
+		_, err = repo.GetFileWriter(context.Background(), name)
+		assert.ErrorIs(t, err, ErrPathOutsideRoot, name)
+
+		assert.ErrorIs(t, repo.DeleteFile(context.Background(), name, false), ErrPathOutsideRoot, name)
+		assert.ErrorIs(t, repo.MakeDirectory(context.Background(), name, true), ErrPathOutsideRoot, name)
+	}
+}
+
+func testDeleteFile(t *testing.T, repo FileRepository) {
+	writeFile(t, repo, "file.txt", "content")
+	writeFile(t, repo, "dir/sub/file.txt", "content")
+	require.NoError(t, repo.MakeDirectory(context.Background(), "empty", false))
+
+	assert.NoError(t, repo.DeleteFile(context.Background(), "file.txt", false))
+	assertNotExist(t, repo, "file.txt")
+
+	assert.NoError(t, repo.DeleteFile(context.Background(), "empty", false))
+	assertNotExist(t, repo, "empty")
+
+	assert.ErrorIs(t, repo.DeleteFile(context.Background(), "dir", false), ErrDirectoryNotEmpty)
+	assert.Equal(t, "content", readFile(t, repo, "dir/sub/file.txt"))
+	assert.NoError(t, repo.DeleteFile(context.Background(), "dir", true))
+	assertNotExist(t, repo, "dir")
+	assertNotExist(t, repo, "dir/sub/file.txt")
+
+	assert.ErrorIs(t, repo.DeleteFile(context.Background(), "missing.txt", false), fs.ErrNotExist)
+	assert.ErrorIs(t, repo.DeleteFile(context.Background(), ".", true), ErrStorageRoot)
+	assert.ErrorIs(t, repo.DeleteFile(context.Background(), "", true), ErrStorageRoot)
+	assert.NoError(t, repo.Probe(context.Background()))
+}
+
+func testRenameFile(t *testing.T, repo FileRepository) {
+	writeFile(t, repo, "a.txt", "a")
+	writeFile(t, repo, "b.txt", "b")
+
+	// Moving creates missing parent directories
+	assert.NoError(t, repo.RenameFile(context.Background(), "a.txt", "dir/moved.txt", false))
+	assertNotExist(t, repo, "a.txt")
+	assert.Equal(t, "a", readFile(t, repo, "dir/moved.txt"))
+
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "b.txt", "dir/moved.txt", false), fs.ErrExist)
+	assert.NoError(t, repo.RenameFile(context.Background(), "b.txt", "dir/moved.txt", true))
+	assert.Equal(t, "b", readFile(t, repo, "dir/moved.txt"))
+	assertNotExist(t, repo, "b.txt")
+
+	// Renaming a file or directory to itself keeps it, whether or not it may be overwritten
+	assert.NoError(t, repo.RenameFile(context.Background(), "dir/moved.txt", "dir/moved.txt", true))
+	assert.Equal(t, "b", readFile(t, repo, "dir/moved.txt"))
+	assert.NoError(t, repo.RenameFile(context.Background(), "dir/moved.txt", "dir/./moved.txt", false))
+	assert.Equal(t, "b", readFile(t, repo, "dir/moved.txt"))
+	assert.NoError(t, repo.RenameFile(context.Background(), "dir", "dir", false))
+	assert.Equal(t, "b", readFile(t, repo, "dir/moved.txt"))
+
+	assert.NoError(t, repo.RenameFile(context.Background(), "dir", "renamed", false))
+	assert.Equal(t, "b", readFile(t, repo, "renamed/moved.txt"))
+	assertNotExist(t, repo, "dir")
+
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "missing.txt", "other.txt", false), fs.ErrNotExist)
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "renamed", "../outside", false), ErrPathOutsideRoot)
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "", "other", false), ErrStorageRoot)
+}
+
+func testCopyFile(t *testing.T, repo FileRepository) {
+	writeFile(t, repo, "file.txt", "content")
+	writeFile(t, repo, "other.txt", "other")
+	require.NoError(t, repo.MakeDirectory(context.Background(), "dir", false))
+
+	written, err := repo.CopyFile(context.Background(), "file.txt", "dir/copy.txt", false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), written)
+	assert.Equal(t, "content", readFile(t, repo, "dir/copy.txt"))
+	assert.Equal(t, "content", readFile(t, repo, "file.txt"))
+
+	_, err = repo.CopyFile(context.Background(), "other.txt", "dir/copy.txt", false)
+	assert.ErrorIs(t, err, fs.ErrExist)
+	_, err = repo.CopyFile(context.Background(), "other.txt", "dir/copy.txt", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "other", readFile(t, repo, "dir/copy.txt"))
+
+	// The copies are independent of their source
+	assert.NoError(t, repo.DeleteFile(context.Background(), "other.txt", false))
+	writeFile(t, repo, "file.txt", "changed")
+	assert.Equal(t, "other", readFile(t, repo, "dir/copy.txt"))
+
+	_, err = repo.CopyFile(context.Background(), "dir", "dir-copy", false)
+	assert.ErrorIs(t, err, ErrIsDirectory)
+	_, err = repo.CopyFile(context.Background(), "missing.txt", "copy.txt", false)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = repo.CopyFile(context.Background(), "file.txt", "../copy.txt", false)
+	assert.ErrorIs(t, err, ErrPathOutsideRoot)
+}
+
+func testMakeDirectory(t *testing.T, repo FileRepository) {
+	assert.NoError(t, repo.MakeDirectory(context.Background(), "dir", false))
+	fileMetadata, err := repo.GetFileInfo(context.Background(), "dir")
+	require.NoError(t, err)
+	assert.Equal(t, api.EntryType_ENTRY_TYPE_DIRECTORY, fileMetadata.(*api.FileInfoResponse).Type)
+	assert.ErrorIs(t, repo.MakeDirectory(context.Background(), "dir", false), fs.ErrExist)
+
+	assert.ErrorIs(t, repo.MakeDirectory(context.Background(), "a/b/c", false), fs.ErrNotExist)
+	assert.NoError(t, repo.MakeDirectory(context.Background(), "a/b/c", true))
+	assert.NoError(t, repo.MakeDirectory(context.Background(), "a/b/c", true))
+	fileList, err := repo.GetFileList(context.Background(), "a", 0)
+	assert.NoError(t, err)
+	assert.Len(t, fileList, 2)
+
+	// Files can be stored in the new directories
+	writeFile(t, repo, "a/b/c/file.txt", "content")
+	assert.Equal(t, "content", readFile(t, repo, "a/b/c/file.txt"))
+}
+
+func testProbe(t *testing.T, repo FileRepository) {
+	assert.NoError(t, repo.Probe(context.Background()))
+	writeFile(t, repo, "file.txt", "content")
+	assert.NoError(t, repo.Probe(context.Background()))
+}
+
+func testCanceled(t *testing.T, repo FileRepository) {
+	writeFile(t, repo, "dir/file1.txt", "content1")
+	require.NoError(t, repo.MakeDirectory(context.Background(), "dir/sub", false))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.GetFileList(ctx, "", 0)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.GetFileInfo(ctx, "dir/file1.txt")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.GetFileContent(ctx, "dir/file1.txt")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, repo.DeleteFile(ctx, "dir", true), context.Canceled)
+	assert.ErrorIs(t, repo.RenameFile(ctx, "dir/file1.txt", "file2.txt", false), context.Canceled)
+	assert.ErrorIs(t, repo.MakeDirectory(ctx, "new", false), context.Canceled)
+	assert.ErrorIs(t, repo.Probe(ctx), context.Canceled)
+	_, err = repo.CopyFile(ctx, "dir/file1.txt", "copy.txt", false)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Nothing was changed
+	fileList, err := repo.GetFileList(context.Background(), "", 0)
+	assert.NoError(t, err)
+	assert.Len(t, fileList, 3)
+}
+
+func testGetFileReaderCanceled(t *testing.T, repo FileRepository) {
+	writeFile(t, repo, "file1.txt", string(make([]byte, 1<<20)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reader, err := repo.GetFileReader(ctx, "file1.txt", 0, 0)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	buf := make([]byte, 1024)
+	_, err = io.ReadFull(reader, buf)
+	assert.NoError(t, err)
+
+	// Reading stops as soon as the context is canceled
+	cancel()
+	n, err := io.Copy(io.Discard, reader)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, n)
+}
+
+func testGetFileWriterCanceled(t *testing.T, repo FileRepository) {
+	ctx, cancel := context.WithCancel(context.Background())
+	writer, err := repo.GetFileWriter(ctx, "file1.txt")
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("partial"))
+	assert.NoError(t, err)
+
+	// A canceled upload is discarded instead of becoming visible
+	cancel()
+	_, err = writer.Write([]byte(" content"))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, writer.Close(), context.Canceled)
+
+	fileList, err := repo.GetFileList(context.Background(), "", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, fileList)
+}
+
+func testGetFileWriterConcurrent(t *testing.T, repo FileRepository) {
+	const writers = 8
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		go func(i int) {
+			writer, err := repo.GetFileWriter(context.Background(), "file.txt")
+			if err != nil {
+				errs <- err
+				return
+			}
+			if _, err := writer.Write([]byte{'a' + byte(i)}); err != nil {
+				writer.Abort()
+				errs <- err
+				return
+			}
+			errs <- writer.Close()
+		}(i)
+	}
+	for i := 0; i < writers; i++ {
+		assert.NoError(t, <-errs)
+	}
+
+	// One of the writers won
+	content := readFile(t, repo, "file.txt")
+	assert.Len(t, content, 1)
+	assert.GreaterOrEqual(t, content, "a")
+	assert.Less(t, content, string(rune('a'+writers)))
+}
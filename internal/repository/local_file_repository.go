@@ -1,63 +1,703 @@
 package repository
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"filetransfer/api"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// localUploadDir is the directory below the storage root staging the content of uploads until they are complete.
+// It is neither listed nor reachable by clients.
+const localUploadDir = ".filetransfer-uploads"
+
 // LocalFileRepository is an implementation of the FileRepository interface for local file storage.
+// Every requested path is resolved through a PathResolver, so no file outside of the storage path can be reached.
 type LocalFileRepository struct {
-	storagePath string
+	resolver *PathResolver
 }
 
 // NewLocalFileRepository creates a new instance of LocalFileRepository with the specified storage path.
+// Uploads left unfinished by an interrupted server are removed.
 func NewLocalFileRepository(storagePath string) *LocalFileRepository {
+	// No upload is in progress yet, a directory that cannot be removed now is still hidden from clients
+	_ = os.RemoveAll(filepath.Join(storagePath, localUploadDir))
+
 	return &LocalFileRepository{
-		storagePath: storagePath,
+		resolver: NewPathResolver(storagePath),
 	}
 }
 
-// GetFileList retrieves a list of file names available in the local storage.
-func (r *LocalFileRepository) GetFileList() ([]string, error) {
-	files, err := os.ReadDir(r.storagePath)
+// resolve resolves a specific file of the local storage like PathResolver.Resolve.
+// The files staging uploads are reported as missing, so they can neither be read nor modified.
+func (r *LocalFileRepository) resolve(filename string) (string, error) {
+	filePath, err := r.resolver.Resolve(filename)
+	if err != nil {
+		return "", err
+	}
+	if err := r.checkVisible(filePath, filename); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+// checkVisible returns an error matching fs.ErrNotExist if the resolved filePath lies in the upload directory.
+func (r *LocalFileRepository) checkVisible(filePath, filename string) error {
+	uploadPath, err := r.resolver.Resolve(localUploadDir)
+	if err != nil {
+		return err
+	}
+	if filePath == uploadPath || strings.HasPrefix(filePath, uploadPath+string(filepath.Separator)) {
+		return &fs.PathError{Op: "open", Path: filename, Err: fs.ErrNotExist}
+	}
+	return nil
+}
+
+// hideLocation replaces the error at err, if any, with one naming files relative to the storage root,
+// so the location of the storage is only found in the server log.
+func (r *LocalFileRepository) hideLocation(err *error) {
+	if *err != nil {
+		*err = relativeError(*err, r.resolver.roots())
+	}
+}
+
+// GetFileList retrieves the entries below a specific directory of the local storage.
+func (r *LocalFileRepository) GetFileList(ctx context.Context, dir string, maxDepth int) (_ []*api.FileEntry, err error) {
+	defer r.hideLocation(&err)
+
+	relDir, err := CleanPath(dir)
 	if err != nil {
 		return nil, err
 	}
 
-	var fileList []string
+	dirPath, err := r.resolve(relDir)
+	if err != nil {
+		return nil, err
+	}
+	uploadPath, err := r.resolver.Resolve(localUploadDir)
+	if err != nil {
+		return nil, err
+	}
+
+	fileList := []*api.FileEntry{}
+	if err := listDirectory(ctx, dirPath, relDir, uploadPath, 1, maxDepth, &fileList); err != nil {
+		return nil, err
+	}
+
+	return fileList, nil
+}
+
+// listDirectory appends the entries of the directory at dirPath to fileList, recursing into subdirectories
+// while depth has not reached maxDepth. relDir is the path of the directory relative to the storage root,
+// the upload directory at uploadPath is skipped. Listing stops with the error of ctx once it is done.
+func listDirectory(ctx context.Context, dirPath, relDir, uploadPath string, depth, maxDepth int, fileList *[]*api.FileEntry) error {
+	files, err := os.ReadDir(dirPath)
+	if err != nil {
+		return err
+	}
+
 	for _, file := range files {
-		if file.IsDir() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if filepath.Join(dirPath, file.Name()) == uploadPath {
 			continue
 		}
-		fileList = append(fileList, file.Name())
+
+		fileInfo, err := file.Info()
+		if err != nil {
+			// The entry was removed after the directory was read
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+
+		entry := &api.FileEntry{
+			Name: file.Name(),
+			Path: filepath.ToSlash(filepath.Join(relDir, file.Name())),
+			Type: entryType(fileInfo.Mode()),
+		}
+		if entry.Type == api.EntryType_ENTRY_TYPE_FILE {
+			entry.Size = uint64(fileInfo.Size())
+		}
+		*fileList = append(*fileList, entry)
+
+		if entry.Type == api.EntryType_ENTRY_TYPE_DIRECTORY && (maxDepth == 0 || depth < maxDepth) {
+			if err := listDirectory(ctx, filepath.Join(dirPath, file.Name()), filepath.Join(relDir, file.Name()), uploadPath, depth+1, maxDepth, fileList); err != nil {
+				return err
+			}
+		}
 	}
 
-	return fileList, nil
+	return nil
+}
+
+// entryType returns the entry type matching the file mode.
+func entryType(mode os.FileMode) api.EntryType {
+	switch {
+	case mode&os.ModeSymlink != 0:
+		return api.EntryType_ENTRY_TYPE_SYMLINK
+	case mode.IsDir():
+		return api.EntryType_ENTRY_TYPE_DIRECTORY
+	case mode.IsRegular():
+		return api.EntryType_ENTRY_TYPE_FILE
+	}
+	return api.EntryType_ENTRY_TYPE_UNSPECIFIED
 }
 
 // GetFileInfo retrieves metadata information about a specific file from the local storage.
-// It returns an interface{}, which encapsulates details like filename and size.
-func (r *LocalFileRepository) GetFileInfo(filename string) (interface{}, error) {
-	filePath := filepath.Join(r.storagePath, filename)
+// It returns an interface{}, which encapsulates details like filename, size, modification time, mode,
+// owner, entry type and MIME type. Symbolic links are reported with their target and the metadata of the target.
+func (r *LocalFileRepository) GetFileInfo(ctx context.Context, filename string) (_ interface{}, err error) {
+	defer r.hideLocation(&err)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	filePath, err := r.resolve(filename)
+	if err != nil {
+		return nil, err
+	}
+
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
 		return nil, err
 	}
 
 	fileMetadata := &api.FileInfoResponse{
-		Filename: filename,
-		Size:     uint64(fileInfo.Size()),
+		Filename:         filename,
+		Size:             uint64(fileInfo.Size()),
+		ModificationTime: timestamppb.New(fileInfo.ModTime()),
+		Mode:             unixMode(fileInfo.Mode()),
+		Type:             entryType(fileInfo.Mode()),
+	}
+	fileMetadata.Uid, fileMetadata.Gid = fileOwner(fileInfo)
+
+	// Report symbolic links with their target relative to the storage root
+	linkInfo, err := r.lstat(filename)
+	if err != nil {
+		return nil, err
+	}
+	if linkInfo.Mode()&os.ModeSymlink != 0 {
+		rootPath, err := r.resolver.Resolve(".")
+		if err != nil {
+			return nil, err
+		}
+		target, err := filepath.Rel(rootPath, filePath)
+		if err != nil {
+			return nil, err
+		}
+		fileMetadata.Type = api.EntryType_ENTRY_TYPE_SYMLINK
+		fileMetadata.SymlinkTarget = filepath.ToSlash(target)
+	}
+
+	if fileInfo.Mode().IsRegular() {
+		mimeType, err := detectMIMEType(filePath)
+		if err != nil {
+			return nil, err
+		}
+		fileMetadata.MimeType = mimeType
 	}
+
 	return fileMetadata, nil
 }
 
+// lstat returns the metadata of a specific file from the local storage without following a symbolic link
+// in its last path element.
+func (r *LocalFileRepository) lstat(filename string) (os.FileInfo, error) {
+	filePath, err := r.resolveLink(filename)
+	if err != nil {
+		return nil, err
+	}
+	return os.Lstat(filePath)
+}
+
+// resolveLink resolves a specific file of the local storage like PathResolver.Resolve, except that a symbolic link
+// in its last path element is not followed, so that the link itself can be inspected or modified.
+func (r *LocalFileRepository) resolveLink(filename string) (string, error) {
+	cleaned, err := CleanPath(filename)
+	if err != nil {
+		return "", err
+	}
+
+	dirPath, err := r.resolver.Resolve(filepath.Dir(cleaned))
+	if err != nil {
+		return "", err
+	}
+	filePath := filepath.Join(dirPath, filepath.Base(cleaned))
+	if err := r.checkVisible(filePath, filename); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+// resolveModifiable resolves a specific file of the local storage that is about to be modified, without following
+// a symbolic link in its last path element. ErrStorageRoot is returned for the storage root itself.
+func (r *LocalFileRepository) resolveModifiable(filename string) (string, error) {
+	cleaned, err := CleanPath(filename)
+	if err != nil {
+		return "", err
+	}
+	if cleaned == "." {
+		return "", ErrStorageRoot
+	}
+	return r.resolveLink(cleaned)
+}
+
+// unixMode converts the permission and special bits of a file mode to their Unix representation.
+func unixMode(mode os.FileMode) uint32 {
+	unix := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		unix |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		unix |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		unix |= 01000
+	}
+	return unix
+}
+
+// detectMIMEType returns the MIME type of the file at filePath, derived from its extension
+// or, for unknown extensions, from its first bytes.
+func detectMIMEType(filePath string) (string, error) {
+	if mimeType := mime.TypeByExtension(filepath.Ext(filePath)); mimeType != "" {
+		return mimeType, nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	// DetectContentType considers at most the first 512 bytes
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
 // GetFileContent retrieves the content of a specific file from the local storage.
-func (r *LocalFileRepository) GetFileContent(filename string) ([]byte, error) {
-	filePath := filepath.Join(r.storagePath, filename)
-	content, err := os.ReadFile(filePath)
+// Reading stops with the error of ctx once it is done.
+func (r *LocalFileRepository) GetFileContent(ctx context.Context, filename string) (_ []byte, err error) {
+	defer r.hideLocation(&err)
+
+	filePath, err := r.resolve(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
-	return content, nil
+	defer file.Close()
+
+	// Reserving the size of the file up front avoids growing the buffer while reading
+	var content bytes.Buffer
+	if fileInfo, err := file.Stat(); err == nil {
+		content.Grow(int(fileInfo.Size()))
+	}
+	if _, err := content.ReadFrom(&contextReader{ctx: ctx, Reader: file}); err != nil {
+		return nil, err
+	}
+	return content.Bytes(), nil
+}
+
+// GetFileReader opens a specific file from the local storage for sequential reading of the byte range
+// starting at offset. A length of zero reads until the end of the file.
+// The reader fails with the error of ctx once it is done.
+func (r *LocalFileRepository) GetFileReader(ctx context.Context, filename string, offset, length int64) (_ io.ReadCloser, err error) {
+	defer r.hideLocation(&err)
+
+	filePath, err := r.resolve(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if offset < 0 || length < 0 || offset > fileInfo.Size() {
+		file.Close()
+		return nil, ErrInvalidRange
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	var reader io.Reader = file
+	if length > 0 {
+		reader = io.LimitReader(file, length)
+	}
+	return &namedReader{
+		ReadCloser: &readCloser{Reader: &contextReader{ctx: ctx, Reader: reader}, Closer: file},
+		name:       filename,
+	}, nil
+}
+
+// readCloser combines a reader with the closer of its underlying resource.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// resolveWritable resolves a specific file of the local storage whose content is about to be replaced. Unlike
+// resolveModifiable it follows a symbolic link in its last path element, so the target of the link is replaced
+// and a link leading outside of the storage root is rejected. ErrStorageRoot is returned for the storage root itself,
+// also when it is reached through a symbolic link.
+func (r *LocalFileRepository) resolveWritable(filename string) (string, error) {
+	cleaned, err := CleanPath(filename)
+	if err != nil {
+		return "", err
+	}
+	if cleaned == "." {
+		return "", ErrStorageRoot
+	}
+
+	filePath, err := r.resolve(cleaned)
+	if err != nil {
+		return "", err
+	}
+	root, err := r.resolver.Resolve(".")
+	if err != nil {
+		return "", err
+	}
+	if filePath == root {
+		return "", ErrStorageRoot
+	}
+	return filePath, nil
+}
+
+// GetFileWriter creates or replaces a specific file in the local storage.
+// The content is written to a temporary file in the upload directory, which replaces the target file when
+// the writer is closed. ErrIsDirectory is returned if the target is a directory.
+// Once ctx is done, writing fails and closing discards the temporary file.
+func (r *LocalFileRepository) GetFileWriter(ctx context.Context, filename string) (_ FileWriter, err error) {
+	defer r.hideLocation(&err)
+
+	filePath, err := r.resolveWritable(filename)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNotDirectory(filePath, filename); err != nil {
+		return nil, err
+	}
+	return r.newFileWriter(ctx, filePath, filename, true)
+}
+
+// checkNotDirectory returns an error matching ErrIsDirectory if the file at filePath is a directory.
+func checkNotDirectory(filePath, filename string) error {
+	fileInfo, err := os.Stat(filePath)
+	if err == nil && fileInfo.IsDir() {
+		return &fs.PathError{Op: "create", Path: filename, Err: ErrIsDirectory}
+	}
+	return nil
+}
+
+// newFileWriter creates the temporary file of a localFileWriter for the resolved target path of filename in the
+// upload directory, creating missing parent directories of the target. Unless overwrite is set, closing the writer
+// fails if the target exists.
+func (r *LocalFileRepository) newFileWriter(ctx context.Context, filePath, filename string, overwrite bool) (*localFileWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, err
+	}
+
+	uploadPath, err := r.resolver.Resolve(localUploadDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(uploadPath, 0700); err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp(uploadPath, "upload-*")
+	if err != nil {
+		return nil, err
+	}
+	return &localFileWriter{File: file, ctx: ctx, target: filePath, name: filename, overwrite: overwrite}, nil
+}
+
+// localFileWriter is a FileWriter that writes to a temporary file and moves it into place on Close.
+type localFileWriter struct {
+	*os.File
+	ctx    context.Context
+	target string
+	// name is the target as requested by the client.
+	name      string
+	overwrite bool
+}
+
+// Write writes to the temporary file unless the context of the writer is done.
+func (w *localFileWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := w.File.Write(p)
+	return n, namedError(err, w.name)
+}
+
+// ReadFrom copies from the reader through Write, so the copy stops once the context of the writer is done.
+func (w *localFileWriter) ReadFrom(reader io.Reader) (int64, error) {
+	return io.Copy(struct{ io.Writer }{w}, reader)
+}
+
+// Close commits the written content by renaming the temporary file to the target path,
+// unless the context of the writer is done. A writer that may not overwrite the target fails
+// with an error matching fs.ErrExist if the target exists by then. Errors name the target as requested,
+// not the temporary file.
+func (w *localFileWriter) Close() error {
+	if err := w.ctx.Err(); err != nil {
+		w.Abort()
+		return err
+	}
+	if err := w.File.Close(); err != nil {
+		os.Remove(w.File.Name())
+		return namedError(err, w.name)
+	}
+	if err := os.Chmod(w.File.Name(), 0644); err != nil {
+		os.Remove(w.File.Name())
+		return namedError(err, w.name)
+	}
+	rename := os.Rename
+	if !w.overwrite {
+		rename = renameNoReplace
+	}
+	if err := rename(w.File.Name(), w.target); err != nil {
+		os.Remove(w.File.Name())
+		// A directory created at the target while the content was written is not replaced
+		if dirErr := checkNotDirectory(w.target, w.name); dirErr != nil {
+			return dirErr
+		}
+		return namedError(err, w.name)
+	}
+	return nil
+}
+
+// Abort discards the temporary file.
+func (w *localFileWriter) Abort() error {
+	w.File.Close()
+	return namedError(os.Remove(w.File.Name()), w.name)
+}
+
+// DeleteFile deletes a specific file or directory from the local storage.
+// A symbolic link is deleted itself, its target is left untouched.
+func (r *LocalFileRepository) DeleteFile(ctx context.Context, filename string, recursive bool) (err error) {
+	defer r.hideLocation(&err)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	filePath, err := r.resolveModifiable(filename)
+	if err != nil {
+		return err
+	}
+
+	fileInfo, err := os.Lstat(filePath)
+	if err != nil {
+		return err
+	}
+
+	if fileInfo.IsDir() {
+		if recursive {
+			return os.RemoveAll(filePath)
+		}
+
+		entries, err := os.ReadDir(filePath)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			return ErrDirectoryNotEmpty
+		}
+	}
+
+	return os.Remove(filePath)
+}
+
+// RenameFile renames or moves a file or directory within the local storage.
+// Missing parent directories of the destination are created, renaming a file to itself has no effect.
+func (r *LocalFileRepository) RenameFile(ctx context.Context, source, destination string, overwrite bool) (err error) {
+	defer r.hideLocation(&err)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	sourcePath, err := r.resolveModifiable(source)
+	if err != nil {
+		return err
+	}
+	destinationPath, err := r.resolveModifiable(destination)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Lstat(sourcePath); err != nil {
+		return err
+	}
+	if sourcePath == destinationPath {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destinationPath), 0755); err != nil {
+		return err
+	}
+	if overwrite {
+		return os.Rename(sourcePath, destinationPath)
+	}
+	return destinationError(renameNoReplace(sourcePath, destinationPath), destination)
+}
+
+// CopyFile copies a file within the local storage.
+// The copy is written like an upload, so an existing destination is replaced atomically.
+// Copying stops with the error of ctx once it is done, leaving the destination untouched.
+func (r *LocalFileRepository) CopyFile(ctx context.Context, source, destination string, overwrite bool) (_ int64, err error) {
+	defer r.hideLocation(&err)
+
+	sourcePath, err := r.resolve(source)
+	if err != nil {
+		return 0, err
+	}
+	destinationPath, err := r.resolveWritable(destination)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.Open(sourcePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if fileInfo.IsDir() {
+		return 0, ErrIsDirectory
+	}
+
+	// Fail early if the destination exists, the writer checks again when it moves the copy into place
+	if err := checkDestination(destinationPath, destination, overwrite); err != nil {
+		return 0, err
+	}
+	if err := checkNotDirectory(destinationPath, destination); err != nil {
+		return 0, err
+	}
+
+	writer, err := r.newFileWriter(ctx, destinationPath, destination, overwrite)
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := io.Copy(writer, &contextReader{ctx: ctx, Reader: file})
+	if err != nil {
+		writer.Abort()
+		return written, err
+	}
+	return written, destinationError(writer.Close(), destination)
+}
+
+// checkDestination returns an error matching fs.ErrExist if the destination exists and may not be overwritten.
+func checkDestination(destinationPath, destination string, overwrite bool) error {
+	if overwrite {
+		return nil
+	}
+
+	_, err := os.Lstat(destinationPath)
+	if err == nil {
+		return &fs.PathError{Op: "create", Path: destination, Err: fs.ErrExist}
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// destinationError replaces an error matching fs.ErrExist with one naming only the destination as requested,
+// neither the source nor a temporary file moved to it.
+func destinationError(err error, destination string) error {
+	if errors.Is(err, fs.ErrExist) {
+		return &fs.PathError{Op: "create", Path: destination, Err: fs.ErrExist}
+	}
+	return err
+}
+
+// MakeDirectory creates a directory in the local storage.
+func (r *LocalFileRepository) MakeDirectory(ctx context.Context, path string, parents bool) (err error) {
+	defer r.hideLocation(&err)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	dirPath, err := r.resolve(path)
+	if err != nil {
+		return err
+	}
+
+	if parents {
+		return os.MkdirAll(dirPath, 0755)
+	}
+	return os.Mkdir(dirPath, 0755)
+}
+
+// ResolveLinks returns the path relative to the storage root that a path of the local storage refers to
+// once every symbolic link on it is followed.
+func (r *LocalFileRepository) ResolveLinks(ctx context.Context, name string) (_ string, err error) {
+	defer r.hideLocation(&err)
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return r.resolver.ResolveRelative(name)
+}
+
+// Probe checks that the storage root of the local storage is a readable directory.
+func (r *LocalFileRepository) Probe(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rootPath, err := r.resolver.Resolve("")
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.Open(rootPath)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	// Reading a single entry proves the directory can be listed, an empty directory reports io.EOF
+	if _, err := dir.ReadDir(1); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
 }
@@ -1,12 +1,19 @@
 package repository
 
 import (
+	"context"
 	"filetransfer/api"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestLocalFileRepository_GetFileList(t *testing.T) {
@@ -21,10 +28,69 @@ func TestLocalFileRepository_GetFileList(t *testing.T) {
 
 	repo := NewLocalFileRepository(tempDir)
 
-	fileList, err := repo.GetFileList()
+	fileList, err := repo.GetFileList(context.Background(), "", 1)
 	assert.NoError(t, err)
 
-	assert.ElementsMatch(t, []string{"file1.txt", "file2.txt"}, fileList)
+	assert.Equal(t, []*api.FileEntry{
+		{Name: "file1.txt", Path: "file1.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 8},
+		{Name: "file2.txt", Path: "file2.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 8},
+	}, fileList)
+}
+
+func TestLocalFileRepository_GetFileList_Directories(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, "dir", "sub", "deep"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "dir", "a.txt"), []byte("a"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "dir", "sub", "b.txt"), []byte("bb"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "dir", "sub", "deep", "c.txt"), []byte("ccc"), 0644))
+	assert.NoError(t, os.Symlink("a.txt", filepath.Join(tempDir, "dir", "link")))
+
+	repo := NewLocalFileRepository(tempDir)
+
+	dir := &api.FileEntry{Name: "dir", Path: "dir", Type: api.EntryType_ENTRY_TYPE_DIRECTORY}
+	a := &api.FileEntry{Name: "a.txt", Path: "dir/a.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 1}
+	link := &api.FileEntry{Name: "link", Path: "dir/link", Type: api.EntryType_ENTRY_TYPE_SYMLINK}
+	sub := &api.FileEntry{Name: "sub", Path: "dir/sub", Type: api.EntryType_ENTRY_TYPE_DIRECTORY}
+	b := &api.FileEntry{Name: "b.txt", Path: "dir/sub/b.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 2}
+	deep := &api.FileEntry{Name: "deep", Path: "dir/sub/deep", Type: api.EntryType_ENTRY_TYPE_DIRECTORY}
+	c := &api.FileEntry{Name: "c.txt", Path: "dir/sub/deep/c.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 3}
+
+	// The root lists directories instead of skipping them
+	fileList, err := repo.GetFileList(context.Background(), "", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{dir}, fileList)
+
+	fileList, err = repo.GetFileList(context.Background(), "dir", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{a, link, sub}, fileList)
+
+	fileList, err = repo.GetFileList(context.Background(), "dir", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{a, link, sub, b, deep}, fileList)
+
+	fileList, err = repo.GetFileList(context.Background(), "./dir/", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{a, link, sub, b, deep, c}, fileList)
+
+	fileList, err = repo.GetFileList(context.Background(), "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{dir, a, link, sub, b, deep, c}, fileList)
+}
+
+func TestLocalFileRepository_GetFileList_Errors(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "file.txt"), []byte("content"), 0644))
+
+	repo := NewLocalFileRepository(tempDir)
+
+	_, err := repo.GetFileList(context.Background(), "missing", 1)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = repo.GetFileList(context.Background(), "file.txt", 1)
+	assert.Error(t, err)
+
+	_, err = repo.GetFileList(context.Background(), "../", 1)
+	assert.ErrorIs(t, err, ErrPathOutsideRoot)
 }
 
 func TestLocalFileRepository_GetFileInfo(t *testing.T) {
@@ -33,21 +99,62 @@ func TestLocalFileRepository_GetFileInfo(t *testing.T) {
 
 	err := os.WriteFile(file, []byte("content"), 0644)
 	assert.NoError(t, err)
+	assert.NoError(t, os.Chmod(file, 0640))
+	modTime := time.Date(2023, 11, 14, 10, 30, 0, 0, time.UTC)
+	assert.NoError(t, os.Chtimes(file, modTime, modTime))
 
 	repo := NewLocalFileRepository(tempDir)
 
-	fileInfoInterface, err := repo.GetFileInfo("file.txt")
+	fileInfoInterface, err := repo.GetFileInfo(context.Background(), "file.txt")
 	assert.NoError(t, err)
 
 	fileInfo, ok := fileInfoInterface.(*api.FileInfoResponse)
 	assert.True(t, ok, "expected *FileInfoResponse")
 
 	expectedFileInfo := &api.FileInfoResponse{
-		Filename: "file.txt",
-		Size:     7,
+		Filename:         "file.txt",
+		Size:             7,
+		ModificationTime: timestamppb.New(modTime),
+		Mode:             0640,
+		Uid:              uint32(os.Getuid()),
+		Gid:              uint32(os.Getgid()),
+		Type:             api.EntryType_ENTRY_TYPE_FILE,
+		MimeType:         "text/plain; charset=utf-8",
 	}
 
-	assert.Equal(t, expectedFileInfo, fileInfo)
+	assert.True(t, proto.Equal(expectedFileInfo, fileInfo), "unexpected file info %v", fileInfo)
+}
+
+func TestLocalFileRepository_GetFileInfo_Types(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, "dir"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "dir", "data.json"), []byte("{}"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "image"), []byte("\x89PNG\r\n\x1a\n"), 0644))
+	assert.NoError(t, os.Symlink("dir/data.json", filepath.Join(tempDir, "link")))
+
+	repo := NewLocalFileRepository(tempDir)
+
+	// MIME types are derived from the extension or, without a known extension, from the content
+	fileInfo, err := repo.GetFileInfo(context.Background(), "dir/data.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", fileInfo.(*api.FileInfoResponse).MimeType)
+
+	fileInfo, err = repo.GetFileInfo(context.Background(), "image")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", fileInfo.(*api.FileInfoResponse).MimeType)
+
+	fileInfo, err = repo.GetFileInfo(context.Background(), "dir")
+	assert.NoError(t, err)
+	assert.Equal(t, api.EntryType_ENTRY_TYPE_DIRECTORY, fileInfo.(*api.FileInfoResponse).Type)
+	assert.Empty(t, fileInfo.(*api.FileInfoResponse).MimeType)
+
+	// Symbolic links are reported with their target and the metadata of the target
+	fileInfo, err = repo.GetFileInfo(context.Background(), "link")
+	assert.NoError(t, err)
+	assert.Equal(t, api.EntryType_ENTRY_TYPE_SYMLINK, fileInfo.(*api.FileInfoResponse).Type)
+	assert.Equal(t, "dir/data.json", fileInfo.(*api.FileInfoResponse).SymlinkTarget)
+	assert.Equal(t, uint64(2), fileInfo.(*api.FileInfoResponse).Size)
+	assert.Equal(t, "application/json", fileInfo.(*api.FileInfoResponse).MimeType)
 }
 
 func TestLocalFileRepository_GetFileContent(t *testing.T) {
@@ -59,8 +166,520 @@ func TestLocalFileRepository_GetFileContent(t *testing.T) {
 
 	repo := NewLocalFileRepository(tempDir)
 
-	content, err := repo.GetFileContent("file.txt")
+	content, err := repo.GetFileContent(context.Background(), "file.txt")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []byte("content"), content)
+}
+
+func TestLocalFileRepository_GetFileReader(t *testing.T) {
+	tempDir := t.TempDir()
+	file := filepath.Join(tempDir, "file.txt")
+
+	err := os.WriteFile(file, []byte("content"), 0644)
+	assert.NoError(t, err)
+
+	repo := NewLocalFileRepository(tempDir)
+
+	reader, err := repo.GetFileReader(context.Background(), "file.txt", 0, 0)
+	assert.NoError(t, err)
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []byte("content"), content)
+}
+
+func TestLocalFileRepository_GetFileReader_Range(t *testing.T) {
+	tempDir := t.TempDir()
+	file := filepath.Join(tempDir, "file.txt")
+
+	err := os.WriteFile(file, []byte("0123456789"), 0644)
+	assert.NoError(t, err)
+
+	repo := NewLocalFileRepository(tempDir)
+
+	reader, err := repo.GetFileReader(context.Background(), "file.txt", 3, 4)
+	assert.NoError(t, err)
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("3456"), content)
+
+	reader, err = repo.GetFileReader(context.Background(), "file.txt", 7, 0)
+	assert.NoError(t, err)
+	defer reader.Close()
+
+	content, err = io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("789"), content)
+}
+
+func TestLocalFileRepository_GetFileReader_InvalidRange(t *testing.T) {
+	tempDir := t.TempDir()
+	file := filepath.Join(tempDir, "file.txt")
+
+	err := os.WriteFile(file, []byte("content"), 0644)
+	assert.NoError(t, err)
+
+	repo := NewLocalFileRepository(tempDir)
+
+	_, err = repo.GetFileReader(context.Background(), "file.txt", 8, 0)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
+
+func TestLocalFileRepository_GetFileWriter(t *testing.T) {
+	tempDir := t.TempDir()
+
+	repo := NewLocalFileRepository(tempDir)
+
+	writer, err := repo.GetFileWriter(context.Background(), filepath.Join("dir", "file.txt"))
+	assert.NoError(t, err)
+
+	_, err = writer.Write([]byte("content"))
 	assert.NoError(t, err)
 
+	// The file must not be visible before the writer is closed
+	_, err = os.Stat(filepath.Join(tempDir, "dir", "file.txt"))
+	assert.True(t, os.IsNotExist(err))
+
+	err = writer.Close()
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "dir", "file.txt"))
+	assert.NoError(t, err)
 	assert.Equal(t, []byte("content"), content)
+
+	entries, err := os.ReadDir(filepath.Join(tempDir, "dir"))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestLocalFileRepository_GetFileWriter_StorageRoot(t *testing.T) {
+	tempDir := t.TempDir()
+	storage := filepath.Join(tempDir, "storage")
+	assert.NoError(t, os.Mkdir(storage, 0755))
+	assert.NoError(t, os.Symlink(".", filepath.Join(storage, "root")))
+
+	repo := NewLocalFileRepository(storage)
+
+	// The storage root cannot be replaced, so nothing is written next to it
+	for _, name := range []string{"", ".", "dir/..", "root"} {
+		_, err := repo.GetFileWriter(context.Background(), name)
+		assert.ErrorIs(t, err, ErrStorageRoot, name)
+	}
+
+	entries, err := os.ReadDir(tempDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	entries, err = os.ReadDir(storage)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestLocalFileRepository_GetFileWriter_Abort(t *testing.T) {
+	tempDir := t.TempDir()
+	file := filepath.Join(tempDir, "file.txt")
+
+	err := os.WriteFile(file, []byte("original"), 0644)
+	assert.NoError(t, err)
+
+	repo := NewLocalFileRepository(tempDir)
+
+	writer, err := repo.GetFileWriter(context.Background(), "file.txt")
+	assert.NoError(t, err)
+
+	_, err = writer.Write([]byte("partial"))
+	assert.NoError(t, err)
+
+	err = writer.Abort()
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("original"), content)
+
+	entries, err := os.ReadDir(filepath.Join(tempDir, localUploadDir))
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLocalFileRepository_PathOutsideRoot(t *testing.T) {
+	tempDir := t.TempDir()
+	storage := filepath.Join(tempDir, "storage")
+
+	err := os.MkdirAll(storage, 0755)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tempDir, "secret.txt"), []byte("secret"), 0644)
+	assert.NoError(t, err)
+	err = os.Symlink(filepath.Join(tempDir, "secret.txt"), filepath.Join(storage, "link.txt"))
+	assert.NoError(t, err)
+
+	repo := NewLocalFileRepository(storage)
+
+	for _, name := range []string{"../secret.txt", filepath.Join(tempDir, "secret.txt"), "link.txt"} {
+		_, err = repo.GetFileInfo(context.Background(), name)
+		assert.ErrorIs(t, err, ErrPathOutsideRoot, name)
+
+		_, err = repo.GetFileContent(context.Background(), name)
+		assert.ErrorIs(t, err, ErrPathOutsideRoot, name)
+
+		_, err = repo.GetFileReader(context.Background(), name, 0, 0)
+		assert.ErrorIs(t, err, ErrPathOutsideRoot, name)
+
+		_, err = repo.GetFileWriter(context.Background(), name)
+		assert.ErrorIs(t, err, ErrPathOutsideRoot, name)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "secret.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), content)
+}
+
+func TestLocalFileRepository_DeleteFile(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, "dir", "sub"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, "empty"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "file.txt"), []byte("content"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "dir", "sub", "file.txt"), []byte("content"), 0644))
+	assert.NoError(t, os.Symlink("file.txt", filepath.Join(tempDir, "link")))
+
+	repo := NewLocalFileRepository(tempDir)
+
+	// Symbolic links are deleted without their target
+	assert.NoError(t, repo.DeleteFile(context.Background(), "link", false))
+	assert.NoFileExists(t, filepath.Join(tempDir, "link"))
+	assert.FileExists(t, filepath.Join(tempDir, "file.txt"))
+
+	assert.NoError(t, repo.DeleteFile(context.Background(), "file.txt", false))
+	assert.NoFileExists(t, filepath.Join(tempDir, "file.txt"))
+
+	assert.NoError(t, repo.DeleteFile(context.Background(), "empty", false))
+	assert.NoDirExists(t, filepath.Join(tempDir, "empty"))
+
+	assert.ErrorIs(t, repo.DeleteFile(context.Background(), "dir", false), ErrDirectoryNotEmpty)
+	assert.NoError(t, repo.DeleteFile(context.Background(), "dir", true))
+	assert.NoDirExists(t, filepath.Join(tempDir, "dir"))
+
+	assert.ErrorIs(t, repo.DeleteFile(context.Background(), "missing.txt", false), os.ErrNotExist)
+	assert.ErrorIs(t, repo.DeleteFile(context.Background(), ".", true), ErrStorageRoot)
+	assert.ErrorIs(t, repo.DeleteFile(context.Background(), "../file.txt", false), ErrPathOutsideRoot)
+	assert.DirExists(t, tempDir)
+}
+
+func TestLocalFileRepository_RenameFile(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "a.txt"), []byte("a"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "b.txt"), []byte("b"), 0644))
+
+	repo := NewLocalFileRepository(tempDir)
+
+	// Moving creates missing parent directories
+	assert.NoError(t, repo.RenameFile(context.Background(), "a.txt", "dir/moved.txt", false))
+	assert.NoFileExists(t, filepath.Join(tempDir, "a.txt"))
+	content, err := os.ReadFile(filepath.Join(tempDir, "dir", "moved.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("a"), content)
+
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "b.txt", "dir/moved.txt", false), os.ErrExist)
+	assert.NoError(t, repo.RenameFile(context.Background(), "b.txt", "dir/moved.txt", true))
+	content, err = os.ReadFile(filepath.Join(tempDir, "dir", "moved.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("b"), content)
+
+	assert.NoError(t, repo.RenameFile(context.Background(), "dir", "renamed", false))
+	assert.FileExists(t, filepath.Join(tempDir, "renamed", "moved.txt"))
+
+	// An existing directory is not replaced, even an empty one
+	assert.NoError(t, os.Mkdir(filepath.Join(tempDir, "empty"), 0755))
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "renamed", "empty", false), os.ErrExist)
+	assert.FileExists(t, filepath.Join(tempDir, "renamed", "moved.txt"))
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "renamed", "renamed/sub", false), syscall.EINVAL)
+	assert.NoDirExists(t, filepath.Join(tempDir, "renamed", "sub"))
+
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "missing.txt", "other.txt", false), os.ErrNotExist)
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "renamed", "../outside", false), ErrPathOutsideRoot)
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "", "other", false), ErrStorageRoot)
+}
+
+func TestLocalFileRepository_CopyFile(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, "dir"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "file.txt"), []byte("content"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "other.txt"), []byte("other"), 0644))
+
+	repo := NewLocalFileRepository(tempDir)
+
+	written, err := repo.CopyFile(context.Background(), "file.txt", "dir/copy.txt", false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), written)
+	content, err := os.ReadFile(filepath.Join(tempDir, "dir", "copy.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("content"), content)
+	assert.FileExists(t, filepath.Join(tempDir, "file.txt"))
+
+	_, err = repo.CopyFile(context.Background(), "other.txt", "dir/copy.txt", false)
+	assert.ErrorIs(t, err, os.ErrExist)
+	_, err = repo.CopyFile(context.Background(), "other.txt", "dir/copy.txt", true)
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(tempDir, "dir", "copy.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("other"), content)
+
+	_, err = repo.CopyFile(context.Background(), "dir", "dir-copy", false)
+	assert.ErrorIs(t, err, ErrIsDirectory)
+	_, err = repo.CopyFile(context.Background(), "missing.txt", "copy.txt", false)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = repo.CopyFile(context.Background(), "file.txt", "../copy.txt", false)
+	assert.ErrorIs(t, err, ErrPathOutsideRoot)
+}
+
+func TestLocalFileRepository_CopyFile_SymbolicLink(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "file.txt"), []byte("content"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "target.txt"), []byte("target"), 0644))
+	assert.NoError(t, os.Symlink("target.txt", filepath.Join(tempDir, "link.txt")))
+
+	repo := NewLocalFileRepository(tempDir)
+
+	// The destination is checked and written through the same resolution of the link
+	_, err := repo.CopyFile(context.Background(), "file.txt", "link.txt", false)
+	assert.ErrorIs(t, err, os.ErrExist)
+	_, err = repo.CopyFile(context.Background(), "file.txt", "link.txt", true)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(tempDir, "target.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("content"), content)
+	target, err := os.Readlink(filepath.Join(tempDir, "link.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "target.txt", target)
+}
+
+func TestLocalFileRepository_ResolveLinks(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, "public"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, "secret"), 0755))
+	assert.NoError(t, os.Symlink("../secret", filepath.Join(tempDir, "public", "link")))
+
+	repo := NewLocalFileRepository(tempDir)
+
+	// Links are resolved on every component, missing files below them included
+	resolved, err := repo.ResolveLinks(context.Background(), "public/link/missing.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret/missing.txt", resolved)
+	resolved, err = repo.ResolveLinks(context.Background(), "public/./")
+	assert.NoError(t, err)
+	assert.Equal(t, "public", resolved)
+
+	// Paths leading outside of the root are rejected
+	_, err = repo.ResolveLinks(context.Background(), "../outside")
+	assert.ErrorIs(t, err, ErrPathOutsideRoot)
+}
+
+func TestLocalFileWriter_NoOverwrite(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "file.txt")
+
+	writer, err := NewLocalFileRepository(tempDir).newFileWriter(context.Background(), filePath, "file.txt", false)
+	assert.NoError(t, err)
+	_, err = writer.Write([]byte("copy"))
+	assert.NoError(t, err)
+
+	// A target created while the content is written is not replaced
+	assert.NoError(t, os.WriteFile(filePath, []byte("concurrent"), 0644))
+	assert.ErrorIs(t, writer.Close(), os.ErrExist)
+
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("concurrent"), content)
+	entries, err := os.ReadDir(filepath.Join(tempDir, localUploadDir))
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLocalFileRepository_UploadDirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, "dir"), 0755))
+
+	// Leftovers of an interrupted server are removed
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, localUploadDir), 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, localUploadDir, "upload-1"), []byte("partial"), 0600))
+	repo := NewLocalFileRepository(tempDir)
+	assert.NoDirExists(t, filepath.Join(tempDir, localUploadDir))
+
+	// Uploads in progress are neither listed nor reachable
+	writer, err := repo.GetFileWriter(context.Background(), "file.txt")
+	assert.NoError(t, err)
+	_, err = writer.Write([]byte("content"))
+	assert.NoError(t, err)
+
+	fileList, err := repo.GetFileList(context.Background(), "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{{Name: "dir", Path: "dir", Type: api.EntryType_ENTRY_TYPE_DIRECTORY}}, fileList)
+	_, err = repo.GetFileInfo(context.Background(), localUploadDir)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = repo.GetFileList(context.Background(), localUploadDir, 0)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = repo.GetFileContent(context.Background(), localUploadDir+"/"+filepath.Base(writer.(*localFileWriter).Name()))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.ErrorIs(t, repo.DeleteFile(context.Background(), localUploadDir, true), os.ErrNotExist)
+	_, err = repo.GetFileWriter(context.Background(), localUploadDir+"/file.txt")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	assert.NoError(t, writer.Close())
+	assert.Equal(t, "content", readFile(t, repo, "file.txt"))
+
+	// Writing to a directory fails before any content is accepted
+	_, err = repo.GetFileWriter(context.Background(), "dir")
+	assert.ErrorIs(t, err, ErrIsDirectory)
+	_, err = repo.CopyFile(context.Background(), "file.txt", "dir", true)
+	assert.ErrorIs(t, err, ErrIsDirectory)
+}
+
+func TestLocalFileRepository_StorageErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, "dir"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "file.txt"), []byte("content"), 0644))
+	repo := NewLocalFileRepository(tempDir)
+	ctx := context.Background()
+
+	// Errors name files relative to the storage root, the location is kept for the server log
+	_, err := repo.GetFileInfo(ctx, "missing/file.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.EqualError(t, err, "stat missing/file.txt: no such file or directory")
+	var storageErr *StorageError
+	if assert.ErrorAs(t, err, &storageErr) {
+		assert.Contains(t, storageErr.Location.Error(), tempDir)
+	}
+
+	err = repo.MakeDirectory(ctx, "missing/dir", false)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.EqualError(t, err, "mkdir missing/dir: no such file or directory")
+
+	err = repo.RenameFile(ctx, "file.txt", "dir", true)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), tempDir)
+
+	// Errors of reading and writing name the file as requested
+	reader, err := repo.GetFileReader(ctx, "dir", 0, 0)
+	assert.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.EqualError(t, err, "read dir: is a directory")
+	assert.NoError(t, reader.Close())
+
+	writer, err := repo.GetFileWriter(ctx, "new.txt")
+	assert.NoError(t, err)
+	assert.NoError(t, os.Remove(writer.(*localFileWriter).Name()))
+	err = writer.Close()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NotContains(t, err.Error(), tempDir)
+	assert.NotContains(t, err.Error(), localUploadDir)
+}
+
+func TestLocalFileRepository_MakeDirectory(t *testing.T) {
+	tempDir := t.TempDir()
+
+	repo := NewLocalFileRepository(tempDir)
+
+	assert.NoError(t, repo.MakeDirectory(context.Background(), "dir", false))
+	assert.DirExists(t, filepath.Join(tempDir, "dir"))
+	assert.ErrorIs(t, repo.MakeDirectory(context.Background(), "dir", false), os.ErrExist)
+
+	assert.ErrorIs(t, repo.MakeDirectory(context.Background(), "a/b/c", false), os.ErrNotExist)
+	assert.NoError(t, repo.MakeDirectory(context.Background(), "a/b/c", true))
+	assert.DirExists(t, filepath.Join(tempDir, "a", "b", "c"))
+	assert.NoError(t, repo.MakeDirectory(context.Background(), "a/b/c", true))
+
+	assert.ErrorIs(t, repo.MakeDirectory(context.Background(), "../outside", true), ErrPathOutsideRoot)
+}
+
+func TestLocalFileRepository_Probe(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "file.txt"), []byte("content"), 0644))
+
+	assert.NoError(t, NewLocalFileRepository(tempDir).Probe(context.Background()))
+	assert.NoError(t, NewLocalFileRepository(t.TempDir()).Probe(context.Background()))
+
+	// A missing root or a file instead of a directory is not available
+	assert.ErrorIs(t, NewLocalFileRepository(filepath.Join(tempDir, "missing")).Probe(context.Background()), fs.ErrNotExist)
+	assert.Error(t, NewLocalFileRepository(filepath.Join(tempDir, "file.txt")).Probe(context.Background()))
+}
+
+func TestLocalFileRepository_Canceled(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, "dir", "sub"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "dir", "file1.txt"), []byte("content1"), 0644))
+	repo := NewLocalFileRepository(tempDir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.GetFileList(ctx, "", 0)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.GetFileInfo(ctx, "dir/file1.txt")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.GetFileContent(ctx, "dir/file1.txt")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, repo.DeleteFile(ctx, "dir", true), context.Canceled)
+	assert.ErrorIs(t, repo.RenameFile(ctx, "dir/file1.txt", "file2.txt", false), context.Canceled)
+	assert.ErrorIs(t, repo.MakeDirectory(ctx, "new", false), context.Canceled)
+	assert.ErrorIs(t, repo.Probe(ctx), context.Canceled)
+
+	// Nothing was changed
+	_, err = repo.CopyFile(ctx, "dir/file1.txt", "copy.txt", false)
+	assert.ErrorIs(t, err, context.Canceled)
+	fileList, err := repo.GetFileList(context.Background(), "", 0)
+	assert.NoError(t, err)
+	assert.Len(t, fileList, 3)
+}
+
+func TestLocalFileRepository_GetFileReader_Canceled(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "file1.txt"), make([]byte, 1<<20), 0644))
+	repo := NewLocalFileRepository(tempDir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reader, err := repo.GetFileReader(ctx, "file1.txt", 0, 0)
+	assert.NoError(t, err)
+	defer reader.Close()
+
+	buf := make([]byte, 1024)
+	_, err = io.ReadFull(reader, buf)
+	assert.NoError(t, err)
+
+	// Reading stops as soon as the context is canceled
+	cancel()
+	n, err := io.Copy(io.Discard, reader)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, n)
+}
+
+func TestLocalFileRepository_GetFileWriter_Canceled(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := NewLocalFileRepository(tempDir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	writer, err := repo.GetFileWriter(ctx, "file1.txt")
+	assert.NoError(t, err)
+	_, err = writer.Write([]byte("partial"))
+	assert.NoError(t, err)
+
+	// A canceled upload is discarded instead of becoming visible
+	cancel()
+	_, err = writer.Write([]byte(" content"))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, writer.Close(), context.Canceled)
+
+	entries, err := os.ReadDir(filepath.Join(tempDir, localUploadDir))
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.NoFileExists(t, filepath.Join(tempDir, "file1.txt"))
+}
+
+func TestLocalFileRepository_Behavior(t *testing.T) {
+	testFileRepository(t, func(t *testing.T) FileRepository {
+		return NewLocalFileRepository(t.TempDir())
+	})
 }
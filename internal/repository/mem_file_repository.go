@@ -0,0 +1,619 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"filetransfer/api"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MemConfig holds the size limits of a MemFileRepository, zero values disable the respective limit.
+type MemConfig struct {
+	// MaxFileSize is the maximum size of a single file.
+	MaxFileSize int64
+	// MaxSize is the maximum size of all files together.
+	MaxSize int64
+}
+
+// MemFileRepository is an implementation of the FileRepository interface keeping a directory tree of files in memory,
+// for tests and ephemeral servers whose files need not outlive the process.
+//
+// A single lock guards the tree, so every operation sees and leaves a consistent tree. The content of a file is never
+// modified once stored: writers collect the content in a buffer of their own until they are closed, copies share the
+// content of their source and readers keep reading the content they opened even if the file is replaced meanwhile.
+// Writes and copies exceeding a size limit fail with ErrStorageFull. The content buffered by open writers is reserved
+// against the total size limit as it is written, so the limit bounds the memory used by concurrent uploads as well.
+// Files are reported with the permissions 0644,
+// directories with 0755, both without an owner, symbolic links are not supported.
+type MemFileRepository struct {
+	config MemConfig
+
+	// mu guards the tree and the size of the stored content.
+	mu   sync.RWMutex
+	root *memNode
+	// size is the size of all files together.
+	size int64
+	// reserved is the size of the content buffered by open writers, which is counted against MaxSize.
+	reserved int64
+}
+
+// memNode is a file or a directory of a MemFileRepository.
+type memNode struct {
+	// children contains the entries of a directory by name, it is nil for files.
+	children map[string]*memNode
+	content  []byte
+	modTime  time.Time
+}
+
+// newMemDirectory returns an empty directory.
+func newMemDirectory() *memNode {
+	return &memNode{children: make(map[string]*memNode), modTime: time.Now()}
+}
+
+// isDir reports whether the node is a directory.
+func (n *memNode) isDir() bool {
+	return n.children != nil
+}
+
+// treeSize returns the size of the files in the tree of the node.
+func (n *memNode) treeSize() int64 {
+	size := int64(len(n.content))
+	for _, child := range n.children {
+		size += child.treeSize()
+	}
+	return size
+}
+
+// NewMemFileRepository creates a new, empty instance of MemFileRepository limited by cfg.
+func NewMemFileRepository(cfg MemConfig) *MemFileRepository {
+	return &MemFileRepository{config: cfg, root: newMemDirectory()}
+}
+
+// splitMemPath cleans a client-supplied path and returns its components, the root having none.
+func splitMemPath(name string) ([]string, error) {
+	cleanName, err := CleanPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if cleanName == "." {
+		return nil, nil
+	}
+	return strings.Split(filepath.ToSlash(cleanName), "/"), nil
+}
+
+// splitModifiable splits a client-supplied path that is about to be modified like splitMemPath.
+// ErrStorageRoot is returned for the storage root itself.
+func splitModifiable(name string) ([]string, error) {
+	parts, err := splitMemPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, ErrStorageRoot
+	}
+	return parts, nil
+}
+
+// lookup returns the node at the path given by parts. The caller must hold r.mu.
+func (r *MemFileRepository) lookup(parts []string, name string) (*memNode, error) {
+	node := r.root
+	for _, part := range parts {
+		if !node.isDir() {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: syscall.ENOTDIR}
+		}
+		child, ok := node.children[part]
+		if !ok {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// lookupFile returns the node of a specific file, ErrIsDirectory is returned for directories.
+// The caller must hold r.mu.
+func (r *MemFileRepository) lookupFile(filename string) (*memNode, error) {
+	parts, err := splitMemPath(filename)
+	if err != nil {
+		return nil, err
+	}
+	node, err := r.lookup(parts, filename)
+	if err != nil {
+		return nil, err
+	}
+	if node.isDir() {
+		return nil, &fs.PathError{Op: "open", Path: filename, Err: ErrIsDirectory}
+	}
+	return node, nil
+}
+
+// parent returns the directory containing the path given by parts. Missing directories are created if create is set,
+// otherwise an error matching fs.ErrNotExist is returned. The caller must hold r.mu for writing if create is set.
+func (r *MemFileRepository) parent(parts []string, name string, create bool) (*memNode, error) {
+	node := r.root
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := node.children[part]
+		if !ok {
+			if !create {
+				return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+			}
+			child = newMemDirectory()
+			node.children[part] = child
+			node.modTime = child.modTime
+		}
+		if !child.isDir() {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: syscall.ENOTDIR}
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// checkSize returns ErrStorageFull if storing a file of the given size, replacing files of the size replaced,
+// exceeds a limit. The content reserved by open writers counts as stored. The caller must hold r.mu.
+func (r *MemFileRepository) checkSize(size, replaced int64) error {
+	if r.config.MaxFileSize > 0 && size > r.config.MaxFileSize {
+		return ErrStorageFull
+	}
+	if r.config.MaxSize > 0 && r.size+r.reserved-replaced+size > r.config.MaxSize {
+		return ErrStorageFull
+	}
+	return nil
+}
+
+// reserve reserves n bytes of content buffered by a writer against the total size limit,
+// ErrStorageFull is returned if they exceed it.
+func (r *MemFileRepository) reserve(n int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.config.MaxSize > 0 && r.size+r.reserved+n > r.config.MaxSize {
+		return ErrStorageFull
+	}
+	r.reserved += n
+	return nil
+}
+
+// release returns n bytes reserved by a writer. The caller must hold r.mu for writing.
+func (r *MemFileRepository) release(n int64) {
+	r.reserved -= n
+}
+
+// store stores the file node at the path given by parts, replacing an existing file and creating missing parent
+// directories. The destination and the size limits are checked first, so a failed store leaves the tree untouched.
+// The caller must hold r.mu for writing.
+func (r *MemFileRepository) store(parts []string, filename string, file *memNode) error {
+	var replaced int64
+	existing, err := r.lookup(parts, filename)
+	switch {
+	case err == nil:
+		if existing.isDir() {
+			return &fs.PathError{Op: "create", Path: filename, Err: ErrIsDirectory}
+		}
+		replaced = int64(len(existing.content))
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+	if err := r.checkSize(int64(len(file.content)), replaced); err != nil {
+		return err
+	}
+
+	// The path was checked, so only missing directories are created
+	dir, err := r.parent(parts, filename, true)
+	if err != nil {
+		return err
+	}
+	dir.children[parts[len(parts)-1]] = file
+	dir.modTime = file.modTime
+	r.size += int64(len(file.content)) - replaced
+	return nil
+}
+
+// GetFileList retrieves the entries below a specific directory of the tree.
+func (r *MemFileRepository) GetFileList(ctx context.Context, dir string, maxDepth int) ([]*api.FileEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	parts, err := splitMemPath(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	node, err := r.lookup(parts, dir)
+	if err != nil {
+		return nil, err
+	}
+	if !node.isDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: dir, Err: syscall.ENOTDIR}
+	}
+
+	fileList := []*api.FileEntry{}
+	if err := listMemDirectory(ctx, node, strings.Join(parts, "/"), 1, maxDepth, &fileList); err != nil {
+		return nil, err
+	}
+	return fileList, nil
+}
+
+// listMemDirectory appends the entries of the directory node at the path relDir to fileList like listDirectory.
+func listMemDirectory(ctx context.Context, node *memNode, relDir string, depth, maxDepth int, fileList *[]*api.FileEntry) error {
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		child := node.children[name]
+		entry := &api.FileEntry{
+			Name: name,
+			Path: path.Join(relDir, name),
+			Type: api.EntryType_ENTRY_TYPE_FILE,
+			Size: uint64(len(child.content)),
+		}
+		if child.isDir() {
+			entry.Type = api.EntryType_ENTRY_TYPE_DIRECTORY
+		}
+		*fileList = append(*fileList, entry)
+
+		if child.isDir() && (maxDepth == 0 || depth < maxDepth) {
+			if err := listMemDirectory(ctx, child, entry.Path, depth+1, maxDepth, fileList); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetFileInfo retrieves metadata information about a specific file or directory of the tree.
+// The MIME type of files is derived from the extension or, for unknown extensions, from the first bytes of the content.
+func (r *MemFileRepository) GetFileInfo(ctx context.Context, filename string) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	parts, err := splitMemPath(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	node, err := r.lookup(parts, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	fileMetadata := &api.FileInfoResponse{
+		Filename:         filename,
+		ModificationTime: timestamppb.New(node.modTime),
+	}
+	if node.isDir() {
+		fileMetadata.Mode = 0755
+		fileMetadata.Type = api.EntryType_ENTRY_TYPE_DIRECTORY
+		return fileMetadata, nil
+	}
+
+	fileMetadata.Size = uint64(len(node.content))
+	fileMetadata.Mode = 0644
+	fileMetadata.Type = api.EntryType_ENTRY_TYPE_FILE
+	fileMetadata.MimeType = mime.TypeByExtension(path.Ext(filename))
+	if fileMetadata.MimeType == "" {
+		// DetectContentType considers at most the first 512 bytes
+		fileMetadata.MimeType = http.DetectContentType(node.content)
+	}
+	return fileMetadata, nil
+}
+
+// GetFileContent retrieves the content of a specific file.
+func (r *MemFileRepository) GetFileContent(ctx context.Context, filename string) ([]byte, error) {
+	reader, err := r.GetFileReader(ctx, filename, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// GetFileReader opens a specific file for sequential reading of the byte range starting at offset.
+// A length of zero reads until the end of the file. The reader fails with the error of ctx once it is done.
+func (r *MemFileRepository) GetFileReader(ctx context.Context, filename string, offset, length int64) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	node, err := r.lookupFile(filename)
+	r.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// The content is never modified, so it is read without holding the lock
+	content := node.content
+	if offset < 0 || length < 0 || offset > int64(len(content)) {
+		return nil, ErrInvalidRange
+	}
+	content = content[offset:]
+	if length > 0 && length < int64(len(content)) {
+		content = content[:length]
+	}
+	return io.NopCloser(&contextReader{ctx: ctx, Reader: bytes.NewReader(content)}), nil
+}
+
+// GetFileWriter creates or replaces a specific file. The content is collected in memory and stored in the tree
+// when the writer is closed, missing parent directories are created then.
+// Once ctx is done, writing fails and closing discards the content.
+func (r *MemFileRepository) GetFileWriter(ctx context.Context, filename string) (FileWriter, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	parts, err := splitModifiable(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &memFileWriter{ctx: ctx, repository: r, parts: parts, filename: filename}, nil
+}
+
+// memFileWriter is a FileWriter collecting the content of a file in memory and storing it on Close.
+type memFileWriter struct {
+	ctx        context.Context
+	repository *MemFileRepository
+	parts      []string
+	filename   string
+	buffer     bytes.Buffer
+	closed     bool
+}
+
+// Write appends to the content unless the context of the writer is done or the content exceeds a size limit.
+// The written bytes are reserved against the total size limit together with the files stored so far and the content
+// of the other open writers, the reservation is returned when the writer is closed or aborted.
+func (w *memFileWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fs.ErrClosed
+	}
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r := w.repository
+	if r.config.MaxFileSize > 0 && int64(w.buffer.Len()+len(p)) > r.config.MaxFileSize {
+		return 0, ErrStorageFull
+	}
+	if err := r.reserve(int64(len(p))); err != nil {
+		return 0, err
+	}
+	return w.buffer.Write(p)
+}
+
+// Close stores the content as the file, unless the context of the writer is done.
+func (w *memFileWriter) Close() error {
+	if w.closed {
+		return fs.ErrClosed
+	}
+	w.closed = true
+
+	r := w.repository
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The content is either stored or discarded
+	r.release(int64(w.buffer.Len()))
+	if err := w.ctx.Err(); err != nil {
+		w.buffer = bytes.Buffer{}
+		return err
+	}
+	file := &memNode{content: w.buffer.Bytes(), modTime: time.Now()}
+	return r.store(w.parts, w.filename, file)
+}
+
+// Abort discards the content.
+func (w *memFileWriter) Abort() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	r := w.repository
+	r.mu.Lock()
+	r.release(int64(w.buffer.Len()))
+	r.mu.Unlock()
+	w.buffer = bytes.Buffer{}
+	return nil
+}
+
+// DeleteFile deletes a specific file or directory from the tree.
+func (r *MemFileRepository) DeleteFile(ctx context.Context, filename string, recursive bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	parts, err := splitModifiable(filename)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dir, err := r.parent(parts, filename, false)
+	if err != nil {
+		return err
+	}
+	name := parts[len(parts)-1]
+	node, ok := dir.children[name]
+	if !ok {
+		return &fs.PathError{Op: "remove", Path: filename, Err: fs.ErrNotExist}
+	}
+	if node.isDir() && len(node.children) > 0 && !recursive {
+		return ErrDirectoryNotEmpty
+	}
+
+	delete(dir.children, name)
+	dir.modTime = time.Now()
+	r.size -= node.treeSize()
+	return nil
+}
+
+// RenameFile renames or moves a file or directory within the tree, missing parent directories of the destination
+// are created. Directories are never replaced, an existing destination directory is reported like an existing file.
+func (r *MemFileRepository) RenameFile(ctx context.Context, source, destination string, overwrite bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	sourceParts, err := splitModifiable(source)
+	if err != nil {
+		return err
+	}
+	destinationParts, err := splitModifiable(destination)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sourceDir, err := r.parent(sourceParts, source, false)
+	if err != nil {
+		return err
+	}
+	sourceName := sourceParts[len(sourceParts)-1]
+	node, ok := sourceDir.children[sourceName]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: source, Err: fs.ErrNotExist}
+	}
+
+	sourcePath, destinationPath := strings.Join(sourceParts, "/"), strings.Join(destinationParts, "/")
+	if sourcePath == destinationPath {
+		return nil
+	}
+	// A directory cannot be moved into itself
+	if node.isDir() && strings.HasPrefix(destinationPath+"/", sourcePath+"/") {
+		return &fs.PathError{Op: "rename", Path: destination, Err: fs.ErrInvalid}
+	}
+
+	// Check the destination before creating its parents, so a failed rename leaves the tree untouched
+	var replaced int64
+	existing, err := r.lookup(destinationParts, destination)
+	switch {
+	case err == nil:
+		if !overwrite || existing.isDir() || node.isDir() {
+			return &fs.PathError{Op: "rename", Path: destination, Err: fs.ErrExist}
+		}
+		replaced = int64(len(existing.content))
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	destinationDir, err := r.parent(destinationParts, destination, true)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	delete(sourceDir.children, sourceName)
+	sourceDir.modTime = now
+	destinationDir.children[destinationParts[len(destinationParts)-1]] = node
+	destinationDir.modTime = now
+	r.size -= replaced
+	return nil
+}
+
+// CopyFile copies a file within the tree, the copy shares the content of the source.
+func (r *MemFileRepository) CopyFile(ctx context.Context, source, destination string, overwrite bool) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	destinationParts, err := splitModifiable(destination)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	node, err := r.lookupFile(source)
+	if err != nil {
+		return 0, err
+	}
+	if !overwrite {
+		if _, err := r.lookup(destinationParts, destination); err == nil {
+			return 0, &fs.PathError{Op: "create", Path: destination, Err: fs.ErrExist}
+		}
+	}
+
+	copied := &memNode{content: node.content, modTime: time.Now()}
+	if err := r.store(destinationParts, destination, copied); err != nil {
+		return 0, err
+	}
+	return int64(len(copied.content)), nil
+}
+
+// MakeDirectory creates a directory in the tree.
+func (r *MemFileRepository) MakeDirectory(ctx context.Context, dirPath string, parents bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	parts, err := splitMemPath(dirPath)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(parts) == 0 {
+		if parents {
+			return nil
+		}
+		return &fs.PathError{Op: "mkdir", Path: dirPath, Err: fs.ErrExist}
+	}
+
+	dir, err := r.parent(parts, dirPath, parents)
+	if err != nil {
+		return err
+	}
+	name := parts[len(parts)-1]
+	if existing, ok := dir.children[name]; ok {
+		if parents && existing.isDir() {
+			return nil
+		}
+		return &fs.PathError{Op: "mkdir", Path: dirPath, Err: fs.ErrExist}
+	}
+
+	created := newMemDirectory()
+	dir.children[name] = created
+	dir.modTime = created.modTime
+	return nil
+}
+
+// Probe reports the in-memory storage as always available.
+func (r *MemFileRepository) Probe(ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"filetransfer/api"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemFileRepository_Behavior(t *testing.T) {
+	testFileRepository(t, func(t *testing.T) FileRepository {
+		return NewMemFileRepository(MemConfig{})
+	})
+}
+
+func TestMemFileRepository_SizeLimits(t *testing.T) {
+	repo := NewMemFileRepository(MemConfig{MaxFileSize: 10, MaxSize: 20})
+
+	// A file larger than the file limit fails while it is written
+	writer, err := repo.GetFileWriter(context.Background(), "large.txt")
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("0123456789"))
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("a"))
+	assert.ErrorIs(t, err, ErrStorageFull)
+	require.NoError(t, writer.Abort())
+	assertNotExist(t, repo, "large.txt")
+
+	// Files fail while they are written once the total limit is reached
+	writeFile(t, repo, "a.txt", "0123456789")
+	writeFile(t, repo, "b.txt", "01234")
+	writer, err = repo.GetFileWriter(context.Background(), "dir/c.txt")
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("01234"))
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("5"))
+	assert.ErrorIs(t, err, ErrStorageFull)
+	require.NoError(t, writer.Abort())
+	_, err = repo.CopyFile(context.Background(), "a.txt", "dir/copy.txt", false)
+	assert.ErrorIs(t, err, ErrStorageFull)
+
+	// Failed writes and copies leave neither files nor their parent directories behind
+	assertNotExist(t, repo, "dir")
+	assert.Equal(t, int64(15), repo.size)
+	assert.Zero(t, repo.reserved)
+
+	// Replaced and deleted files free their space
+	writeFile(t, repo, "a.txt", "012")
+	_, err = repo.CopyFile(context.Background(), "a.txt", "dir/copy.txt", false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), repo.size)
+	require.NoError(t, repo.RenameFile(context.Background(), "a.txt", "b.txt", true))
+	assert.Equal(t, int64(6), repo.size)
+	require.NoError(t, repo.DeleteFile(context.Background(), "dir", true))
+	assert.Equal(t, int64(3), repo.size)
+	writeFile(t, repo, "c.txt", "0123456789")
+	assert.Equal(t, int64(13), repo.size)
+}
+
+func TestMemFileRepository_SizeLimits_OpenWriters(t *testing.T) {
+	repo := NewMemFileRepository(MemConfig{MaxSize: 10})
+	writeFile(t, repo, "a.txt", "0123")
+
+	// The content buffered by open writers counts against the total limit
+	first, err := repo.GetFileWriter(context.Background(), "b.txt")
+	require.NoError(t, err)
+	_, err = first.Write([]byte("012"))
+	require.NoError(t, err)
+	second, err := repo.GetFileWriter(context.Background(), "c.txt")
+	require.NoError(t, err)
+	_, err = second.Write([]byte("0123"))
+	assert.ErrorIs(t, err, ErrStorageFull)
+	_, err = repo.CopyFile(context.Background(), "a.txt", "copy.txt", false)
+	assert.ErrorIs(t, err, ErrStorageFull)
+	_, err = second.Write([]byte("012"))
+	require.NoError(t, err)
+
+	// Closing stores the reserved content, aborting returns it
+	require.NoError(t, first.Close())
+	require.NoError(t, second.Abort())
+	assert.Equal(t, int64(7), repo.size)
+	assert.Zero(t, repo.reserved)
+	writeFile(t, repo, "c.txt", "012")
+
+	// A canceled writer returns its reservation as well
+	ctx, cancel := context.WithCancel(context.Background())
+	writer, err := repo.GetFileWriter(ctx, "d.txt")
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteFile(context.Background(), "c.txt", false))
+	_, err = writer.Write([]byte("012"))
+	require.NoError(t, err)
+	cancel()
+	assert.ErrorIs(t, writer.Close(), context.Canceled)
+	assert.Zero(t, repo.reserved)
+}
+
+func TestMemFileRepository_Metadata(t *testing.T) {
+	repo := NewMemFileRepository(MemConfig{})
+	require.NoError(t, repo.MakeDirectory(context.Background(), "dir", false))
+	dirMetadata, err := repo.GetFileInfo(context.Background(), "dir")
+	require.NoError(t, err)
+	created := dirMetadata.(*api.FileInfoResponse).ModificationTime.AsTime()
+	assert.Equal(t, uint32(0755), dirMetadata.(*api.FileInfoResponse).Mode)
+
+	// Storing a file updates the modification time of its directory
+	writeFile(t, repo, "dir/file.txt", "content")
+	fileMetadata, err := repo.GetFileInfo(context.Background(), "dir/file.txt")
+	require.NoError(t, err)
+	modified := fileMetadata.(*api.FileInfoResponse).ModificationTime.AsTime()
+	dirMetadata, err = repo.GetFileInfo(context.Background(), "dir")
+	require.NoError(t, err)
+	assert.Equal(t, modified, dirMetadata.(*api.FileInfoResponse).ModificationTime.AsTime())
+	assert.False(t, modified.Before(created))
+}
+
+func TestMemFileRepository_ReaderSnapshot(t *testing.T) {
+	repo := NewMemFileRepository(MemConfig{})
+	writeFile(t, repo, "file.txt", "original")
+
+	// An open reader keeps reading the content it opened
+	reader, err := repo.GetFileReader(context.Background(), "file.txt", 0, 0)
+	require.NoError(t, err)
+	writeFile(t, repo, "file.txt", "replaced")
+	require.NoError(t, repo.DeleteFile(context.Background(), "file.txt", false))
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, "original", string(content))
+}
+
+func TestMemFileRepository_Concurrent(t *testing.T) {
+	repo := NewMemFileRepository(MemConfig{})
+
+	// Writers, readers and listings of different goroutines see a consistent tree
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("dir%d/file.txt", i%4)
+			content := strings.Repeat(string(rune('a'+i)), 100)
+			writeFile(t, repo, name, content)
+			assert.Len(t, readFile(t, repo, name), 100)
+			_, err := repo.GetFileList(context.Background(), "", 0)
+			assert.NoError(t, err)
+			_, err = repo.CopyFile(context.Background(), name, fmt.Sprintf("copy%d.txt", i), true)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	fileList, err := repo.GetFileList(context.Background(), "", 0)
+	require.NoError(t, err)
+	assert.Len(t, fileList, 4*2+16)
+	assert.Equal(t, int64((4+16)*100), repo.size)
+}
+
+func TestMemFileRepository_Errors(t *testing.T) {
+	repo := NewMemFileRepository(MemConfig{})
+	writeFile(t, repo, "dir/file.txt", "content")
+
+	// Files are not directories and directories are never replaced
+	assert.ErrorIs(t, repo.MakeDirectory(context.Background(), "dir/file.txt/sub", true), syscall.ENOTDIR)
+	_, err := repo.GetFileList(context.Background(), "dir/file.txt", 0)
+	assert.ErrorIs(t, err, syscall.ENOTDIR)
+	writer, err := repo.GetFileWriter(context.Background(), "dir")
+	require.NoError(t, err)
+	assert.ErrorIs(t, writer.Close(), ErrIsDirectory)
+	writer, err = repo.GetFileWriter(context.Background(), "dir/file.txt/sub/file.txt")
+	require.NoError(t, err)
+	assert.ErrorIs(t, writer.Close(), syscall.ENOTDIR)
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "dir/file.txt", "dir", true), fs.ErrExist)
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "dir", "dir/sub", false), fs.ErrInvalid)
+	_, err = repo.CopyFile(context.Background(), "dir/file.txt", "dir", true)
+	assert.ErrorIs(t, err, ErrIsDirectory)
+	assert.Equal(t, "content", readFile(t, repo, "dir/file.txt"))
+
+	// Closed writers cannot be used again
+	writer, err = repo.GetFileWriter(context.Background(), "other.txt")
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	_, err = writer.Write([]byte("content"))
+	assert.ErrorIs(t, err, fs.ErrClosed)
+	assert.ErrorIs(t, writer.Close(), fs.ErrClosed)
+}
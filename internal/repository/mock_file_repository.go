@@ -1,14 +1,17 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: filetransfer/internal/repository (interfaces: FileRepository)
+// Source: filetransfer/internal/repository (interfaces: FileRepository,FileWriter)
 //
 // Generated by this command:
 //
-//	mockgen.exe . FileRepository
+//	mockgen.exe . FileRepository,FileWriter
 //
 // Package mock_repository is a generated GoMock package.
 package repository
 
 import (
+	context "context"
+	api "filetransfer/api"
+	io "io"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -37,47 +40,214 @@ func (m *MockFileRepository) EXPECT() *MockFileRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CopyFile mocks base method.
+func (m *MockFileRepository) CopyFile(arg0 context.Context, arg1, arg2 string, arg3 bool) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFile", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFile indicates an expected call of CopyFile.
+func (mr *MockFileRepositoryMockRecorder) CopyFile(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFile", reflect.TypeOf((*MockFileRepository)(nil).CopyFile), arg0, arg1, arg2, arg3)
+}
+
+// DeleteFile mocks base method.
+func (m *MockFileRepository) DeleteFile(arg0 context.Context, arg1 string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockFileRepositoryMockRecorder) DeleteFile(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockFileRepository)(nil).DeleteFile), arg0, arg1, arg2)
+}
+
 // GetFileContent mocks base method.
-func (m *MockFileRepository) GetFileContent(arg0 string) ([]byte, error) {
+func (m *MockFileRepository) GetFileContent(arg0 context.Context, arg1 string) ([]byte, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFileContent", arg0)
+	ret := m.ctrl.Call(m, "GetFileContent", arg0, arg1)
 	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFileContent indicates an expected call of GetFileContent.
-func (mr *MockFileRepositoryMockRecorder) GetFileContent(arg0 any) *gomock.Call {
+func (mr *MockFileRepositoryMockRecorder) GetFileContent(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileContent", reflect.TypeOf((*MockFileRepository)(nil).GetFileContent), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileContent", reflect.TypeOf((*MockFileRepository)(nil).GetFileContent), arg0, arg1)
 }
 
 // GetFileInfo mocks base method.
-func (m *MockFileRepository) GetFileInfo(arg0 string) (any, error) {
+func (m *MockFileRepository) GetFileInfo(arg0 context.Context, arg1 string) (any, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFileInfo", arg0)
+	ret := m.ctrl.Call(m, "GetFileInfo", arg0, arg1)
 	ret0, _ := ret[0].(any)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFileInfo indicates an expected call of GetFileInfo.
-func (mr *MockFileRepositoryMockRecorder) GetFileInfo(arg0 any) *gomock.Call {
+func (mr *MockFileRepositoryMockRecorder) GetFileInfo(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileInfo", reflect.TypeOf((*MockFileRepository)(nil).GetFileInfo), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileInfo", reflect.TypeOf((*MockFileRepository)(nil).GetFileInfo), arg0, arg1)
 }
 
 // GetFileList mocks base method.
-func (m *MockFileRepository) GetFileList() ([]string, error) {
+func (m *MockFileRepository) GetFileList(arg0 context.Context, arg1 string, arg2 int) ([]*api.FileEntry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFileList")
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "GetFileList", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*api.FileEntry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFileList indicates an expected call of GetFileList.
-func (mr *MockFileRepositoryMockRecorder) GetFileList() *gomock.Call {
+func (mr *MockFileRepositoryMockRecorder) GetFileList(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileList", reflect.TypeOf((*MockFileRepository)(nil).GetFileList), arg0, arg1, arg2)
+}
+
+// GetFileReader mocks base method.
+func (m *MockFileRepository) GetFileReader(arg0 context.Context, arg1 string, arg2, arg3 int64) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileReader", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileReader indicates an expected call of GetFileReader.
+func (mr *MockFileRepositoryMockRecorder) GetFileReader(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileReader", reflect.TypeOf((*MockFileRepository)(nil).GetFileReader), arg0, arg1, arg2, arg3)
+}
+
+// GetFileWriter mocks base method.
+func (m *MockFileRepository) GetFileWriter(arg0 context.Context, arg1 string) (FileWriter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileWriter", arg0, arg1)
+	ret0, _ := ret[0].(FileWriter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileWriter indicates an expected call of GetFileWriter.
+func (mr *MockFileRepositoryMockRecorder) GetFileWriter(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileWriter", reflect.TypeOf((*MockFileRepository)(nil).GetFileWriter), arg0, arg1)
+}
+
+// MakeDirectory mocks base method.
+func (m *MockFileRepository) MakeDirectory(arg0 context.Context, arg1 string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MakeDirectory", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MakeDirectory indicates an expected call of MakeDirectory.
+func (mr *MockFileRepositoryMockRecorder) MakeDirectory(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeDirectory", reflect.TypeOf((*MockFileRepository)(nil).MakeDirectory), arg0, arg1, arg2)
+}
+
+// Probe mocks base method.
+func (m *MockFileRepository) Probe(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Probe", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Probe indicates an expected call of Probe.
+func (mr *MockFileRepositoryMockRecorder) Probe(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Probe", reflect.TypeOf((*MockFileRepository)(nil).Probe), arg0)
+}
+
+// RenameFile mocks base method.
+func (m *MockFileRepository) RenameFile(arg0 context.Context, arg1, arg2 string, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameFile", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameFile indicates an expected call of RenameFile.
+func (mr *MockFileRepositoryMockRecorder) RenameFile(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameFile", reflect.TypeOf((*MockFileRepository)(nil).RenameFile), arg0, arg1, arg2, arg3)
+}
+
+// MockFileWriter is a mock of FileWriter interface.
+type MockFileWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileWriterMockRecorder
+}
+
+// MockFileWriterMockRecorder is the mock recorder for MockFileWriter.
+type MockFileWriterMockRecorder struct {
+	mock *MockFileWriter
+}
+
+// NewMockFileWriter creates a new mock instance.
+func NewMockFileWriter(ctrl *gomock.Controller) *MockFileWriter {
+	mock := &MockFileWriter{ctrl: ctrl}
+	mock.recorder = &MockFileWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileWriter) EXPECT() *MockFileWriterMockRecorder {
+	return m.recorder
+}
+
+// Abort mocks base method.
+func (m *MockFileWriter) Abort() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Abort")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Abort indicates an expected call of Abort.
+func (mr *MockFileWriterMockRecorder) Abort() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Abort", reflect.TypeOf((*MockFileWriter)(nil).Abort))
+}
+
+// Close mocks base method.
+func (m *MockFileWriter) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockFileWriterMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockFileWriter)(nil).Close))
+}
+
+// Write mocks base method.
+func (m *MockFileWriter) Write(arg0 []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockFileWriterMockRecorder) Write(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileList", reflect.TypeOf((*MockFileRepository)(nil).GetFileList))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockFileWriter)(nil).Write), arg0)
 }
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// ErrPathOutsideRoot is returned when a requested path resolves to a location outside of the storage root.
+var ErrPathOutsideRoot = errors.New("path is outside of the storage root")
+
+// CleanPath cleans a client-supplied path and makes sure it stays within the root it is relative to.
+// Absolute paths and paths escaping the root with ".." are rejected with ErrPathOutsideRoot.
+// An empty path refers to the root itself.
+func CleanPath(name string) (string, error) {
+	if name == "" {
+		return ".", nil
+	}
+	if !filepath.IsLocal(name) {
+		return "", ErrPathOutsideRoot
+	}
+	return filepath.Clean(name), nil
+}
+
+// PathResolver resolves client-supplied paths against a storage root on the local file system
+// and confines them to it, including the targets of symbolic links.
+type PathResolver struct {
+	root string
+}
+
+// NewPathResolver creates a new instance of PathResolver confined to the specified root directory.
+// A relative root is made absolute, so the resolved paths are absolute as well.
+func NewPathResolver(root string) *PathResolver {
+	if absRoot, err := filepath.Abs(root); err == nil {
+		root = absRoot
+	}
+	return &PathResolver{
+		root: root,
+	}
+}
+
+// Resolve returns the location of the given path inside the root with all symbolic links resolved.
+// Paths that do not exist yet are resolved up to their deepest existing parent directory.
+// ErrPathOutsideRoot is returned if the path or any symbolic link on it points outside of the root.
+func (r *PathResolver) Resolve(name string) (string, error) {
+	cleanName, err := CleanPath(name)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := filepath.EvalSymlinks(r.root)
+	if err != nil {
+		return "", err
+	}
+
+	resolved, err := evalExistingSymlinks(filepath.Join(root, cleanName))
+	if err != nil {
+		return "", err
+	}
+
+	// Make sure the resolved path is still located inside the resolved root
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", ErrPathOutsideRoot
+	}
+
+	return resolved, nil
+}
+
+// ResolveRelative resolves the given path like Resolve, but returns the resolved location relative to the root,
+// using forward slashes. The root itself is ".".
+func (r *PathResolver) ResolveRelative(name string) (string, error) {
+	resolved, err := r.Resolve(name)
+	if err != nil {
+		return "", err
+	}
+	root, err := filepath.EvalSymlinks(r.root)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// roots returns the root as configured and with its symbolic links resolved, the paths returned by Resolve
+// start with the latter.
+func (r *PathResolver) roots() []string {
+	roots := []string{r.root}
+	if root, err := filepath.EvalSymlinks(r.root); err == nil && root != r.root {
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+// evalExistingSymlinks resolves symbolic links on the longest existing prefix of the path
+// and appends the remaining, not yet existing, path elements to it.
+func evalExistingSymlinks(path string) (string, error) {
+	rest := ""
+	for {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+
+		// A dangling symbolic link exists itself, so its target cannot be confined to the root
+		if _, lstatErr := os.Lstat(path); lstatErr == nil {
+			return "", err
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", err
+		}
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
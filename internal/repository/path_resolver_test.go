@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathResolver_Resolve(t *testing.T) {
+	root := t.TempDir()
+	err := os.MkdirAll(filepath.Join(root, "dir"), 0755)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(root, "dir", "file.txt"), []byte("content"), 0644)
+	assert.NoError(t, err)
+
+	resolver := NewPathResolver(root)
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	assert.NoError(t, err)
+
+	resolved, err := resolver.Resolve("dir/file.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(resolvedRoot, "dir", "file.txt"), resolved)
+
+	resolved, err = resolver.Resolve("dir/../dir/./file.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(resolvedRoot, "dir", "file.txt"), resolved)
+
+	resolved, err = resolver.Resolve("")
+	assert.NoError(t, err)
+	assert.Equal(t, resolvedRoot, resolved)
+
+	// Paths which do not exist yet are resolved for writing
+	resolved, err = resolver.Resolve("dir/new/file.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(resolvedRoot, "dir", "new", "file.txt"), resolved)
+}
+
+func TestPathResolver_Resolve_Traversal(t *testing.T) {
+	root := t.TempDir()
+	resolver := NewPathResolver(filepath.Join(root, "storage"))
+	err := os.MkdirAll(filepath.Join(root, "storage"), 0755)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(root, "secret.txt"), []byte("secret"), 0644)
+	assert.NoError(t, err)
+
+	for _, name := range []string{
+		"..",
+		"../secret.txt",
+		"../../etc/shadow",
+		"dir/../../secret.txt",
+		"./../storage/../secret.txt",
+	} {
+		_, err := resolver.Resolve(name)
+		assert.ErrorIs(t, err, ErrPathOutsideRoot, name)
+	}
+}
+
+func TestPathResolver_Resolve_AbsolutePath(t *testing.T) {
+	root := t.TempDir()
+	resolver := NewPathResolver(root)
+	err := os.WriteFile(filepath.Join(root, "file.txt"), []byte("content"), 0644)
+	assert.NoError(t, err)
+
+	for _, name := range []string{
+		"/etc/shadow",
+		filepath.Join(root, "file.txt"),
+	} {
+		_, err := resolver.Resolve(name)
+		assert.ErrorIs(t, err, ErrPathOutsideRoot, name)
+	}
+}
+
+func TestPathResolver_Resolve_Symlink(t *testing.T) {
+	root := t.TempDir()
+	storage := filepath.Join(root, "storage")
+	outside := filepath.Join(root, "outside")
+	assert.NoError(t, os.MkdirAll(filepath.Join(storage, "dir"), 0755))
+	assert.NoError(t, os.MkdirAll(outside, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(storage, "dir", "file.txt"), []byte("content"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0644))
+
+	// Symbolic links pointing outside of the root
+	assert.NoError(t, os.Symlink(filepath.Join(outside, "secret.txt"), filepath.Join(storage, "file-link")))
+	assert.NoError(t, os.Symlink(outside, filepath.Join(storage, "dir-link")))
+	assert.NoError(t, os.Symlink("../outside/secret.txt", filepath.Join(storage, "relative-link")))
+	assert.NoError(t, os.Symlink(filepath.Join(outside, "missing.txt"), filepath.Join(storage, "dangling-link")))
+
+	// Symbolic link pointing inside of the root
+	assert.NoError(t, os.Symlink(filepath.Join(storage, "dir", "file.txt"), filepath.Join(storage, "inside-link")))
+
+	resolver := NewPathResolver(storage)
+
+	for _, name := range []string{
+		"file-link",
+		"dir-link/secret.txt",
+		"dir-link/new.txt",
+		"relative-link",
+	} {
+		_, err := resolver.Resolve(name)
+		assert.ErrorIs(t, err, ErrPathOutsideRoot, name)
+	}
+
+	_, err := resolver.Resolve("dangling-link")
+	assert.Error(t, err)
+
+	resolved, err := resolver.Resolve("inside-link")
+	assert.NoError(t, err)
+	resolvedStorage, err := filepath.EvalSymlinks(storage)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(resolvedStorage, "dir", "file.txt"), resolved)
+}
@@ -0,0 +1,17 @@
+//go:build !unix
+
+package repository
+
+import (
+	"os"
+	"syscall"
+)
+
+// renameNoReplace renames oldPath to newPath, failing with an error matching fs.ErrExist if newPath exists.
+// Unlike os.Rename, the rename system call does not replace an existing newPath on these platforms.
+func renameNoReplace(oldPath, newPath string) error {
+	if err := syscall.Rename(oldPath, newPath); err != nil {
+		return &os.LinkError{Op: "rename", Old: oldPath, New: newPath, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+//go:build unix
+
+package repository
+
+import (
+	"os"
+	"syscall"
+)
+
+// renameNoReplace renames oldPath to newPath, failing with an error matching fs.ErrExist if newPath exists.
+// Instead of checking newPath before renaming, which races with other writers, newPath is claimed atomically:
+// a file is hard linked to newPath and then removed from oldPath, a directory replaces the empty directory
+// created at newPath.
+func renameNoReplace(oldPath, newPath string) error {
+	fileInfo, err := os.Lstat(oldPath)
+	if err != nil {
+		return err
+	}
+
+	if !fileInfo.IsDir() {
+		if err := os.Link(oldPath, newPath); err != nil {
+			return err
+		}
+		return os.Remove(oldPath)
+	}
+
+	// os.Rename refuses to replace a directory, the rename system call replaces an empty one
+	if err := os.Mkdir(newPath, 0755); err != nil {
+		return err
+	}
+	if err := syscall.Rename(oldPath, newPath); err != nil {
+		os.Remove(newPath)
+		return &os.LinkError{Op: "rename", Old: oldPath, New: newPath, Err: err}
+	}
+	return nil
+}
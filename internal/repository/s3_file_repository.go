@@ -0,0 +1,847 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"filetransfer/api"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultS3PartSize is the size of the parts of multipart uploads if no part size is configured.
+	// S3 allows at most 10000 parts, which limits uploaded files to about 78 GiB.
+	DefaultS3PartSize = 8 << 20
+	// defaultS3Region is used to sign requests if neither the configuration nor the environment sets a region.
+	defaultS3Region = "us-east-1"
+	// maxDeleteObjects is the maximum number of objects deleted by a single DeleteObjects request.
+	maxDeleteObjects = 1000
+	// s3DirectoryMarker is the name of the empty object keeping an empty directory in existence. It is not named
+	// after the directory with a trailing slash, since not every S3-compatible service supports such keys.
+	s3DirectoryMarker = ".filetransfer-dir"
+)
+
+// S3Config holds the settings of an S3FileRepository.
+type S3Config struct {
+	// Endpoint is the URL of an S3-compatible service, AWS S3 is used if empty.
+	Endpoint string
+	// Region is the region of the bucket, it is taken from the AWS environment if empty.
+	Region string
+	// Bucket is the bucket storing the files.
+	Bucket string
+	// Prefix is the key prefix of the files in the bucket, the whole bucket is served if empty.
+	Prefix string
+	// AccessKeyID and SecretAccessKey are the static credentials of the service.
+	// The default AWS credential chain is used if they are empty.
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses the bucket in the path of the URL instead of the host name,
+	// which most S3-compatible services require.
+	PathStyle bool
+	// PartSize is the size of the parts of multipart uploads, DefaultS3PartSize if zero.
+	// Files smaller than a part are uploaded with a single request.
+	PartSize int64
+}
+
+// S3FileRepository is an implementation of the FileRepository interface serving the objects of an S3 bucket.
+// Every file is an object whose key is its path below the configured prefix. Directories are implied by the keys
+// of the objects below them, MakeDirectory creates an empty marker object inside the directory so that empty
+// directories exist as well. Marker objects are not listed, nor are keys ending in a slash, which other clients
+// use as directory markers.
+//
+// Listing maps to prefix listings delimited by slashes, file information to HEAD requests and reading to ranged GET
+// requests. Files are uploaded as multipart uploads, which are only completed when the writer is closed.
+// S3 has no renames, so RenameFile copies every object and deletes the originals, which is not atomic and limited
+// to objects of at most 5 GiB like CopyFile. Files are reported with the permissions 0644 and no owner.
+type S3FileRepository struct {
+	client   *s3.Client
+	bucket   string
+	prefix   string
+	partSize int64
+}
+
+// NewS3FileRepository creates a new instance of S3FileRepository serving the bucket configured in cfg.
+// The bucket is not accessed before the first call, Probe checks that it is available.
+func NewS3FileRepository(ctx context.Context, cfg S3Config) (*S3FileRepository, error) {
+	var options []func(*awsconfig.LoadOptions) error
+	if cfg.Region != "" {
+		options = append(options, awsconfig.WithRegion(cfg.Region))
+	}
+	if cfg.AccessKeyID != "" {
+		options = append(options, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, "")))
+	}
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS configuration: %w", err)
+	}
+	if awsConfig.Region == "" {
+		awsConfig.Region = defaultS3Region
+	}
+
+	client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+		o.UsePathStyle = cfg.PathStyle
+	})
+
+	r := &S3FileRepository{
+		client:   client,
+		bucket:   cfg.Bucket,
+		partSize: cfg.PartSize,
+	}
+	if prefix := strings.Trim(cfg.Prefix, "/"); prefix != "" {
+		r.prefix = prefix + "/"
+	}
+	if r.partSize <= 0 {
+		r.partSize = DefaultS3PartSize
+	}
+	return r, nil
+}
+
+// resolveS3Path cleans a client-supplied path and returns it slash-separated, the root being empty.
+func resolveS3Path(name string) (string, error) {
+	cleanName, err := CleanPath(name)
+	if err != nil {
+		return "", err
+	}
+	if cleanName == "." {
+		return "", nil
+	}
+	return filepath.ToSlash(cleanName), nil
+}
+
+// objectKey returns the key of the object of the file at the cleaned path rel.
+func (r *S3FileRepository) objectKey(rel string) string {
+	return r.prefix + rel
+}
+
+// dirPrefix returns the key prefix of the objects below the directory at the cleaned path rel.
+func (r *S3FileRepository) dirPrefix(rel string) string {
+	if rel == "" {
+		return r.prefix
+	}
+	return r.prefix + rel + "/"
+}
+
+// isNotFound reports whether err is the error of S3 for a missing object.
+func isNotFound(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NotFound", "NoSuchKey":
+			return true
+		}
+	}
+	return false
+}
+
+// markerKey returns the key of the marker object of the directory at the cleaned path rel.
+func (r *S3FileRepository) markerKey(rel string) string {
+	return r.dirPrefix(rel) + s3DirectoryMarker
+}
+
+// stat returns the metadata of the object of the file at the cleaned path rel, or nil if rel is a directory.
+// A directory exists if any object has its prefix, an error matching fs.ErrNotExist is returned if neither exists.
+func (r *S3FileRepository) stat(ctx context.Context, rel, name string) (*s3.HeadObjectOutput, error) {
+	if rel == "" {
+		return nil, nil
+	}
+
+	head, err := r.client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &r.bucket, Key: aws.String(r.objectKey(rel))})
+	if err == nil {
+		return head, nil
+	}
+	if !isNotFound(err) {
+		return nil, err
+	}
+
+	list, err := r.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{Bucket: &r.bucket, Prefix: aws.String(r.dirPrefix(rel)), MaxKeys: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Contents) == 0 {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return nil, nil
+}
+
+// checkParents returns an error matching syscall.ENOTDIR if a parent directory of the cleaned path rel is a file,
+// since keys below the key of a file would make it a directory as well.
+func (r *S3FileRepository) checkParents(ctx context.Context, rel, op, name string) error {
+	for parent := path.Dir(rel); parent != "."; parent = path.Dir(parent) {
+		_, err := r.client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &r.bucket, Key: aws.String(r.objectKey(parent))})
+		if err == nil {
+			return &fs.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+		}
+		if !isNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFileList retrieves the entries below a directory of the bucket with one prefix listing per directory.
+func (r *S3FileRepository) GetFileList(ctx context.Context, dir string, maxDepth int) ([]*api.FileEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rel, err := resolveS3Path(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fileList := make([]*api.FileEntry, 0)
+	found, err := r.listDir(ctx, rel, 1, maxDepth, &fileList)
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty listing is either an empty root, a file or a missing directory
+	if !found && rel != "" {
+		head, err := r.stat(ctx, rel, dir)
+		if err != nil {
+			return nil, err
+		}
+		if head != nil {
+			return nil, &fs.PathError{Op: "list", Path: dir, Err: syscall.ENOTDIR}
+		}
+	}
+	return fileList, nil
+}
+
+// listDir appends the entries of the directory at rel to fileList, sorted by name, and descends into
+// subdirectories while depth does not exceed maxDepth. It reports whether any object, including the marker
+// of the directory, has the prefix of the directory.
+func (r *S3FileRepository) listDir(ctx context.Context, rel string, depth, maxDepth int, fileList *[]*api.FileEntry) (bool, error) {
+	prefix := r.dirPrefix(rel)
+	var entries []*api.FileEntry
+	found := false
+
+	paginator := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{Bucket: &r.bucket, Prefix: &prefix, Delimiter: aws.String("/")})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		for _, object := range page.Contents {
+			found = true
+			// Skip directory markers and keys that are no valid paths
+			name := strings.TrimPrefix(aws.ToString(object.Key), prefix)
+			if !fs.ValidPath(name) || name == "." || name == s3DirectoryMarker {
+				continue
+			}
+			entries = append(entries, &api.FileEntry{
+				Name: name,
+				Path: path.Join(rel, name),
+				Type: api.EntryType_ENTRY_TYPE_FILE,
+				Size: uint64(object.Size),
+			})
+		}
+		for _, commonPrefix := range page.CommonPrefixes {
+			found = true
+			name := strings.TrimSuffix(strings.TrimPrefix(aws.ToString(commonPrefix.Prefix), prefix), "/")
+			if !fs.ValidPath(name) || name == "." {
+				continue
+			}
+			entries = append(entries, &api.FileEntry{
+				Name: name,
+				Path: path.Join(rel, name),
+				Type: api.EntryType_ENTRY_TYPE_DIRECTORY,
+			})
+		}
+	}
+
+	// Files and directories are listed separately, both sorted by key
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	for _, entry := range entries {
+		*fileList = append(*fileList, entry)
+		if entry.Type == api.EntryType_ENTRY_TYPE_DIRECTORY && (maxDepth <= 0 || depth < maxDepth) {
+			if _, err := r.listDir(ctx, entry.Path, depth+1, maxDepth, fileList); err != nil {
+				return false, err
+			}
+		}
+	}
+	return found, nil
+}
+
+// GetFileInfo retrieves metadata information about a specific file from the HEAD request of its object.
+// The MIME type is derived from the extension or, for unknown extensions, from the content type of the object.
+func (r *S3FileRepository) GetFileInfo(ctx context.Context, filename string) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rel, err := resolveS3Path(filename)
+	if err != nil {
+		return nil, err
+	}
+	head, err := r.stat(ctx, rel, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if head == nil {
+		fileMetadata := &api.FileInfoResponse{
+			Filename: filename,
+			Mode:     0755,
+			Type:     api.EntryType_ENTRY_TYPE_DIRECTORY,
+		}
+		// Only directories created by MakeDirectory have a modification time
+		marker, err := r.client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &r.bucket, Key: aws.String(r.markerKey(rel))})
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+		if err == nil {
+			fileMetadata.ModificationTime = timestamppb.New(aws.ToTime(marker.LastModified))
+		}
+		return fileMetadata, nil
+	}
+
+	fileMetadata := &api.FileInfoResponse{
+		Filename:         filename,
+		Size:             uint64(head.ContentLength),
+		ModificationTime: timestamppb.New(aws.ToTime(head.LastModified)),
+		Mode:             0644,
+		Type:             api.EntryType_ENTRY_TYPE_FILE,
+		MimeType:         mime.TypeByExtension(path.Ext(rel)),
+	}
+	if fileMetadata.MimeType == "" {
+		fileMetadata.MimeType = aws.ToString(head.ContentType)
+	}
+	if fileMetadata.MimeType == "" {
+		fileMetadata.MimeType = "application/octet-stream"
+	}
+	return fileMetadata, nil
+}
+
+// GetFileContent retrieves the content of a specific file from the bucket.
+// Reading stops with the error of ctx once it is done.
+func (r *S3FileRepository) GetFileContent(ctx context.Context, filename string) ([]byte, error) {
+	reader, err := r.GetFileReader(ctx, filename, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// GetFileReader opens a specific file for sequential reading of the byte range starting at offset with a ranged
+// GET request. A length of zero reads until the end of the file. The reader fails with the error of ctx once it is
+// done. The GET request only succeeds if the object was not replaced since the range was checked.
+func (r *S3FileRepository) GetFileReader(ctx context.Context, filename string, offset, length int64) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rel, err := resolveS3Path(filename)
+	if err != nil {
+		return nil, err
+	}
+	head, err := r.stat(ctx, rel, filename)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, &fs.PathError{Op: "open", Path: filename, Err: ErrIsDirectory}
+	}
+
+	size := head.ContentLength
+	if offset < 0 || length < 0 || offset > size {
+		return nil, ErrInvalidRange
+	}
+	// S3 rejects ranges starting at the end of an object
+	if offset == size {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	input := &s3.GetObjectInput{Bucket: &r.bucket, Key: aws.String(r.objectKey(rel)), IfMatch: head.ETag}
+	if offset > 0 || length > 0 {
+		end := size - 1
+		if length > 0 && offset+length < size {
+			end = offset + length - 1
+		}
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-%d", offset, end))
+	}
+	object, err := r.client.GetObject(ctx, input)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, &fs.PathError{Op: "open", Path: filename, Err: fs.ErrNotExist}
+		}
+		return nil, err
+	}
+	return &readCloser{Reader: &contextReader{ctx: ctx, Reader: object.Body}, Closer: object.Body}, nil
+}
+
+// GetFileWriter creates or replaces a specific file. The content is buffered in parts of the configured part
+// size, every full part is uploaded as a part of a multipart upload, which is completed when the writer is closed.
+// Content smaller than a part is uploaded with a single request on Close. Once ctx is done, writing fails and
+// closing aborts the upload. ErrIsDirectory is returned if the file is a directory, an error matching
+// syscall.ENOTDIR if one of its parent directories is a file.
+func (r *S3FileRepository) GetFileWriter(ctx context.Context, filename string) (FileWriter, error) {
+	rel, err := resolveS3Path(filename)
+	if err != nil {
+		return nil, err
+	}
+	if rel == "" {
+		return nil, &fs.PathError{Op: "create", Path: filename, Err: ErrIsDirectory}
+	}
+
+	head, err := r.stat(ctx, rel, filename)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil && head == nil {
+		return nil, &fs.PathError{Op: "create", Path: filename, Err: ErrIsDirectory}
+	}
+	if err := r.checkParents(ctx, rel, "create", filename); err != nil {
+		return nil, err
+	}
+
+	return &s3FileWriter{
+		ctx:        ctx,
+		repository: r,
+		key:        r.objectKey(rel),
+		buffer:     make([]byte, 0, r.partSize),
+	}, nil
+}
+
+// s3FileWriter is a FileWriter uploading to S3, as a multipart upload once the content exceeds a part.
+type s3FileWriter struct {
+	ctx        context.Context
+	repository *S3FileRepository
+	key        string
+	// buffer holds the content of the part being written.
+	buffer []byte
+	// contentType is detected from the first bytes of the content.
+	contentType string
+	// uploadID identifies the multipart upload, it is nil until the first part is uploaded.
+	uploadID *string
+	parts    []types.CompletedPart
+	// err is the error that made the writer fail, the multipart upload is aborted then.
+	err error
+}
+
+// Write buffers p and uploads every part that is full.
+func (w *s3FileWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for len(p) > 0 {
+		n := copy(w.buffer[len(w.buffer):cap(w.buffer)], p)
+		w.buffer = w.buffer[:len(w.buffer)+n]
+		written += n
+		p = p[n:]
+
+		if len(w.buffer) == cap(w.buffer) {
+			if err := w.uploadPart(); err != nil {
+				w.fail(err)
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// detectContentType sets the content type from the extension of the key or the first bytes of the content.
+func (w *s3FileWriter) detectContentType() {
+	if w.contentType != "" {
+		return
+	}
+	w.contentType = mime.TypeByExtension(path.Ext(w.key))
+	if w.contentType == "" {
+		// DetectContentType considers at most the first 512 bytes
+		w.contentType = http.DetectContentType(w.buffer)
+	}
+}
+
+// uploadPart uploads the buffered content as the next part, starting the multipart upload with the first part.
+func (w *s3FileWriter) uploadPart() error {
+	r := w.repository
+	if w.uploadID == nil {
+		w.detectContentType()
+		upload, err := r.client.CreateMultipartUpload(w.ctx, &s3.CreateMultipartUploadInput{
+			Bucket:      &r.bucket,
+			Key:         &w.key,
+			ContentType: &w.contentType,
+		})
+		if err != nil {
+			return err
+		}
+		w.uploadID = upload.UploadId
+	}
+
+	partNumber := int32(len(w.parts) + 1)
+	part, err := r.client.UploadPart(w.ctx, &s3.UploadPartInput{
+		Bucket:        &r.bucket,
+		Key:           &w.key,
+		UploadId:      w.uploadID,
+		PartNumber:    partNumber,
+		Body:          bytes.NewReader(w.buffer),
+		ContentLength: int64(len(w.buffer)),
+	})
+	if err != nil {
+		return err
+	}
+	w.parts = append(w.parts, types.CompletedPart{ETag: part.ETag, PartNumber: partNumber})
+	w.buffer = w.buffer[:0]
+	return nil
+}
+
+// Close uploads the remaining content and completes the upload, which makes the file visible.
+func (w *s3FileWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.ctx.Err(); err != nil {
+		w.fail(err)
+		return err
+	}
+
+	r := w.repository
+	if w.uploadID == nil {
+		// The content fits into a single part
+		w.detectContentType()
+		_, err := r.client.PutObject(w.ctx, &s3.PutObjectInput{
+			Bucket:        &r.bucket,
+			Key:           &w.key,
+			Body:          bytes.NewReader(w.buffer),
+			ContentLength: int64(len(w.buffer)),
+			ContentType:   &w.contentType,
+		})
+		if err != nil {
+			w.fail(err)
+		}
+		return err
+	}
+
+	if len(w.buffer) > 0 {
+		if err := w.uploadPart(); err != nil {
+			w.fail(err)
+			return err
+		}
+	}
+	_, err := r.client.CompleteMultipartUpload(w.ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          &r.bucket,
+		Key:             &w.key,
+		UploadId:        w.uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: w.parts},
+	})
+	if err != nil {
+		w.fail(err)
+		return err
+	}
+	w.err = fs.ErrClosed
+	return nil
+}
+
+// Abort discards the buffered content and aborts the multipart upload, leaving any existing file untouched.
+func (w *s3FileWriter) Abort() error {
+	if w.err == nil {
+		w.fail(fs.ErrClosed)
+	}
+	return nil
+}
+
+// fail makes every further call of the writer fail with err and aborts the multipart upload.
+// The upload is aborted even if the context of the writer is done, so no parts are left behind.
+func (w *s3FileWriter) fail(err error) {
+	w.err = err
+	w.buffer = nil
+	if w.uploadID == nil {
+		return
+	}
+
+	r := w.repository
+	// Parts that cannot be removed now are removed by the lifecycle rules of the bucket, if it has any
+	_, _ = r.client.AbortMultipartUpload(context.Background(), &s3.AbortMultipartUploadInput{
+		Bucket:   &r.bucket,
+		Key:      &w.key,
+		UploadId: w.uploadID,
+	})
+	w.uploadID = nil
+}
+
+// listKeys returns the keys of all objects starting with prefix.
+func (r *S3FileRepository) listKeys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{Bucket: &r.bucket, Prefix: &prefix})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+	}
+	return keys, nil
+}
+
+// deleteKeys deletes the objects with the given keys in batches.
+func (r *S3FileRepository) deleteKeys(ctx context.Context, keys []string) error {
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > maxDeleteObjects {
+			batch = batch[:maxDeleteObjects]
+		}
+		keys = keys[len(batch):]
+
+		objects := make([]types.ObjectIdentifier, len(batch))
+		for i := range batch {
+			objects[i] = types.ObjectIdentifier{Key: aws.String(batch[i])}
+		}
+		output, err := r.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: &r.bucket,
+			Delete: &types.Delete{Objects: objects, Quiet: true},
+		})
+		if err != nil {
+			return err
+		}
+		if len(output.Errors) > 0 {
+			failed := output.Errors[0]
+			return fmt.Errorf("deleting %s: %s", aws.ToString(failed.Key), aws.ToString(failed.Message))
+		}
+	}
+	return nil
+}
+
+// DeleteFile deletes the object of a specific file or the objects of a directory from the bucket.
+func (r *S3FileRepository) DeleteFile(ctx context.Context, filename string, recursive bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rel, err := resolveS3Path(filename)
+	if err != nil {
+		return err
+	}
+	if rel == "" {
+		return ErrStorageRoot
+	}
+	head, err := r.stat(ctx, rel, filename)
+	if err != nil {
+		return err
+	}
+
+	if head != nil {
+		_, err = r.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &r.bucket, Key: aws.String(r.objectKey(rel))})
+		return err
+	}
+
+	keys, err := r.listKeys(ctx, r.dirPrefix(rel))
+	if err != nil {
+		return err
+	}
+	// The objects of the directory may have been deleted since it was found
+	if len(keys) == 0 {
+		return &fs.PathError{Op: "remove", Path: filename, Err: fs.ErrNotExist}
+	}
+	if !recursive && (len(keys) > 1 || keys[0] != r.markerKey(rel)) {
+		return &fs.PathError{Op: "remove", Path: filename, Err: ErrDirectoryNotEmpty}
+	}
+	return r.deleteKeys(ctx, keys)
+}
+
+// checkS3Destination returns an error matching fs.ErrExist if the destination exists and must not be replaced.
+// Directories are never replaced, since replacing them would merge their objects with the new ones.
+// An error matching syscall.ENOTDIR is returned if a parent directory of the destination is a file.
+func (r *S3FileRepository) checkS3Destination(ctx context.Context, rel, destination string, overwrite bool) error {
+	head, err := r.stat(ctx, rel, destination)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r.checkParents(ctx, rel, "create", destination)
+	}
+	if err != nil {
+		return err
+	}
+	if overwrite && head != nil {
+		return nil
+	}
+	return &fs.PathError{Op: "create", Path: destination, Err: fs.ErrExist}
+}
+
+// copyObject copies the object with the key source to the key destination within the bucket.
+func (r *S3FileRepository) copyObject(ctx context.Context, source, destination string) error {
+	// The key in the copy source must be URL-encoded, a plus sign would otherwise be read as a space
+	copySource := r.bucket + "/" + strings.ReplaceAll(url.PathEscape(source), "+", "%2B")
+	_, err := r.client.CopyObject(ctx, &s3.CopyObjectInput{Bucket: &r.bucket, Key: &destination, CopySource: &copySource})
+	return err
+}
+
+// RenameFile moves a file or directory within the bucket by copying its objects and deleting the originals.
+// An interrupted rename of a directory leaves the objects copied so far in both places.
+func (r *S3FileRepository) RenameFile(ctx context.Context, source, destination string, overwrite bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	sourceRel, err := resolveS3Path(source)
+	if err != nil {
+		return err
+	}
+	destinationRel, err := resolveS3Path(destination)
+	if err != nil {
+		return err
+	}
+	if sourceRel == "" || destinationRel == "" {
+		return ErrStorageRoot
+	}
+
+	head, err := r.stat(ctx, sourceRel, source)
+	if err != nil {
+		return err
+	}
+	if sourceRel == destinationRel {
+		return nil
+	}
+	if err := r.checkS3Destination(ctx, destinationRel, destination, overwrite); err != nil {
+		return err
+	}
+
+	if head != nil {
+		if err := r.copyObject(ctx, r.objectKey(sourceRel), r.objectKey(destinationRel)); err != nil {
+			return err
+		}
+		_, err = r.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &r.bucket, Key: aws.String(r.objectKey(sourceRel))})
+		return err
+	}
+
+	// A directory cannot be moved into itself
+	if strings.HasPrefix(destinationRel+"/", sourceRel+"/") {
+		return &fs.PathError{Op: "rename", Path: destination, Err: fs.ErrInvalid}
+	}
+	sourcePrefix, destinationPrefix := r.dirPrefix(sourceRel), r.dirPrefix(destinationRel)
+	keys, err := r.listKeys(ctx, sourcePrefix)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := r.copyObject(ctx, key, destinationPrefix+strings.TrimPrefix(key, sourcePrefix)); err != nil {
+			return err
+		}
+	}
+	return r.deleteKeys(ctx, keys)
+}
+
+// CopyFile copies a file within the bucket without downloading it.
+func (r *S3FileRepository) CopyFile(ctx context.Context, source, destination string, overwrite bool) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	sourceRel, err := resolveS3Path(source)
+	if err != nil {
+		return 0, err
+	}
+	destinationRel, err := resolveS3Path(destination)
+	if err != nil {
+		return 0, err
+	}
+	if destinationRel == "" {
+		return 0, ErrStorageRoot
+	}
+
+	head, err := r.stat(ctx, sourceRel, source)
+	if err != nil {
+		return 0, err
+	}
+	if head == nil {
+		return 0, &fs.PathError{Op: "copy", Path: source, Err: ErrIsDirectory}
+	}
+	if err := r.checkS3Destination(ctx, destinationRel, destination, overwrite); err != nil {
+		return 0, err
+	}
+
+	if err := r.copyObject(ctx, r.objectKey(sourceRel), r.objectKey(destinationRel)); err != nil {
+		return 0, err
+	}
+	return head.ContentLength, nil
+}
+
+// MakeDirectory creates a directory by storing an empty marker object in it.
+// The parent directories are implied by the marker, so parents only relaxes the checks. An error matching
+// syscall.ENOTDIR is returned if a parent directory is a file.
+func (r *S3FileRepository) MakeDirectory(ctx context.Context, dirPath string, parents bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rel, err := resolveS3Path(dirPath)
+	if err != nil {
+		return err
+	}
+
+	head, err := r.stat(ctx, rel, dirPath)
+	if err == nil {
+		if parents && head == nil {
+			return nil
+		}
+		return &fs.PathError{Op: "mkdir", Path: dirPath, Err: fs.ErrExist}
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if parentRel := path.Dir(rel); !parents && parentRel != "." {
+		if _, err := r.stat(ctx, parentRel, dirPath); err != nil {
+			return err
+		}
+	}
+	if err := r.checkParents(ctx, rel, "mkdir", dirPath); err != nil {
+		return err
+	}
+
+	_, err = r.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: &r.bucket,
+		Key:    aws.String(r.markerKey(rel)),
+		Body:   bytes.NewReader(nil),
+	})
+	return err
+}
+
+// WriteBufferSize returns the part size, every writer buffers a part in memory before uploading it.
+func (r *S3FileRepository) WriteBufferSize() int64 {
+	return r.partSize
+}
+
+// Probe checks that the bucket exists and can be accessed.
+func (r *S3FileRepository) Probe(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := r.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: &r.bucket})
+	if err != nil {
+		return fmt.Errorf("bucket %s: %w", r.bucket, err)
+	}
+	return nil
+}
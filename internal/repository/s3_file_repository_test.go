@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"filetransfer/api"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// s3TestBucket is the bucket created in the fake S3 service.
+const s3TestBucket = "files"
+
+// fakeS3 is an in-process S3-compatible service recording the requests it receives.
+type fakeS3 struct {
+	*httptest.Server
+	backend gofakes3.Backend
+	// beforeRequest is called with every request before it is served, if it is set.
+	beforeRequest func(request string)
+
+	mu       sync.Mutex
+	requests []string
+}
+
+// newFakeS3 starts a fake S3 service with an empty bucket.
+func newFakeS3(t *testing.T) *fakeS3 {
+	backend := s3mem.New()
+	require.NoError(t, backend.CreateBucket(s3TestBucket))
+	handler := gofakes3.New(backend, gofakes3.WithLogger(gofakes3.DiscardLog())).Server()
+
+	fake := &fakeS3{backend: backend}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.Path + "?" + r.URL.RawQuery
+		if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+			request += " " + rangeHeader
+		}
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, request)
+		beforeRequest := fake.beforeRequest
+		fake.mu.Unlock()
+		if beforeRequest != nil {
+			beforeRequest(request)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+// takeRequests returns the requests received since the last call.
+func (f *fakeS3) takeRequests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	requests := f.requests
+	f.requests = nil
+	return requests
+}
+
+// newRepository returns a repository serving the files below prefix in the bucket of the fake service.
+func (f *fakeS3) newRepository(t *testing.T, prefix string, partSize int64) *S3FileRepository {
+	repo, err := NewS3FileRepository(context.Background(), S3Config{
+		Endpoint:        f.URL,
+		Region:          "eu-central-1",
+		Bucket:          s3TestBucket,
+		Prefix:          prefix,
+		AccessKeyID:     "access-key",
+		SecretAccessKey: "secret-key",
+		PathStyle:       true,
+		PartSize:        partSize,
+	})
+	require.NoError(t, err)
+	return repo
+}
+
+func TestS3FileRepository_Behavior(t *testing.T) {
+	testFileRepository(t, func(t *testing.T) FileRepository {
+		return newFakeS3(t).newRepository(t, "root", 0)
+	})
+}
+
+func TestS3FileRepository_Requests(t *testing.T) {
+	fake := newFakeS3(t)
+	repo := fake.newRepository(t, "root/", 0)
+	writeFile(t, repo, "dir/file.txt", "0123456789")
+	fake.takeRequests()
+
+	// Listing maps to prefix listings, one per directory
+	_, err := repo.GetFileList(context.Background(), "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"GET /files?delimiter=%2F&list-type=2&prefix=root%2F",
+		"GET /files?delimiter=%2F&list-type=2&prefix=root%2Fdir%2F",
+	}, fake.takeRequests())
+
+	// Information maps to a HEAD request
+	_, err = repo.GetFileInfo(context.Background(), "dir/file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"HEAD /files/root/dir/file.txt?"}, fake.takeRequests())
+
+	// Content is read with a ranged GET request
+	reader, err := repo.GetFileReader(context.Background(), "dir/file.txt", 2, 5)
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, "23456", string(content))
+	assert.Equal(t, []string{
+		"HEAD /files/root/dir/file.txt?",
+		"GET /files/root/dir/file.txt?x-id=GetObject bytes=2-6",
+	}, fake.takeRequests())
+}
+
+func TestS3FileRepository_MultipartUpload(t *testing.T) {
+	fake := newFakeS3(t)
+	repo := fake.newRepository(t, "", 1024)
+	content := bytes.Repeat([]byte("0123456789"), 250)
+	assert.Equal(t, int64(1024), repo.WriteBufferSize())
+	assert.Equal(t, int64(DefaultS3PartSize), fake.newRepository(t, "", 0).WriteBufferSize())
+
+	// Content larger than a part is uploaded in parts, which are completed on Close
+	writer, err := repo.GetFileWriter(context.Background(), "file.txt")
+	require.NoError(t, err)
+	fake.takeRequests()
+	_, err = io.Copy(writer, bytes.NewReader(content))
+	require.NoError(t, err)
+	assertNotExist(t, repo, "file.txt")
+	require.NoError(t, writer.Close())
+
+	requests := fake.takeRequests()
+	var parts int
+	for _, request := range requests {
+		if strings.Contains(request, "partNumber=") {
+			parts++
+		}
+	}
+	assert.Equal(t, 3, parts)
+	assert.Contains(t, requests[0], "POST /files/file.txt?uploads")
+	assert.Contains(t, requests[len(requests)-1], "POST /files/file.txt?uploadId=")
+
+	assert.Equal(t, string(content), readFile(t, repo, "file.txt"))
+	fileMetadata, err := repo.GetFileInfo(context.Background(), "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(len(content)), fileMetadata.(*api.FileInfoResponse).Size)
+
+	// An aborted upload leaves no parts behind
+	writer, err = repo.GetFileWriter(context.Background(), "aborted.txt")
+	require.NoError(t, err)
+	_, err = writer.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Abort())
+	uploads, err := repo.client.ListMultipartUploads(context.Background(), &s3.ListMultipartUploadsInput{Bucket: &repo.bucket})
+	require.NoError(t, err)
+	assert.Empty(t, uploads.Uploads)
+	assertNotExist(t, repo, "aborted.txt")
+}
+
+func TestS3FileRepository_Prefix(t *testing.T) {
+	fake := newFakeS3(t)
+	repo := fake.newRepository(t, "/root/", 0)
+	bucket := fake.newRepository(t, "", 0)
+
+	// Files are stored below the prefix
+	writeFile(t, repo, "file.txt", "content")
+	assert.Equal(t, "content", readFile(t, bucket, "root/file.txt"))
+
+	// Directories are implied by the keys of objects stored by other clients
+	writeFile(t, bucket, "root/a/b/c.txt", "c")
+	writeFile(t, bucket, "other.txt", "other")
+	fileList, err := repo.GetFileList(context.Background(), "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{
+		{Name: "a", Path: "a", Type: api.EntryType_ENTRY_TYPE_DIRECTORY},
+		{Name: "b", Path: "a/b", Type: api.EntryType_ENTRY_TYPE_DIRECTORY},
+		{Name: "c.txt", Path: "a/b/c.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 1},
+		{Name: "file.txt", Path: "file.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 7},
+	}, fileList)
+
+	// Implied directories disappear with their last object
+	require.NoError(t, repo.DeleteFile(context.Background(), "a/b/c.txt", false))
+	assertNotExist(t, repo, "a")
+	assert.Equal(t, "other", readFile(t, bucket, "other.txt"))
+
+	// Keys with special characters are copied and renamed as well
+	writeFile(t, repo, "a+b c.txt", "special")
+	require.NoError(t, repo.RenameFile(context.Background(), "a+b c.txt", "dir/ä+ö.txt", false))
+	assert.Equal(t, "special", readFile(t, repo, "dir/ä+ö.txt"))
+	assertNotExist(t, repo, "a+b c.txt")
+}
+
+func TestS3FileRepository_Errors(t *testing.T) {
+	fake := newFakeS3(t)
+	repo := fake.newRepository(t, "", 0)
+	writeFile(t, repo, "dir/file.txt", "content")
+
+	// Existing directories are never replaced by files
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "dir/file.txt", "dir", true), fs.ErrExist)
+	_, err := repo.CopyFile(context.Background(), "dir/file.txt", "dir", true)
+	assert.ErrorIs(t, err, fs.ErrExist)
+	assert.ErrorIs(t, repo.RenameFile(context.Background(), "dir", "dir/sub", false), fs.ErrInvalid)
+	assert.ErrorIs(t, repo.MakeDirectory(context.Background(), "dir/file.txt/sub", false), syscall.ENOTDIR)
+
+	_, err = repo.GetFileReader(context.Background(), "dir", 0, 0)
+	assert.ErrorIs(t, err, ErrIsDirectory)
+
+	// A directory whose objects are deleted concurrently is reported as missing
+	fake.mu.Lock()
+	fake.beforeRequest = func(request string) {
+		if request == "GET /files?list-type=2&prefix=dir%2F" {
+			_, err := fake.backend.DeleteObject(s3TestBucket, "dir/file.txt")
+			assert.NoError(t, err)
+		}
+	}
+	fake.mu.Unlock()
+	assert.ErrorIs(t, repo.DeleteFile(context.Background(), "dir", false), fs.ErrNotExist)
+
+	// A missing bucket is reported by the probe
+	missing, err := NewS3FileRepository(context.Background(), S3Config{
+		Endpoint:        fake.URL,
+		Bucket:          "missing",
+		AccessKeyID:     "access-key",
+		SecretAccessKey: "secret-key",
+		PathStyle:       true,
+	})
+	require.NoError(t, err)
+	assert.Error(t, missing.Probe(context.Background()))
+}
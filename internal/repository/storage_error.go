@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// StorageError is an error of a repository storing files on the local file system. Its message names files by their
+// paths relative to the storage root, so the location of the storage is not exposed to clients. The original error,
+// naming the files by their location on the file system, is kept for the server log.
+type StorageError struct {
+	// Err is the error naming files relative to the storage root.
+	Err error
+	// Location is the original error.
+	Location error
+}
+
+// Error returns the message of the error naming files relative to the storage root.
+func (e *StorageError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error naming files relative to the storage root, which wraps the same cause as the original.
+func (e *StorageError) Unwrap() error {
+	return e.Err
+}
+
+// relativeError returns a *StorageError for a *fs.PathError or an *os.LinkError naming a path below one of roots,
+// with the path made relative to the first root containing it. Other errors are returned unchanged.
+func relativeError(err error, roots []string) error {
+	relative := func(path string) string {
+		for _, root := range roots {
+			if rel, err := filepath.Rel(root, path); err == nil && filepath.IsAbs(path) && filepath.IsLocal(rel) {
+				return filepath.ToSlash(rel)
+			}
+		}
+		return path
+	}
+
+	switch e := err.(type) {
+	case *fs.PathError:
+		if path := relative(e.Path); path != e.Path {
+			return &StorageError{Err: &fs.PathError{Op: e.Op, Path: path, Err: e.Err}, Location: err}
+		}
+	case *os.LinkError:
+		if oldPath, newPath := relative(e.Old), relative(e.New); oldPath != e.Old || newPath != e.New {
+			return &StorageError{Err: &os.LinkError{Op: e.Op, Old: oldPath, New: newPath, Err: e.Err}, Location: err}
+		}
+	}
+	return err
+}
+
+// namedError returns a *StorageError naming the file as requested by the client instead of the file on the file
+// system, for a *fs.PathError or an *os.LinkError of reading or writing its content. This hides temporary files and
+// the blobs of deduplicated content as well. Other errors are returned unchanged.
+func namedError(err error, name string) error {
+	switch e := err.(type) {
+	case *fs.PathError:
+		return &StorageError{Err: &fs.PathError{Op: e.Op, Path: name, Err: e.Err}, Location: err}
+	case *os.LinkError:
+		return &StorageError{Err: &fs.PathError{Op: "create", Path: name, Err: e.Err}, Location: err}
+	}
+	return err
+}
+
+// namedReader is a reader of a file whose errors name the file as requested by the client, see namedError.
+type namedReader struct {
+	io.ReadCloser
+	name string
+}
+
+// Read reads from the underlying reader.
+func (r *namedReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	return n, namedError(err, r.name)
+}
+
+// Close closes the underlying reader.
+func (r *namedReader) Close() error {
+	return namedError(r.ReadCloser.Close(), r.name)
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"filetransfer/internal/client"
+	"filetransfer/internal/logger"
+	"filetransfer/internal/repository"
+	"filetransfer/internal/usecase"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// largeFileSize is the size of the sparse file read by the cancellation tests, far more than is read before they cancel.
+const largeFileSize = 4 << 30
+
+// countingRepository counts the bytes read through the readers of the wrapped repository
+// and signals when a reader is closed.
+type countingRepository struct {
+	repository.FileRepository
+	read   atomic.Int64
+	closed chan struct{}
+}
+
+func (r *countingRepository) GetFileReader(ctx context.Context, filename string, offset, length int64) (io.ReadCloser, error) {
+	reader, err := r.FileRepository.GetFileReader(ctx, filename, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return &countingReader{ReadCloser: reader, repository: r}, nil
+}
+
+// countingReader adds the bytes it reads to the count of its repository.
+type countingReader struct {
+	io.ReadCloser
+	repository *countingRepository
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.repository.read.Add(int64(n))
+	return n, err
+}
+
+func (r *countingReader) Close() error {
+	err := r.ReadCloser.Close()
+	close(r.repository.closed)
+	return err
+}
+
+// startCancellationServer starts a server on a storage holding a large sparse file and returns
+// the counting repository of the server and a client connected to it.
+func startCancellationServer(t *testing.T, opts ...client.Option) (*countingRepository, *client.FileTransferClient) {
+	root := t.TempDir()
+	require.NoError(t, os.Truncate(createFile(t, filepath.Join(root, "large.bin")), largeFileSize))
+
+	log, err := logger.New(io.Discard, logger.FormatText, "error")
+	require.NoError(t, err)
+	counting := &countingRepository{FileRepository: repository.NewLocalFileRepository(root), closed: make(chan struct{})}
+	server := NewFileTransferServer(usecase.NewFileUsecase(counting), log)
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	t.Cleanup(server.Stop)
+
+	fileTransferClient, err := client.NewFileTransferClient(server.Addr().String(), log, opts...)
+	require.NoError(t, err)
+	t.Cleanup(fileTransferClient.Close)
+	return counting, fileTransferClient
+}
+
+// createFile creates an empty file and returns its path.
+func createFile(t *testing.T, path string) string {
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	return path
+}
+
+// assertStopped asserts that the server closed the reader of the repository soon after the call ended,
+// without reading the whole file.
+func assertStopped(t *testing.T, counting *countingRepository) {
+	select {
+	case <-counting.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server still reads the file after the call ended")
+	}
+	assert.Less(t, counting.read.Load(), int64(largeFileSize/4))
+}
+
+func TestFileTransferServer_HashStopsAtDeadline(t *testing.T) {
+	counting, fileTransferClient := startCancellationServer(t, client.WithCallTimeout(100*time.Millisecond), client.WithRetryPolicy(client.RetryPolicy{}))
+
+	_, err := fileTransferClient.GetFileInfo(context.Background(), "large.bin", true)
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assertStopped(t, counting)
+}
+
+func TestFileTransferServer_DownloadStopsWhenCanceled(t *testing.T) {
+	counting, fileTransferClient := startCancellationServer(t)
+
+	// The client cancels the download after receiving the first chunk
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := fileTransferClient.DownloadFile(ctx, "large.bin", writerFunc(func(p []byte) (int, error) {
+		cancel()
+		return len(p), nil
+	}))
+
+	assert.Equal(t, codes.Canceled, status.Code(err))
+	assertStopped(t, counting)
+}
+
+// writerFunc adapts a function to an io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"filetransfer/internal/client"
+	"filetransfer/internal/compression"
+	"filetransfer/internal/logger"
+	"filetransfer/internal/metrics"
+	"filetransfer/internal/repository"
+	"filetransfer/internal/usecase"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileTransferServer_Compression(t *testing.T) {
+	root := t.TempDir()
+	logContent := []byte(strings.Repeat("time=2023-10-18 level=info msg=\"gRPC call finished\"\n", 10000))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "server.log"), logContent, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "archive.zip"), logContent, 0644))
+
+	var logged bytes.Buffer
+	log, err := logger.New(&logged, logger.FormatText, "info")
+	require.NoError(t, err)
+	serverMetrics := metrics.NewServerMetrics()
+	fileUsecase := usecase.NewFileUsecase(repository.NewLocalFileRepository(root))
+	server := NewFileTransferServer(fileUsecase, log, WithMetrics(serverMetrics))
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	defer server.Stop()
+
+	for _, compressor := range []string{compression.Gzip, compression.Zstd} {
+		t.Run(compressor, func(t *testing.T) {
+			fileTransferClient, err := client.NewFileTransferClient(server.Addr().String(), log, client.WithCompression(compressor))
+			require.NoError(t, err)
+			defer fileTransferClient.Close()
+			before := savedBytes(t, serverMetrics)
+
+			var downloaded bytes.Buffer
+			_, err = fileTransferClient.DownloadFile(context.Background(), "server.log", &downloaded)
+			require.NoError(t, err)
+			assert.Equal(t, logContent, downloaded.Bytes())
+			_, err = fileTransferClient.UploadFile(context.Background(), "uploaded.log", bytes.NewReader(logContent))
+			require.NoError(t, err)
+
+			// Both transfers were compressed to a fraction of their size
+			assert.Greater(t, savedBytes(t, serverMetrics)-before, float64(2*len(logContent)*8/10))
+			assert.Contains(t, logged.String(), `msg="gRPC call compressed" method=/api.FileTransfer/DownloadFile`)
+		})
+	}
+
+	// Already compressed files are sent uncompressed, even if the client asks for compression
+	fileTransferClient, err := client.NewFileTransferClient(server.Addr().String(), log, client.WithCompression(compression.Zstd))
+	require.NoError(t, err)
+	defer fileTransferClient.Close()
+	before := savedBytes(t, serverMetrics)
+	_, err = fileTransferClient.DownloadFile(context.Background(), "archive.zip", io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, before, savedBytes(t, serverMetrics))
+}
+
+// savedBytes returns the number of bytes saved by compression in all calls.
+func savedBytes(t *testing.T, m *metrics.ServerMetrics) float64 {
+	return counterValue(t, m, "filetransfer_compression_saved_bytes_total")
+}
+
+// counterValue returns the sum of the values of the counter with the given name over all labels.
+func counterValue(t *testing.T, m *metrics.ServerMetrics, name string) float64 {
+	families, err := m.Registry().Gather()
+	require.NoError(t, err)
+
+	var sum float64
+	for _, family := range families {
+		if family.GetName() == name {
+			for _, metric := range family.GetMetric() {
+				sum += metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return sum
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"filetransfer/api"
+	"filetransfer/internal/client"
+	"filetransfer/internal/logger"
+	"filetransfer/internal/metrics"
+	"filetransfer/internal/repository"
+	"filetransfer/internal/usecase"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestFileTransferServer_DeltaDownload(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	oldContent := make([]byte, 1<<20)
+	random.Read(oldContent)
+
+	// The remote file has a changed block, an insertion and a cut-off end
+	newContent := append([]byte(nil), oldContent[:300000]...)
+	newContent = append(newContent, []byte("inserted")...)
+	newContent = append(newContent, oldContent[300000:900000]...)
+	copy(newContent[500000:], "changed")
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file.bin"), newContent, 0644))
+	var logged bytes.Buffer
+	log, err := logger.New(&logged, logger.FormatText, "info")
+	require.NoError(t, err)
+	serverMetrics := metrics.NewServerMetrics()
+	server := NewFileTransferServer(usecase.NewFileUsecase(repository.NewLocalFileRepository(root)), log, WithMetrics(serverMetrics))
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	defer server.Stop()
+
+	fileTransferClient, err := client.NewFileTransferClient(server.Addr().String(), log)
+	require.NoError(t, err)
+	defer fileTransferClient.Close()
+
+	localPath := filepath.Join(t.TempDir(), "file.bin")
+	require.NoError(t, os.WriteFile(localPath, oldContent, 0644))
+	report, err := fileTransferClient.DeltaDownload(context.Background(), "file.bin", localPath)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(localPath)
+	require.NoError(t, err)
+	assert.Equal(t, newContent, content)
+
+	// Only the blocks around the changes are downloaded
+	assert.True(t, report.Delta)
+	assert.Equal(t, int64(len(newContent)), report.ReusedBytes+report.LiteralBytes)
+	assert.Less(t, report.LiteralBytes, int64(8*1024))
+	assert.Greater(t, report.BytesSaved(), int64(800000))
+	assert.False(t, report.Unprofitable())
+	assert.Equal(t, float64(report.ReusedBytes), counterValue(t, serverMetrics, "filetransfer_delta_saved_bytes_total"))
+	assert.Contains(t, logged.String(), `msg="Delta download finished" filename=file.bin size=900008`)
+
+	// An unchanged file is copied from the local file completely
+	report, err = fileTransferClient.DeltaDownload(context.Background(), "file.bin", localPath)
+	require.NoError(t, err)
+	assert.Zero(t, report.LiteralBytes)
+
+	// Updated files of a sync are downloaded as a delta as well
+	syncDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(syncDir, "file.bin"), oldContent, 0644))
+	syncReport, err := fileTransferClient.Sync(context.Background(), "", syncDir, client.SyncOptions{Delta: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, syncReport.Updated)
+	assert.Less(t, syncReport.BytesTransferred, int64(8*1024))
+	assert.Greater(t, syncReport.BytesSaved, int64(800000))
+	assert.Zero(t, syncReport.UnprofitableDeltas)
+	content, err = os.ReadFile(filepath.Join(syncDir, "file.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, newContent, content)
+}
+
+func TestFileTransferServer_DeltaDownload_Unprofitable(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	remoteContent := make([]byte, 1<<20)
+	random.Read(remoteContent)
+	localContent := make([]byte, 1<<20)
+	random.Read(localContent)
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file.bin"), remoteContent, 0644))
+	log, err := logger.New(&bytes.Buffer{}, logger.FormatText, "info")
+	require.NoError(t, err)
+	server := NewFileTransferServer(usecase.NewFileUsecase(repository.NewLocalFileRepository(root)), log)
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	defer server.Stop()
+
+	fileTransferClient, err := client.NewFileTransferClient(server.Addr().String(), log)
+	require.NoError(t, err)
+	defer fileTransferClient.Close()
+
+	// A local file sharing no blocks with the remote file costs its signatures on top of the content
+	localPath := filepath.Join(t.TempDir(), "file.bin")
+	require.NoError(t, os.WriteFile(localPath, localContent, 0644))
+	report, err := fileTransferClient.DeltaDownload(context.Background(), "file.bin", localPath)
+	require.NoError(t, err)
+	assert.True(t, report.Delta)
+	assert.Zero(t, report.ReusedBytes)
+	assert.Negative(t, report.BytesSaved())
+	assert.True(t, report.Unprofitable())
+	content, err := os.ReadFile(localPath)
+	require.NoError(t, err)
+	assert.Equal(t, remoteContent, content)
+
+	// Syncs count such downloads
+	syncDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(syncDir, "file.bin"), localContent, 0644))
+	syncReport, err := fileTransferClient.Sync(context.Background(), "", syncDir, client.SyncOptions{Delta: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, syncReport.Updated)
+	assert.Equal(t, 1, syncReport.UnprofitableDeltas)
+	assert.Negative(t, syncReport.BytesSaved)
+}
+
+func TestFileTransferServer_DeltaDownload_InvalidRequests(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file.bin"), make([]byte, 10000), 0644))
+	log, err := logger.New(&bytes.Buffer{}, logger.FormatText, "info")
+	require.NoError(t, err)
+	server := NewFileTransferServer(usecase.NewFileUsecase(repository.NewLocalFileRepository(root)), log)
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	defer server.Stop()
+
+	conn, err := grpc.Dial(server.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	fileTransferClient := api.NewFileTransferClient(conn)
+
+	metadata := func(filename string, size uint64) *api.DeltaDownloadRequest {
+		return &api.DeltaDownloadRequest{Payload: &api.DeltaDownloadRequest_Metadata{
+			Metadata: &api.DeltaDownloadMetadata{Filename: filename, BlockSize: 1024, Size: size},
+		}}
+	}
+	signatures := func(n int) *api.DeltaDownloadRequest {
+		blocks := &api.BlockSignatures{}
+		for i := 0; i < n; i++ {
+			blocks.Blocks = append(blocks.Blocks, &api.BlockSignature{Strong: make([]byte, 16)})
+		}
+		return &api.DeltaDownloadRequest{Payload: &api.DeltaDownloadRequest_Signatures{Signatures: blocks}}
+	}
+
+	tests := []struct {
+		name     string
+		requests []*api.DeltaDownloadRequest
+		code     codes.Code
+	}{
+		{"signatures first", []*api.DeltaDownloadRequest{signatures(1)}, codes.InvalidArgument},
+		{"metadata twice", []*api.DeltaDownloadRequest{metadata("file.bin", 2048), metadata("file.bin", 2048)}, codes.InvalidArgument},
+		{"too many signatures", []*api.DeltaDownloadRequest{metadata("file.bin", 2048), signatures(3)}, codes.InvalidArgument},
+		{"missing signatures", []*api.DeltaDownloadRequest{metadata("file.bin", 2048), signatures(1)}, codes.InvalidArgument},
+		{"missing file", []*api.DeltaDownloadRequest{metadata("missing.bin", 2048), signatures(2)}, codes.NotFound},
+		{"empty filename", []*api.DeltaDownloadRequest{metadata("", 0)}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := fileTransferClient.DeltaDownload(context.Background())
+			require.NoError(t, err)
+			for _, req := range tt.requests {
+				require.NoError(t, stream.Send(req))
+			}
+			require.NoError(t, stream.CloseSend())
+
+			_, err = stream.Recv()
+			assert.Equal(t, tt.code, status.Code(err), err)
+		})
+	}
+}
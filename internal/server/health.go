@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"filetransfer/api"
+	"log/slog"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// defaultHealthCheckInterval is the time between two probes of the storage.
+	defaultHealthCheckInterval = 10 * time.Second
+	// defaultDrainTimeout is how long Stop waits for running calls before closing the connections.
+	defaultDrainTimeout = 30 * time.Second
+)
+
+// WithHealthCheckInterval sets the time between two probes of the storage updating the health status.
+func WithHealthCheckInterval(interval time.Duration) Option {
+	return func(s *FileTransferServer) {
+		s.healthCheckInterval = interval
+	}
+}
+
+// WithDrainTimeout sets how long Stop waits for running calls to finish before closing the connections.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(s *FileTransferServer) {
+		s.drainTimeout = timeout
+	}
+}
+
+// checkHealth probes the storage and updates the health status of the server and of the FileTransfer service.
+// Changes of the status are logged.
+func (s *FileTransferServer) checkHealth() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.healthCheckInterval)
+	defer cancel()
+
+	servingStatus := healthpb.HealthCheckResponse_SERVING
+	err := s.fileUsecase.CheckHealth(ctx)
+	if err != nil {
+		servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	if servingStatus != s.servingStatus {
+		if err != nil {
+			s.logger.Log(ctx, slog.LevelError, "Health status changed", "status", servingStatus.String(), "error", err)
+		} else {
+			s.logger.Log(ctx, slog.LevelInfo, "Health status changed", "status", servingStatus.String())
+		}
+		s.servingStatus = servingStatus
+	}
+
+	s.health.SetServingStatus("", servingStatus)
+	s.health.SetServingStatus(api.FileTransfer_ServiceDesc.ServiceName, servingStatus)
+}
+
+// probeHealth checks the health periodically until stop is closed.
+func (s *FileTransferServer) probeHealth(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.checkHealth()
+		}
+	}
+}
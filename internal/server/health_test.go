@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"filetransfer/internal/logger"
+	"filetransfer/internal/repository"
+	"filetransfer/internal/usecase"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// startHealthTestServer starts a server on a random local port serving root and returns a connection to it.
+func startHealthTestServer(t *testing.T, root string) (*FileTransferServer, *grpc.ClientConn) {
+	log, err := logger.New(io.Discard, logger.FormatText, "info")
+	require.NoError(t, err)
+	fileUsecase := usecase.NewFileUsecase(repository.NewLocalFileRepository(root))
+	server := NewFileTransferServer(fileUsecase, log, WithHealthCheckInterval(10*time.Millisecond), WithDrainTimeout(time.Second))
+	require.NoError(t, server.Start("127.0.0.1:0"))
+
+	conn, err := grpc.Dial(server.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return server, conn
+}
+
+func TestFileTransferServer_Health(t *testing.T) {
+	root := t.TempDir()
+	server, conn := startHealthTestServer(t, root)
+	defer server.Stop()
+	healthClient := healthpb.NewHealthClient(conn)
+
+	for _, service := range []string{"", "api.FileTransfer"} {
+		resp, err := healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status, service)
+	}
+
+	// The probe notices that the storage root is gone
+	require.NoError(t, os.Remove(root))
+	assert.Eventually(t, func() bool {
+		resp, err := healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "api.FileTransfer"})
+		return err == nil && resp.Status == healthpb.HealthCheckResponse_NOT_SERVING
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// And that it is back
+	require.NoError(t, os.Mkdir(root, 0755))
+	assert.Eventually(t, func() bool {
+		resp, err := healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		return err == nil && resp.Status == healthpb.HealthCheckResponse_SERVING
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestFileTransferServer_HealthNotServingOnStop(t *testing.T) {
+	server, conn := startHealthTestServer(t, t.TempDir())
+	healthClient := healthpb.NewHealthClient(conn)
+
+	watch, err := healthClient.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "api.FileTransfer"})
+	require.NoError(t, err)
+	resp, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	// The open watch stream must not keep Stop from returning
+	stopped := make(chan struct{})
+	go func() {
+		server.Stop()
+		close(stopped)
+	}()
+
+	resp, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return after the drain timeout")
+	}
+}
+
+func TestFileTransferServer_StopTwice(t *testing.T) {
+	server, _ := startHealthTestServer(t, t.TempDir())
+
+	// Stopping again, also concurrently, neither panics nor blocks
+	done := make(chan struct{})
+	go func() {
+		server.Stop()
+		close(done)
+	}()
+	server.Stop()
+	server.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+}
+
+func TestFileTransferServer_Reflection(t *testing.T) {
+	server, conn := startHealthTestServer(t, t.TempDir())
+	defer server.Stop()
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+
+	var services []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		services = append(services, service.Name)
+	}
+	assert.Contains(t, services, "api.FileTransfer")
+	assert.Contains(t, services, "grpc.health.v1.Health")
+	require.NoError(t, stream.CloseSend())
+}
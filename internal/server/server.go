@@ -2,100 +2,808 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
 	"filetransfer/api"
+	"filetransfer/internal/admission"
+	"filetransfer/internal/authz"
+	"filetransfer/internal/compression"
+	"filetransfer/internal/delta"
+	"filetransfer/internal/identity"
 	"filetransfer/internal/logger"
+	"filetransfer/internal/metrics"
+	"filetransfer/internal/ratelimit"
+	"filetransfer/internal/repository"
 	"filetransfer/internal/server/server_interceptor"
+	"filetransfer/internal/tracing"
 	"filetransfer/internal/usecase"
-	"fmt"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
+	"io"
+	"io/fs"
+	"log/slog"
 	"net"
+	"path"
+	"strings"
+	"sync"
+	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
 )
 
+// downloadChunkSize is the maximum number of content bytes sent in a single FileChunk.
+const downloadChunkSize = 64 * 1024
+
 // FileTransferServer represents the gRPC server for file transfer operations.
 type FileTransferServer struct {
 	fileUsecase *usecase.FileUsecase
 	server      *grpc.Server
-	logger      logger.ServerLogger
+	listener    net.Listener
+	health      *health.Server
+	// servingStatus is the health status set by the last probe.
+	servingStatus       healthpb.HealthCheckResponse_ServingStatus
+	healthCheckInterval time.Duration
+	stopProbe           chan struct{}
+	stopOnce            sync.Once
+	drainTimeout        time.Duration
+	logger              logger.ServerLogger
+	tlsConfig           *tls.Config
+	authorizer          authz.Authorizer
+	metrics             *metrics.ServerMetrics
+	rateLimiter         *ratelimit.Limiter
+	admission           *admission.Controller
+	tracer              trace.TracerProvider
+	// grpcOptions holds additional options of the underlying gRPC server.
+	grpcOptions []grpc.ServerOption
 	api.UnimplementedFileTransferServer
 }
 
+// Option configures optional behaviour of a FileTransferServer.
+type Option func(*FileTransferServer)
+
+// WithTLSConfig makes the server accept only TLS connections using the provided configuration.
+// Mutual TLS is enforced when the configuration requires and verifies client certificates.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(s *FileTransferServer) {
+		s.tlsConfig = tlsConfig
+	}
+}
+
+// WithAuthorizer makes the server check every request against the provided authorizer.
+// Without an authorizer every caller may perform every operation.
+func WithAuthorizer(authorizer authz.Authorizer) Option {
+	return func(s *FileTransferServer) {
+		s.authorizer = authorizer
+	}
+}
+
+// WithMetrics makes the server record the metrics of every call.
+func WithMetrics(m *metrics.ServerMetrics) Option {
+	return func(s *FileTransferServer) {
+		s.metrics = m
+	}
+}
+
+// WithRateLimiter makes the server limit the request rate and the transfer bandwidth of its clients.
+func WithRateLimiter(limiter *ratelimit.Limiter) Option {
+	return func(s *FileTransferServer) {
+		s.rateLimiter = limiter
+	}
+}
+
+// WithAdmissionController makes the server run calls only once the admission controller admitted them,
+// limiting the concurrent calls, transfers and buffered bytes.
+func WithAdmissionController(controller *admission.Controller) Option {
+	return func(s *FileTransferServer) {
+		s.admission = controller
+	}
+}
+
+// WithTracerProvider makes the server continue the traces of its clients with a span for every call,
+// created by the provided tracer provider.
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(s *FileTransferServer) {
+		s.tracer = tracerProvider
+	}
+}
+
+// WithMaxRecvMessageSize limits the size of a message received from a client in bytes.
+// A size of zero keeps the gRPC default.
+func WithMaxRecvMessageSize(size int) Option {
+	return func(s *FileTransferServer) {
+		if size > 0 {
+			s.grpcOptions = append(s.grpcOptions, grpc.MaxRecvMsgSize(size))
+		}
+	}
+}
+
+// WithMaxConcurrentStreams limits the number of concurrent calls per client connection.
+// A limit of zero keeps the gRPC default.
+func WithMaxConcurrentStreams(streams uint32) Option {
+	return func(s *FileTransferServer) {
+		if streams > 0 {
+			s.grpcOptions = append(s.grpcOptions, grpc.MaxConcurrentStreams(streams))
+		}
+	}
+}
+
 // NewFileTransferServer creates a new instance of FileTransferServer.
-func NewFileTransferServer(fileUsecase *usecase.FileUsecase, logger logger.ServerLogger) *FileTransferServer {
-	return &FileTransferServer{
-		fileUsecase: fileUsecase,
-		logger:      logger,
+func NewFileTransferServer(fileUsecase *usecase.FileUsecase, logger logger.ServerLogger, opts ...Option) *FileTransferServer {
+	s := &FileTransferServer{
+		fileUsecase:         fileUsecase,
+		logger:              logger,
+		healthCheckInterval: defaultHealthCheckInterval,
+		drainTimeout:        defaultDrainTimeout,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// Start starts the gRPC server on the specified port.
-func (s *FileTransferServer) Start(port int) error {
-	listen, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+// Start starts the gRPC server on the specified address, for example ":50051".
+func (s *FileTransferServer) Start(address string) error {
+	listen, err := net.Listen("tcp", address)
 	if err != nil {
-		s.logger.Printf("Error starting listener: %v", err)
+		s.logger.Log(context.Background(), slog.LevelError, "Error starting listener", "address", address, "error", err)
 		return err
 	}
 
-	s.server = grpc.NewServer(
-		grpc.ChainUnaryInterceptor(server_interceptor.LoggingInterceptor(s.logger), server_interceptor.ValidationInterceptor()),
-	)
+	// Tracing comes first, so the messages logged for a call carry its trace ID
+	var unaryInterceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
+	if s.tracer != nil {
+		tracingOptions := []otelgrpc.Option{otelgrpc.WithTracerProvider(s.tracer), otelgrpc.WithPropagators(tracing.Propagator())}
+		unaryInterceptors = append(unaryInterceptors, otelgrpc.UnaryServerInterceptor(tracingOptions...))
+		streamInterceptors = append(streamInterceptors, otelgrpc.StreamServerInterceptor(tracingOptions...))
+	}
+
+	// Metrics are recorded before rate limiting, admission and validation, so rejected requests are counted as well
+	unaryInterceptors = append(unaryInterceptors, server_interceptor.LoggingInterceptor(s.logger))
+	streamInterceptors = append(streamInterceptors, server_interceptor.StreamLoggingInterceptor(s.logger))
+	if s.metrics != nil {
+		unaryInterceptors = append(unaryInterceptors, server_interceptor.MetricsInterceptor(s.metrics))
+		streamInterceptors = append(streamInterceptors, server_interceptor.StreamMetricsInterceptor(s.metrics))
+	}
+	if s.rateLimiter != nil {
+		unaryInterceptors = append(unaryInterceptors, server_interceptor.RateLimitInterceptor(s.rateLimiter))
+		streamInterceptors = append(streamInterceptors, server_interceptor.StreamRateLimitInterceptor(s.rateLimiter))
+	}
+	if s.admission != nil {
+		unaryInterceptors = append(unaryInterceptors, server_interceptor.AdmissionInterceptor(s.admission, s.admissionCost))
+		streamInterceptors = append(streamInterceptors, server_interceptor.StreamAdmissionInterceptor(s.admission, s.admissionCost))
+	}
+	unaryInterceptors = append(unaryInterceptors, server_interceptor.ValidationInterceptor())
+	streamInterceptors = append(streamInterceptors, server_interceptor.StreamValidationInterceptor())
+
+	serverOptions := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+		grpc.StatsHandler(compression.NewStatsHandler(s.reportCompression)),
+	}
+	if s.tlsConfig != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+	serverOptions = append(serverOptions, s.grpcOptions...)
+
+	s.server = grpc.NewServer(serverOptions...)
+	s.listener = listen
 	api.RegisterFileTransferServer(s.server, s)
 
-	s.logger.Printf("gRPC server started on :%d\n", port)
+	// Register the standard health service, reporting the health of the storage, and server reflection
+	s.health = health.NewServer()
+	healthpb.RegisterHealthServer(s.server, s.health)
+	reflection.Register(s.server)
+	s.checkHealth()
+	s.stopProbe = make(chan struct{})
+	go s.probeHealth(s.stopProbe)
+
+	s.logger.Log(context.Background(), slog.LevelInfo, "gRPC server started", "address", listen.Addr().String(), "tls", s.tlsConfig != nil)
 
 	go func() {
 		if err := s.server.Serve(listen); err != nil {
-			s.logger.Printf("Error serving gRPC: %v", err)
+			s.logger.Log(context.Background(), slog.LevelError, "Error serving gRPC", "error", err)
 		}
 	}()
 
 	return nil
 }
 
-// Stop stops the gRPC server gracefully.
+// Addr returns the address the server listens on, or nil if it has not been started.
+// It reveals the port chosen by the system when the server was started on port 0.
+func (s *FileTransferServer) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Stop stops the gRPC server gracefully. The health status changes to NOT_SERVING first, so no new calls
+// are sent while the running calls finish. Connections still busy after the drain timeout are closed.
+// Stopping a server that is already stopped has no effect.
 func (s *FileTransferServer) Stop() {
 	if s.server != nil {
+		s.stopOnce.Do(s.stop)
+	}
+}
+
+// stop stops the started gRPC server, see Stop.
+func (s *FileTransferServer) stop() {
+	close(s.stopProbe)
+	s.health.Shutdown()
+	s.logger.Log(context.Background(), slog.LevelInfo, "Health status changed", "status", healthpb.HealthCheckResponse_NOT_SERVING.String())
+
+	stopped := make(chan struct{})
+	go func() {
 		s.server.GracefulStop()
-		s.logger.Printf("gRPC server stopped")
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(s.drainTimeout):
+		s.logger.Log(context.Background(), slog.LevelWarn, "Calls still running after the drain timeout, closing connections", "timeout", s.drainTimeout)
+		s.server.Stop()
+		<-stopped
 	}
+
+	s.logger.Log(context.Background(), slog.LevelInfo, "gRPC server stopped")
 }
 
 // handleError handles errors and returns a gRPC status with the appropriate code.
-func handleError(err error, msg string, code codes.Code) error {
-	if err != nil {
-		return status.Errorf(code, "%s: %v", msg, err)
+// The status names files as the error of a local repository does, relative to the storage root, their location
+// on the file system is only logged, at error level for internal errors.
+func (s *FileTransferServer) handleError(ctx context.Context, err error, msg string, code codes.Code) error {
+	if err == nil {
+		return nil
+	}
+
+	var storageErr *repository.StorageError
+	if errors.As(err, &storageErr) {
+		level := slog.LevelDebug
+		if code == codes.Internal {
+			level = slog.LevelError
+		}
+		s.logger.Log(ctx, level, msg, "error", storageErr.Location)
+	}
+	return status.Errorf(code, "%s: %v", msg, err)
+}
+
+// errorCode returns the gRPC code for repository errors with a well-known meaning, or the fallback code otherwise.
+func errorCode(err error, fallback codes.Code) codes.Code {
+	switch {
+	case errors.Is(err, repository.ErrPathOutsideRoot):
+		return codes.PermissionDenied
+	case errors.Is(err, repository.ErrInvalidRange):
+		return codes.OutOfRange
+	case errors.Is(err, repository.ErrStorageRoot):
+		return codes.InvalidArgument
+	case errors.Is(err, repository.ErrDirectoryNotEmpty), errors.Is(err, repository.ErrIsDirectory):
+		return codes.FailedPrecondition
+	case errors.Is(err, repository.ErrStorageFull):
+		return codes.ResourceExhausted
+	case errors.Is(err, fs.ErrNotExist):
+		return codes.NotFound
+	case errors.Is(err, fs.ErrExist):
+		return codes.AlreadyExists
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+	return fallback
+}
+
+// skipCompression sends the response of the call uncompressed if the file content is already compressed,
+// even if the client requested compression. It must be called before the first message is sent.
+func skipCompression(ctx context.Context, filename string, content []byte) {
+	if compression.IsCompressed(filename, content) {
+		// Fails only outside of a gRPC call, which only happens in tests
+		_ = grpc.SetSendCompressor(ctx, encoding.Identity)
+	}
+}
+
+// reportCompression logs and records the bytes compression saved in a call.
+func (s *FileTransferServer) reportCompression(ctx context.Context, method string, stats compression.Stats) {
+	// The request ID is only known from the metadata here, since the call context is gone
+	if id := metadata.ValueFromIncomingContext(ctx, logger.RequestIDMetadataKey); len(id) > 0 {
+		ctx = logger.ContextWithRequestID(ctx, id[0])
+	}
+	s.logger.Log(ctx, slog.LevelInfo, "gRPC call compressed", "method", method, "bytes", stats.Bytes,
+		"compressed_bytes", stats.CompressedBytes, "bytes_saved", stats.BytesSaved())
+
+	if s.metrics != nil {
+		s.metrics.AddBytesSaved(method, stats.BytesSaved())
+	}
+}
+
+// admissionCost returns the resources needed by a call of the method. Transfers buffer a chunk at a time,
+// uploads also the write buffer of the repository, except GetFileContent, which holds the whole file in memory.
+// GetFileInfo reading the whole file to hash it is a transfer as well.
+func (s *FileTransferServer) admissionCost(ctx context.Context, method string, req interface{}) admission.Cost {
+	switch method {
+	case api.FileTransfer_DownloadFile_FullMethodName:
+		return admission.Cost{Transfer: true, BufferedBytes: downloadChunkSize}
+	case api.FileTransfer_GetFileInfo_FullMethodName:
+		if r, ok := req.(*api.FileInfoRequest); ok && r.Hash {
+			return admission.Cost{Transfer: true, BufferedBytes: downloadChunkSize}
+		}
+	case api.FileTransfer_UploadFile_FullMethodName:
+		return admission.Cost{Transfer: true, BufferedBytes: downloadChunkSize + s.fileUsecase.WriteBufferSize()}
+	case api.FileTransfer_DeltaDownload_FullMethodName:
+		// The signatures are not known yet, only the buffer of the new file is accounted for
+		return admission.Cost{Transfer: true, BufferedBytes: 2 * downloadChunkSize}
+	case api.FileTransfer_GetFileContent_FullMethodName:
+		cost := admission.Cost{Transfer: true}
+		// Calls for files that cannot be read fail in the handler, which reports the error,
+		// the size of files the caller may not read is not revealed by rejecting the call
+		if r, ok := req.(*api.FileInfoRequest); ok && s.mayRead(ctx, r.Filename) {
+			if fileMetadata, err := s.fileUsecase.GetFileInfo(ctx, r.Filename); err == nil {
+				if info, ok := fileMetadata.(*api.FileInfoResponse); ok {
+					cost.BufferedBytes = int64(info.Size)
+				}
+			}
+		}
+		return cost
+	}
+	return admission.Cost{}
+}
+
+// mayRead reports whether the caller may read the file, without logging denied access.
+func (s *FileTransferServer) mayRead(ctx context.Context, filename string) bool {
+	if s.authorizer == nil {
+		return true
+	}
+	for _, name := range s.authorizedPaths(ctx, filename) {
+		if s.authorizer.Authorize(identity.FromContext(ctx), authz.OperationRead, name) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// authorizedPaths returns the paths the caller must be authorized for to access a path: the path itself and,
+// if it leads through symbolic links, the path they lead to. Policies match paths lexically, so a link to a path
+// outside of the authorized prefixes would give access to it otherwise. A path that cannot be resolved is only
+// checked as requested, accessing it fails in the repository.
+func (s *FileTransferServer) authorizedPaths(ctx context.Context, name string) []string {
+	resolved, err := s.fileUsecase.ResolveLinks(ctx, name)
+	if err != nil || resolved == name {
+		return []string{name}
+	}
+	return []string{name, resolved}
+}
+
+// authorize checks whether the caller may perform the operation on the path and on the path symbolic links on it
+// lead to. Denied requests are logged and answered with PermissionDenied.
+func (s *FileTransferServer) authorize(ctx context.Context, op authz.Operation, name string) error {
+	if s.authorizer == nil {
+		return nil
+	}
+
+	id := identity.FromContext(ctx)
+	for _, checked := range s.authorizedPaths(ctx, name) {
+		if err := s.authorizer.Authorize(id, op, checked); err != nil {
+			args := []any{"user", id.User, "groups", id.Groups, "operation", op, "path", name}
+			if checked != name {
+				args = append(args, "resolved_path", checked)
+			}
+			s.logger.Log(ctx, slog.LevelWarn, "Access denied", args...)
+			return status.Errorf(codes.PermissionDenied, "%s %s: %v", op, name, err)
+		}
 	}
 	return nil
 }
 
-// GetFileList retrieves the list of files from the repository.
+// authorizeStream checks whether the caller of the stream may perform the operation on the path.
+func (s *FileTransferServer) authorizeStream(stream grpc.ServerStream, op authz.Operation, name string) error {
+	if s.authorizer == nil {
+		return nil
+	}
+	return s.authorize(stream.Context(), op, name)
+}
+
+// GetFileList retrieves the entries below the requested directory from the repository.
+// A listing that is not recursive only contains the direct children of the directory.
+// Entries the caller is not allowed to see are left out.
 func (s *FileTransferServer) GetFileList(ctx context.Context, req *api.FileListRequest) (*api.FileListResponse, error) {
-	files, err := s.fileUsecase.GetFileList()
+	// Directories the caller may not see, also through symbolic links, are denied, the root is filtered below instead
+	var paths []string
+	if s.authorizer != nil && req.Path != "" {
+		paths = s.authorizedPaths(ctx, req.Path)
+		for _, name := range paths {
+			if !s.authorizer.CanSee(identity.FromContext(ctx), name) {
+				return nil, s.authorize(ctx, authz.OperationList, req.Path)
+			}
+		}
+	}
+
+	maxDepth := 1
+	if req.Recursive {
+		maxDepth = int(req.MaxDepth)
+	}
+
+	entries, err := s.fileUsecase.GetFileList(ctx, req.Path, maxDepth)
 	if err != nil {
-		return nil, handleError(err, "Error getting file list", codes.Internal)
+		return nil, s.handleError(ctx, err, "Error getting file list", errorCode(err, codes.Internal))
 	}
 
-	return &api.FileListResponse{Files: files}, nil
+	if s.authorizer != nil {
+		id := identity.FromContext(ctx)
+		visible := make([]*api.FileEntry, 0, len(entries))
+		for _, entry := range entries {
+			if s.authorizer.CanSee(id, entry.Path) && (len(paths) < 2 || s.authorizer.CanSee(id, linkedPath(entry.Path, req.Path, paths[1]))) {
+				visible = append(visible, entry)
+			}
+		}
+		entries = visible
+	}
+
+	resp := &api.FileListResponse{Entries: entries}
+	for _, entry := range entries {
+		if entry.Type == api.EntryType_ENTRY_TYPE_FILE {
+			resp.Files = append(resp.Files, entry.Path)
+		}
+	}
+	return resp, nil
+}
+
+// linkedPath returns the path an entry listed below dir is located at, if dir leads through symbolic links to
+// resolvedDir. An entry not below dir is returned as resolvedDir, so it is only visible with the directory.
+func linkedPath(entryPath, dir, resolvedDir string) string {
+	rel, ok := strings.CutPrefix(entryPath, path.Clean(dir)+"/")
+	if !ok {
+		return resolvedDir
+	}
+	return path.Join(resolvedDir, rel)
 }
 
 // GetFileInfo retrieves information about a specific file from the repository.
+// The content hash is only computed if requested, since it requires reading the whole file.
 func (s *FileTransferServer) GetFileInfo(ctx context.Context, req *api.FileInfoRequest) (*api.FileInfoResponse, error) {
-	fileMetadata, err := s.fileUsecase.GetFileInfo(req.Filename)
+	if err := s.authorize(ctx, authz.OperationInfo, req.Filename); err != nil {
+		return nil, err
+	}
+
+	fileMetadata, err := s.fileUsecase.GetFileInfo(ctx, req.Filename)
 	if err != nil {
-		return nil, handleError(err, "Error getting file metadata", codes.NotFound)
+		return nil, s.handleError(ctx, err, "Error getting file metadata", errorCode(err, codes.NotFound))
 	}
 
-	return fileMetadata.(*api.FileInfoResponse), nil
+	resp := fileMetadata.(*api.FileInfoResponse)
+	if req.Hash && resp.Type != api.EntryType_ENTRY_TYPE_DIRECTORY {
+		hash, err := s.fileUsecase.GetFileHash(ctx, req.Filename)
+		if err != nil {
+			return nil, s.handleError(ctx, err, "Error hashing file content", errorCode(err, codes.Internal))
+		}
+		resp.Sha256 = hash
+	}
+
+	return resp, nil
 }
 
 // GetFileContent retrieves the content of a specific file from the repository.
 func (s *FileTransferServer) GetFileContent(ctx context.Context, req *api.FileInfoRequest) (*api.FileContentResponse, error) {
-	content, err := s.fileUsecase.GetFileContent(req.Filename)
+	if err := s.authorize(ctx, authz.OperationRead, req.Filename); err != nil {
+		return nil, err
+	}
+
+	content, err := s.fileUsecase.GetFileContent(ctx, req.Filename)
 	if err != nil {
-		return nil, handleError(err, "Error getting file content", codes.Internal)
+		return nil, s.handleError(ctx, err, "Error getting file content", errorCode(err, codes.Internal))
 	}
+	skipCompression(ctx, req.Filename, content)
 
 	return &api.FileContentResponse{Filename: req.Filename, Content: content}, nil
 }
+
+// DownloadFile streams the requested byte range of a specific file from the repository in fixed-size chunks.
+// The final message carries the SHA-256 hash of the streamed content, so the client can verify what it received.
+// If the hash is requested from the start of the file, the content before the range is read and hashed first.
+func (s *FileTransferServer) DownloadFile(req *api.DownloadFileRequest, stream api.FileTransfer_DownloadFileServer) error {
+	if err := s.authorizeStream(stream, authz.OperationRead, req.Filename); err != nil {
+		return err
+	}
+
+	offset, length := req.Offset, req.Length
+	if req.HashFromStart {
+		offset = 0
+		if length > 0 {
+			length += req.Offset
+		}
+	}
+	reader, err := s.fileUsecase.GetFileReader(stream.Context(), req.Filename, offset, length)
+	if err != nil {
+		return s.handleError(stream.Context(), err, "Error opening file", errorCode(err, codes.NotFound))
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	if offset < req.Offset {
+		if _, err := io.CopyN(hash, reader, req.Offset); err != nil {
+			if err == io.EOF {
+				err = repository.ErrInvalidRange
+			}
+			return s.handleError(stream.Context(), err, "Error reading file content", errorCode(err, codes.Internal))
+		}
+	}
+	buf := make([]byte, downloadChunkSize)
+	for first := true; ; first = false {
+		n, err := io.ReadFull(reader, buf)
+		if first {
+			skipCompression(stream.Context(), req.Filename, buf[:n])
+		}
+		if n > 0 {
+			hash.Write(buf[:n])
+			if err := stream.Send(&api.FileChunk{Content: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return stream.Send(&api.FileChunk{Sha256: hex.EncodeToString(hash.Sum(nil))})
+		}
+		if err != nil {
+			return s.handleError(stream.Context(), err, "Error reading file content", errorCode(err, codes.Internal))
+		}
+	}
+}
+
+// DeltaDownload streams a file as a delta against the copy of the client. The first message of the stream
+// must carry the size of the copy and its block size, the following messages carry the signatures of its blocks.
+// Once the client closed its side of the stream, the file is read and sent as blocks to copy from the copy of the
+// client and literal data. The final message carries the SHA-256 hash of the file.
+func (s *FileTransferServer) DeltaDownload(stream api.FileTransfer_DeltaDownloadServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	meta := req.GetMetadata()
+	if meta == nil {
+		return status.Error(codes.InvalidArgument, "the first delta download message must carry the metadata")
+	}
+	if err := s.authorizeStream(stream, authz.OperationRead, meta.Filename); err != nil {
+		return err
+	}
+
+	blockSize := int(meta.BlockSize)
+	blockCount := delta.BlockCount(int64(meta.Size), blockSize)
+	if blockCount > delta.MaxBlocks {
+		return status.Errorf(codes.InvalidArgument, "the copy of the client has more than %d blocks", delta.MaxBlocks)
+	}
+
+	// The signatures of all blocks are needed before the file can be compared
+	signatures := make([]delta.Signature, 0, blockCount)
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		blocks := req.GetSignatures()
+		if blocks == nil {
+			return status.Error(codes.InvalidArgument, "expected block signatures after the metadata")
+		}
+		if int64(len(signatures)+len(blocks.Blocks)) > blockCount {
+			return status.Errorf(codes.InvalidArgument, "more than %d block signatures", blockCount)
+		}
+		for _, block := range blocks.Blocks {
+			signature := delta.Signature{Weak: block.Weak}
+			copy(signature.Strong[:], block.Strong)
+			signatures = append(signatures, signature)
+		}
+	}
+	index, err := delta.NewIndex(blockSize, int64(meta.Size), signatures)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid block signatures: %v", err)
+	}
+
+	reader, err := s.fileUsecase.GetFileReader(stream.Context(), meta.Filename, 0, 0)
+	if err != nil {
+		return s.handleError(stream.Context(), err, "Error opening file", errorCode(err, codes.NotFound))
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	sender := &deltaSender{stream: stream, filename: meta.Filename, chunk: &api.DeltaChunk{}}
+	stats, err := delta.Diff(io.TeeReader(reader, hash), index, sender.add)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return s.handleError(stream.Context(), err, "Error reading file content", errorCode(err, codes.Internal))
+	}
+	sender.chunk.Sha256 = hex.EncodeToString(hash.Sum(nil))
+	if err := sender.flush(); err != nil {
+		return err
+	}
+
+	s.logger.Log(stream.Context(), slog.LevelInfo, "Delta download finished", "filename", meta.Filename,
+		"size", stats.Reused+stats.Literal, "reused_bytes", stats.Reused, "literal_bytes", stats.Literal)
+	if s.metrics != nil {
+		s.metrics.AddDeltaBytesSaved(stats.Reused)
+	}
+	return nil
+}
+
+// deltaSender batches delta instructions into chunks of about downloadChunkSize literal bytes.
+type deltaSender struct {
+	stream   api.FileTransfer_DeltaDownloadServer
+	filename string
+	chunk    *api.DeltaChunk
+	// literal is the number of literal bytes in the chunk.
+	literal int
+	sent    bool
+}
+
+// maxDeltaInstructions is the maximum number of instructions sent in a single DeltaChunk.
+const maxDeltaInstructions = 4096
+
+// add adds an instruction to the chunk and sends the chunk when it is full.
+func (d *deltaSender) add(instruction delta.Instruction) error {
+	if instruction.Count > 0 {
+		d.chunk.Instructions = append(d.chunk.Instructions, &api.DeltaInstruction{
+			Operation: &api.DeltaInstruction_Copy{Copy: &api.BlockRange{Index: instruction.Index, Count: instruction.Count}},
+		})
+	} else {
+		// The literal data is only valid during the call
+		literal := append([]byte(nil), instruction.Literal...)
+		d.chunk.Instructions = append(d.chunk.Instructions, &api.DeltaInstruction{
+			Operation: &api.DeltaInstruction_Literal{Literal: literal},
+		})
+		d.literal += len(literal)
+	}
+
+	if d.literal >= downloadChunkSize || len(d.chunk.Instructions) >= maxDeltaInstructions {
+		return d.flush()
+	}
+	return nil
+}
+
+// flush sends the chunk, the first chunk is sent uncompressed if its literal data is already compressed.
+func (d *deltaSender) flush() error {
+	if !d.sent {
+		var content []byte
+		for _, instruction := range d.chunk.Instructions {
+			if literal := instruction.GetLiteral(); literal != nil {
+				content = literal
+				break
+			}
+		}
+		skipCompression(d.stream.Context(), d.filename, content)
+		d.sent = true
+	}
+
+	if err := d.stream.Send(d.chunk); err != nil {
+		return err
+	}
+	d.chunk = &api.DeltaChunk{}
+	d.literal = 0
+	return nil
+}
+
+// UploadFile receives a file from the client stream and stores it in the repository.
+// The first message of the stream must carry the file metadata, every following message carries a data chunk.
+func (s *FileTransferServer) UploadFile(stream api.FileTransfer_UploadFileServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	meta := req.GetMetadata()
+	if meta == nil {
+		return status.Error(codes.InvalidArgument, "the first upload message must carry file metadata")
+	}
+	if err := s.authorizeStream(stream, authz.OperationWrite, meta.Filename); err != nil {
+		return err
+	}
+
+	size, err := s.fileUsecase.UploadFile(stream.Context(), meta.Filename, &uploadStreamReader{stream: stream})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return s.handleError(stream.Context(), err, "Error storing file", errorCode(err, codes.Internal))
+	}
+
+	return stream.SendAndClose(&api.UploadFileResponse{Filename: meta.Filename, Size: uint64(size)})
+}
+
+// uploadStreamReader adapts the data chunks of an upload stream to an io.Reader.
+type uploadStreamReader struct {
+	stream api.FileTransfer_UploadFileServer
+	buf    []byte
+}
+
+// Read reads the data of the received chunks, receiving the next chunk from the stream when needed.
+func (r *uploadStreamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+
+		chunk, ok := req.Payload.(*api.UploadFileRequest_Chunk)
+		if !ok {
+			return 0, status.Error(codes.InvalidArgument, "expected a data chunk after the file metadata")
+		}
+		r.buf = chunk.Chunk
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// DeleteFile deletes a specific file or directory from the repository.
+func (s *FileTransferServer) DeleteFile(ctx context.Context, req *api.DeleteFileRequest) (*api.DeleteFileResponse, error) {
+	if err := s.authorize(ctx, authz.OperationDelete, req.Filename); err != nil {
+		return nil, err
+	}
+
+	if err := s.fileUsecase.DeleteFile(ctx, req.Filename, req.Recursive); err != nil {
+		return nil, s.handleError(ctx, err, "Error deleting file", errorCode(err, codes.Internal))
+	}
+
+	return &api.DeleteFileResponse{}, nil
+}
+
+// RenameFile renames or moves a file or directory within the repository.
+// The caller needs to be allowed to delete the source and to write the destination.
+func (s *FileTransferServer) RenameFile(ctx context.Context, req *api.RenameFileRequest) (*api.RenameFileResponse, error) {
+	if err := s.authorize(ctx, authz.OperationDelete, req.Source); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, authz.OperationWrite, req.Destination); err != nil {
+		return nil, err
+	}
+
+	if err := s.fileUsecase.RenameFile(ctx, req.Source, req.Destination, req.Overwrite); err != nil {
+		return nil, s.handleError(ctx, err, "Error renaming file", errorCode(err, codes.Internal))
+	}
+
+	return &api.RenameFileResponse{}, nil
+}
+
+// CopyFile copies a file within the repository.
+// The caller needs to be allowed to read the source and to write the destination.
+func (s *FileTransferServer) CopyFile(ctx context.Context, req *api.CopyFileRequest) (*api.CopyFileResponse, error) {
+	if err := s.authorize(ctx, authz.OperationRead, req.Source); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, authz.OperationWrite, req.Destination); err != nil {
+		return nil, err
+	}
+
+	written, err := s.fileUsecase.CopyFile(ctx, req.Source, req.Destination, req.Overwrite)
+	if err != nil {
+		return nil, s.handleError(ctx, err, "Error copying file", errorCode(err, codes.Internal))
+	}
+
+	return &api.CopyFileResponse{Size: uint64(written)}, nil
+}
+
+// MakeDirectory creates a directory in the repository.
+func (s *FileTransferServer) MakeDirectory(ctx context.Context, req *api.MakeDirectoryRequest) (*api.MakeDirectoryResponse, error) {
+	if err := s.authorize(ctx, authz.OperationWrite, req.Path); err != nil {
+		return nil, err
+	}
+
+	if err := s.fileUsecase.MakeDirectory(ctx, req.Path, req.Parents); err != nil {
+		return nil, s.handleError(ctx, err, "Error creating directory", errorCode(err, codes.Internal))
+	}
+
+	return &api.MakeDirectoryResponse{}, nil
+}
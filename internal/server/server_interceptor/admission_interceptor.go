@@ -0,0 +1,49 @@
+package server_interceptor
+
+import (
+	"context"
+	"filetransfer/internal/admission"
+
+	"google.golang.org/grpc"
+)
+
+// CostFunc returns the resources needed by a call of the full method with the request.
+// The request is nil for streams, whose messages are received by the handler.
+type CostFunc func(ctx context.Context, method string, req interface{}) admission.Cost
+
+// AdmissionInterceptor returns a unary server interceptor that runs a call only once the admission controller
+// admitted it with the cost returned by cost, and releases the reserved resources when the call has finished.
+// Calls that are not admitted are rejected with the error of the controller.
+// Only calls of the FileTransfer service are limited, health checks and reflection are always allowed.
+func AdmissionInterceptor(controller *admission.Controller, cost CostFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !isFileTransferMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		release, err := controller.Admit(ctx, cost(ctx, info.FullMethod, req))
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAdmissionInterceptor returns a stream server interceptor that admits streams like AdmissionInterceptor.
+func StreamAdmissionInterceptor(controller *admission.Controller, cost CostFunc) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !isFileTransferMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		release, err := controller.Admit(ss.Context(), cost(ss.Context(), info.FullMethod, nil))
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,64 @@
+package server_interceptor
+
+import (
+	"context"
+	"filetransfer/api"
+	"filetransfer/internal/admission"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdmissionInterceptor(t *testing.T) {
+	controller := admission.New(admission.Config{MaxBufferedBytes: 100, QueueTimeout: 10 * time.Millisecond})
+	interceptor := AdmissionInterceptor(controller, func(ctx context.Context, method string, req interface{}) admission.Cost {
+		assert.Equal(t, "/api.FileTransfer/GetFileContent", method)
+		return admission.Cost{Transfer: true, BufferedBytes: int64(len(req.(*api.FileInfoRequest).Filename))}
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.FileTransfer/GetFileContent"}
+
+	// A call holds its bytes while it runs, so a second call does not fit
+	_, err := interceptor(context.Background(), &api.FileInfoRequest{Filename: string(make([]byte, 60))}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		_, err := interceptor(ctx, &api.FileInfoRequest{Filename: string(make([]byte, 60))}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Fatal("the second call must not run")
+			return nil, nil
+		})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		return &api.FileContentResponse{}, nil
+	})
+	require.NoError(t, err)
+
+	// The bytes are released after the call
+	_, err = interceptor(context.Background(), &api.FileInfoRequest{Filename: string(make([]byte, 100))}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &api.FileContentResponse{}, nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestStreamAdmissionInterceptor(t *testing.T) {
+	controller := admission.New(admission.Config{MaxConcurrentTransfers: 1})
+	interceptor := StreamAdmissionInterceptor(controller, func(ctx context.Context, method string, req interface{}) admission.Cost {
+		assert.Nil(t, req)
+		return admission.Cost{Transfer: method == "/api.FileTransfer/DownloadFile"}
+	})
+	info := &grpc.StreamServerInfo{FullMethod: "/api.FileTransfer/DownloadFile", IsServerStream: true}
+	healthInfo := &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch", IsServerStream: true}
+
+	err := interceptor(nil, &fakeDownloadStream{ctx: context.Background()}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		// No second transfer is admitted while the first one runs, health checks are not limited
+		err := interceptor(nil, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+			return nil
+		})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		return interceptor(nil, stream, healthInfo, func(srv interface{}, stream grpc.ServerStream) error {
+			return nil
+		})
+	})
+
+	assert.NoError(t, err)
+}
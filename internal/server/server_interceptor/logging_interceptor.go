@@ -2,32 +2,213 @@ package server_interceptor
 
 import (
 	"context"
+	"filetransfer/api"
+	"filetransfer/internal/identity"
 	"filetransfer/internal/logger"
 	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"log/slog"
 	"time"
 
 	"google.golang.org/grpc"
 )
 
+// maxRequestIDLength is the maximum length of a request ID accepted from a client.
+const maxRequestIDLength = 128
+
 // LoggingInterceptor returns a unary server interceptor that logs information about gRPC method calls.
-func LoggingInterceptor(logger logger.ServerLogger) grpc.UnaryServerInterceptor {
+// Every call is logged with its method, peer, user, request ID, duration and status code,
+// as well as the requested filename and the number of content bytes sent or copied if applicable.
+func LoggingInterceptor(log logger.ServerLogger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		startTime := time.Now()
+		ctx = requestContext(ctx)
 
 		// Call the handler to process the request
 		resp, err := handler(ctx, req)
 
-		duration := time.Since(startTime)
-		logger.Printf("gRPC method %s took %s\n", info.FullMethod, duration)
+		attrs := append(callAttrs(ctx, info.FullMethod, time.Since(startTime)), requestAttrs(req)...)
+		attrs = append(attrs, responseAttrs(resp)...)
+		logCall(ctx, log, attrs, err)
 
-		// Log and return an error if the handler encounters an error
+		// Return an error naming the method if the handler encounters an error
 		if err != nil {
-			loggedError := fmt.Errorf("gRPC method %s failed: %v", info.FullMethod, err)
-			logger.Printf(loggedError.Error())
-			return nil, status.Error(status.Code(err), loggedError.Error())
+			return nil, withMessage(err, fmt.Sprintf("gRPC method %s failed: %v", info.FullMethod, err))
 		}
 
 		return resp, err
 	}
 }
+
+// StreamLoggingInterceptor returns a stream server interceptor that logs information about gRPC streaming method calls.
+// Every stream is logged with its method, peer, user, request ID, duration and status code,
+// as well as the transferred filename and the number of content bytes sent or received.
+func StreamLoggingInterceptor(log logger.ServerLogger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		startTime := time.Now()
+		stream := &loggingServerStream{ServerStream: ss, ctx: requestContext(ss.Context())}
+
+		// Call the handler to process the stream
+		err := handler(srv, stream)
+
+		attrs := callAttrs(stream.ctx, info.FullMethod, time.Since(startTime))
+		if stream.filename != "" {
+			attrs = append(attrs, "filename", stream.filename)
+		}
+		attrs = append(attrs, "bytes", stream.bytes)
+		logCall(stream.ctx, log, attrs, err)
+
+		// Return an error naming the method if the handler encounters an error
+		if err != nil {
+			return withMessage(err, fmt.Sprintf("gRPC stream %s failed: %v", info.FullMethod, err))
+		}
+
+		return nil
+	}
+}
+
+// requestContext returns a copy of ctx carrying the request ID sent by the client, or a new one if the
+// client did not send a usable ID. The request ID is sent back to the client in the response header.
+func requestContext(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(logger.RequestIDMetadataKey); len(values) > 0 && len(values[0]) <= maxRequestIDLength {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = logger.NewRequestID()
+	}
+
+	// Sending the header fails outside of a gRPC call, which only happens in tests
+	_ = grpc.SetHeader(ctx, metadata.Pairs(logger.RequestIDMetadataKey, id))
+
+	return logger.ContextWithRequestID(ctx, id)
+}
+
+// withMessage returns a status error with the code and details of err and the provided message.
+func withMessage(err error, msg string) error {
+	st := status.Convert(err).Proto()
+	st.Message = msg
+	return status.ErrorProto(st)
+}
+
+// callAttrs returns the attributes logged for every call.
+func callAttrs(ctx context.Context, method string, duration time.Duration) []any {
+	attrs := []any{"method", method}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		attrs = append(attrs, "peer", p.Addr.String())
+	}
+	if id := identity.FromContext(ctx); id.User != "" {
+		attrs = append(attrs, "user", id.User)
+	}
+	return append(attrs, "duration", duration)
+}
+
+// requestAttrs returns the paths addressed by a request.
+func requestAttrs(req interface{}) []any {
+	var attrs []any
+	if r, ok := req.(interface{ GetFilename() string }); ok && r.GetFilename() != "" {
+		attrs = append(attrs, "filename", r.GetFilename())
+	}
+	if r, ok := req.(interface{ GetPath() string }); ok && r.GetPath() != "" {
+		attrs = append(attrs, "path", r.GetPath())
+	}
+	if r, ok := req.(interface {
+		GetSource() string
+		GetDestination() string
+	}); ok {
+		attrs = append(attrs, "source", r.GetSource(), "destination", r.GetDestination())
+	}
+	return attrs
+}
+
+// responseAttrs returns the number of content bytes sent or copied by a response.
+func responseAttrs(resp interface{}) []any {
+	switch r := resp.(type) {
+	case *api.FileContentResponse:
+		return []any{"bytes", len(r.GetContent())}
+	case *api.CopyFileResponse:
+		return []any{"bytes", r.GetSize()}
+	}
+	return nil
+}
+
+// logCall logs a finished call with its status code, at info level if it succeeded,
+// at warn level if the client caused the error and at error level otherwise.
+func logCall(ctx context.Context, log logger.ServerLogger, attrs []any, err error) {
+	code := status.Code(err)
+	attrs = append(attrs, "code", code.String())
+
+	switch code {
+	case codes.OK:
+		log.Log(ctx, slog.LevelInfo, "gRPC call finished", attrs...)
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.FailedPrecondition, codes.OutOfRange, codes.Unauthenticated, codes.ResourceExhausted:
+		log.Log(ctx, slog.LevelWarn, "gRPC call failed", append(attrs, "error", status.Convert(err).Message())...)
+	default:
+		log.Log(ctx, slog.LevelError, "gRPC call failed", append(attrs, "error", status.Convert(err).Message())...)
+	}
+}
+
+// loggingServerStream wraps a grpc.ServerStream, replacing its context with one carrying the request ID
+// and recording the transferred filename and the number of content bytes.
+type loggingServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	filename string
+	bytes    int64
+}
+
+// Context returns the context carrying the request ID.
+func (s *loggingServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// SendMsg sends a message on the underlying stream, counting the content bytes of file chunks
+// and the literal bytes of delta chunks.
+func (s *loggingServerStream) SendMsg(m interface{}) error {
+	switch chunk := m.(type) {
+	case *api.FileChunk:
+		s.bytes += int64(len(chunk.GetContent()))
+	case *api.DeltaChunk:
+		s.bytes += int64(literalBytes(chunk))
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// literalBytes returns the number of literal content bytes in a delta chunk.
+func literalBytes(chunk *api.DeltaChunk) int {
+	n := 0
+	for _, instruction := range chunk.GetInstructions() {
+		n += len(instruction.GetLiteral())
+	}
+	return n
+}
+
+// RecvMsg receives a message from the underlying stream, recording the filename of download requests,
+// delta download and upload metadata and counting the content bytes of uploaded chunks.
+func (s *loggingServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	switch msg := m.(type) {
+	case *api.DownloadFileRequest:
+		s.filename = msg.GetFilename()
+	case *api.DeltaDownloadRequest:
+		if meta := msg.GetMetadata(); meta != nil {
+			s.filename = meta.GetFilename()
+		}
+	case *api.UploadFileRequest:
+		if meta := msg.GetMetadata(); meta != nil {
+			s.filename = meta.GetFilename()
+		}
+		s.bytes += int64(len(msg.GetChunk()))
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package server_interceptor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"filetransfer/api"
+	"filetransfer/internal/logger"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// newTestLogger returns a JSON logger and a function decoding the single message it logged.
+func newTestLogger(t *testing.T) (logger.ServerLogger, func() map[string]any) {
+	var buf bytes.Buffer
+	log, err := logger.New(&buf, logger.FormatJSON, "debug")
+	require.NoError(t, err)
+
+	return log, func() map[string]any {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		return record
+	}
+}
+
+// incomingContext returns a context of a call from 127.0.0.1 sending the request ID.
+func incomingContext(requestID string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 4242}})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(logger.RequestIDMetadataKey, requestID))
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	log, record := newTestLogger(t)
+	interceptor := LoggingInterceptor(log)
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.FileTransfer/GetFileContent"}
+
+	resp, err := interceptor(incomingContext("abc123"), &api.FileInfoRequest{Filename: "file.txt"}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		// The handler sees the request ID sent by the client
+		assert.Equal(t, "abc123", logger.RequestID(ctx))
+		return &api.FileContentResponse{Filename: "file.txt", Content: []byte("content")}, nil
+	})
+
+	require.NoError(t, err)
+	assert.NotNil(t, resp)
+	logged := record()
+	assert.Equal(t, "INFO", logged["level"])
+	assert.Equal(t, "/api.FileTransfer/GetFileContent", logged["method"])
+	assert.Equal(t, "127.0.0.1:4242", logged["peer"])
+	assert.Equal(t, "abc123", logged["request_id"])
+	assert.Equal(t, "file.txt", logged["filename"])
+	assert.Equal(t, float64(7), logged["bytes"])
+	assert.Equal(t, "OK", logged["code"])
+	assert.Contains(t, logged, "duration")
+}
+
+func TestLoggingInterceptor_Error(t *testing.T) {
+	log, record := newTestLogger(t)
+	interceptor := LoggingInterceptor(log)
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.FileTransfer/GetFileInfo"}
+
+	_, err := interceptor(context.Background(), &api.FileInfoRequest{Filename: "missing.txt"}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		// A request ID is generated if the client did not send one
+		assert.NotEmpty(t, logger.RequestID(ctx))
+		return nil, status.Error(codes.NotFound, "no such file")
+	})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	logged := record()
+	assert.Equal(t, "WARN", logged["level"])
+	assert.Equal(t, "NotFound", logged["code"])
+	assert.Equal(t, "no such file", logged["error"])
+	assert.NotEmpty(t, logged["request_id"])
+}
+
+func TestLoggingInterceptor_KeepsErrorDetails(t *testing.T) {
+	log, _ := newTestLogger(t)
+	interceptor := LoggingInterceptor(log)
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.FileTransfer/GetFileInfo"}
+
+	_, err := interceptor(context.Background(), &api.FileInfoRequest{Filename: "file.txt"}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		st, err := status.New(codes.ResourceExhausted, "slow down").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+		require.NoError(t, err)
+		return nil, st.Err()
+	})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Equal(t, "gRPC method /api.FileTransfer/GetFileInfo failed: rpc error: code = ResourceExhausted desc = slow down", st.Message())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, time.Second, st.Details()[0].(*errdetails.RetryInfo).RetryDelay.AsDuration())
+}
+
+// fakeDownloadStream is a server stream receiving a download request and accepting every sent message.
+type fakeDownloadStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeDownloadStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeDownloadStream) RecvMsg(m interface{}) error {
+	m.(*api.DownloadFileRequest).Filename = "big.bin"
+	return nil
+}
+
+func (s *fakeDownloadStream) SendMsg(m interface{}) error {
+	return nil
+}
+
+func TestStreamLoggingInterceptor(t *testing.T) {
+	log, record := newTestLogger(t)
+	interceptor := StreamLoggingInterceptor(log)
+	info := &grpc.StreamServerInfo{FullMethod: "/api.FileTransfer/DownloadFile", IsServerStream: true}
+
+	err := interceptor(nil, &fakeDownloadStream{ctx: incomingContext("abc123")}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		assert.Equal(t, "abc123", logger.RequestID(stream.Context()))
+
+		req := &api.DownloadFileRequest{}
+		require.NoError(t, stream.RecvMsg(req))
+		require.NoError(t, stream.SendMsg(&api.FileChunk{Content: make([]byte, 1000)}))
+		require.NoError(t, stream.SendMsg(&api.FileChunk{Content: make([]byte, 24)}))
+		return stream.SendMsg(&api.FileChunk{Sha256: "checksum"})
+	})
+
+	require.NoError(t, err)
+	logged := record()
+	assert.Equal(t, "/api.FileTransfer/DownloadFile", logged["method"])
+	assert.Equal(t, "abc123", logged["request_id"])
+	assert.Equal(t, "big.bin", logged["filename"])
+	assert.Equal(t, float64(1024), logged["bytes"])
+	assert.Equal(t, "OK", logged["code"])
+}
@@ -0,0 +1,80 @@
+package server_interceptor
+
+import (
+	"context"
+	"filetransfer/internal/metrics"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// MetricsInterceptor returns a unary server interceptor that records the status code, duration
+// and the size of the request and response of gRPC method calls.
+func MetricsInterceptor(m *metrics.ServerMetrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		startTime := time.Now()
+		m.AddBytesReceived(info.FullMethod, messageSize(req))
+
+		// Call the handler to process the request
+		resp, err := handler(ctx, req)
+
+		if err == nil {
+			m.AddBytesSent(info.FullMethod, messageSize(resp))
+		}
+		m.ObserveCall(info.FullMethod, status.Code(err), time.Since(startTime))
+
+		return resp, err
+	}
+}
+
+// StreamMetricsInterceptor returns a stream server interceptor that records the status code and duration
+// of gRPC streaming method calls, the size of every message sent and received and the transfers in progress.
+func StreamMetricsInterceptor(m *metrics.ServerMetrics) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		startTime := time.Now()
+		m.TransferStarted(info.FullMethod)
+		defer m.TransferFinished(info.FullMethod)
+
+		// Call the handler with a stream that measures the transferred messages
+		err := handler(srv, &metricsServerStream{ServerStream: ss, metrics: m, method: info.FullMethod})
+
+		m.ObserveCall(info.FullMethod, status.Code(err), time.Since(startTime))
+
+		return err
+	}
+}
+
+// messageSize returns the encoded size of a protobuf message, or zero for other values.
+func messageSize(m interface{}) int {
+	if msg, ok := m.(proto.Message); ok {
+		return proto.Size(msg)
+	}
+	return 0
+}
+
+// metricsServerStream wraps a grpc.ServerStream and records the size of every message sent and received.
+type metricsServerStream struct {
+	grpc.ServerStream
+	metrics *metrics.ServerMetrics
+	method  string
+}
+
+// SendMsg sends a message on the underlying stream and records its size.
+func (s *metricsServerStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.metrics.AddBytesSent(s.method, messageSize(m))
+	return nil
+}
+
+// RecvMsg receives a message from the underlying stream and records its size.
+func (s *metricsServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.metrics.AddBytesReceived(s.method, messageSize(m))
+	return nil
+}
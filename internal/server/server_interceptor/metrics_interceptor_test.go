@@ -0,0 +1,88 @@
+package server_interceptor
+
+import (
+	"context"
+	"filetransfer/api"
+	"filetransfer/internal/metrics"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMetricsInterceptor(t *testing.T) {
+	m := metrics.NewServerMetrics()
+	interceptor := MetricsInterceptor(m)
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.FileTransfer/GetFileInfo"}
+	req := &api.FileInfoRequest{Filename: "file.txt"}
+	resp := &api.FileInfoResponse{Filename: "file.txt", Size: 42}
+
+	_, err := interceptor(context.Background(), req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return resp, nil
+	})
+	require.NoError(t, err)
+	_, err = interceptor(context.Background(), req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "no such file")
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	expected := `
+# HELP filetransfer_grpc_requests_total Number of finished gRPC calls by method and status code.
+# TYPE filetransfer_grpc_requests_total counter
+filetransfer_grpc_requests_total{code="NotFound",method="/api.FileTransfer/GetFileInfo"} 1
+filetransfer_grpc_requests_total{code="OK",method="/api.FileTransfer/GetFileInfo"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(m.Registry(), strings.NewReader(expected), "filetransfer_grpc_requests_total"))
+	assertMetric(t, m, "filetransfer_grpc_received_bytes_total", 2*proto.Size(req))
+	assertMetric(t, m, "filetransfer_grpc_sent_bytes_total", proto.Size(resp))
+}
+
+func TestStreamMetricsInterceptor(t *testing.T) {
+	m := metrics.NewServerMetrics()
+	interceptor := StreamMetricsInterceptor(m)
+	info := &grpc.StreamServerInfo{FullMethod: "/api.FileTransfer/DownloadFile", IsServerStream: true}
+	chunk := &api.FileChunk{Content: make([]byte, 1024)}
+
+	err := interceptor(nil, &fakeDownloadStream{ctx: context.Background()}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		// The transfer is in progress while the handler runs
+		assertMetric(t, m, "filetransfer_transfers_in_flight", 1)
+
+		require.NoError(t, stream.RecvMsg(&api.DownloadFileRequest{}))
+		require.NoError(t, stream.SendMsg(chunk))
+		return stream.SendMsg(chunk)
+	})
+
+	require.NoError(t, err)
+	assertMetric(t, m, "filetransfer_transfers_in_flight", 0)
+	assertMetric(t, m, "filetransfer_grpc_sent_bytes_total", 2*proto.Size(chunk))
+	assertMetric(t, m, "filetransfer_grpc_received_bytes_total", proto.Size(&api.DownloadFileRequest{Filename: "big.bin"}))
+	assertMetric(t, m, "filetransfer_grpc_requests_total", 1)
+}
+
+// assertMetric asserts the value of the single series of the named metric.
+func assertMetric(t *testing.T, m *metrics.ServerMetrics, name string, value int) {
+	families, err := m.Registry().Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		require.Len(t, family.GetMetric(), 1)
+		metric := family.GetMetric()[0]
+		switch {
+		case metric.GetCounter() != nil:
+			assert.Equal(t, float64(value), metric.GetCounter().GetValue(), name)
+		case metric.GetGauge() != nil:
+			assert.Equal(t, float64(value), metric.GetGauge().GetValue(), name)
+		}
+		return
+	}
+	t.Fatalf("metric %s not found", name)
+}
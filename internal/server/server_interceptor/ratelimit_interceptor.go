@@ -0,0 +1,120 @@
+package server_interceptor
+
+import (
+	"context"
+	"filetransfer/api"
+	"filetransfer/internal/ratelimit"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RateLimitInterceptor returns a unary server interceptor that rejects calls exceeding the request rate
+// of the client with ResourceExhausted. The error carries a RetryInfo detail with the time to wait.
+// Only calls of the FileTransfer service are limited, health checks and reflection are always allowed.
+func RateLimitInterceptor(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if isFileTransferMethod(info.FullMethod) {
+			if err := allowRequest(ctx, limiter); err != nil {
+				return nil, err
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimitInterceptor returns a stream server interceptor that rejects streams exceeding the request rate
+// of the client like RateLimitInterceptor, and shapes the content bytes sent and received on the stream
+// to the bandwidth of the client and the global bandwidth.
+func StreamRateLimitInterceptor(limiter *ratelimit.Limiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !isFileTransferMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		if err := allowRequest(ss.Context(), limiter); err != nil {
+			return err
+		}
+
+		return handler(srv, &rateLimitedServerStream{ServerStream: ss, limiter: limiter, key: ratelimit.ClientKey(ss.Context())})
+	}
+}
+
+// isFileTransferMethod reports whether the full method name belongs to the FileTransfer service.
+func isFileTransferMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+api.FileTransfer_ServiceDesc.ServiceName+"/")
+}
+
+// allowRequest returns a ResourceExhausted error with retry information if the client of ctx exceeds its request rate.
+func allowRequest(ctx context.Context, limiter *ratelimit.Limiter) error {
+	allowed, retryAfter := limiter.AllowRequest(ratelimit.ClientKey(ctx))
+	if allowed {
+		return nil
+	}
+
+	// Round up, so retrying after the given delay succeeds
+	retryAfter = retryAfter.Truncate(time.Millisecond) + time.Millisecond
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf("request rate limit exceeded, retry after %v", retryAfter))
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// rateLimitedServerStream wraps a grpc.ServerStream and delays the transfer of file content
+// until the bandwidth limits allow it.
+type rateLimitedServerStream struct {
+	grpc.ServerStream
+	limiter *ratelimit.Limiter
+	key     string
+}
+
+// SendMsg waits until the content of file chunks or the literal data of delta chunks may be sent
+// and sends the message on the underlying stream.
+func (s *rateLimitedServerStream) SendMsg(m interface{}) error {
+	n := 0
+	switch chunk := m.(type) {
+	case *api.FileChunk:
+		n = len(chunk.GetContent())
+	case *api.DeltaChunk:
+		n = literalBytes(chunk)
+	}
+	if n > 0 {
+		if err := s.wait(n); err != nil {
+			return err
+		}
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// RecvMsg receives a message from the underlying stream and waits until the content of uploaded chunks
+// was allowed, which delays receiving the next chunk and slows down the client.
+func (s *rateLimitedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if msg, ok := m.(*api.UploadFileRequest); ok {
+		return s.wait(len(msg.GetChunk()))
+	}
+	return nil
+}
+
+// wait blocks until n content bytes may be transferred, it fails if the stream ends first.
+func (s *rateLimitedServerStream) wait(n int) error {
+	if err := s.limiter.WaitBytes(s.Context(), s.key, n); err != nil {
+		if ctxErr := s.Context().Err(); ctxErr != nil {
+			return status.FromContextError(ctxErr).Err()
+		}
+		// The wait would outlast the deadline of the call
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return nil
+}
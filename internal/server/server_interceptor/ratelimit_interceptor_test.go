@@ -0,0 +1,111 @@
+package server_interceptor
+
+import (
+	"context"
+	"filetransfer/api"
+	"filetransfer/internal/ratelimit"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// peerContext returns a context of a call from the IP address.
+func peerContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000}})
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	interceptor := RateLimitInterceptor(ratelimit.New(ratelimit.Config{RequestsPerSecond: 1, RequestBurst: 2}))
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.FileTransfer/GetFileInfo"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &api.FileInfoResponse{}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := interceptor(peerContext("192.0.2.1"), &api.FileInfoRequest{}, info, handler)
+		require.NoError(t, err)
+	}
+
+	// The third request exceeds the burst and is rejected with retry information
+	_, err := interceptor(peerContext("192.0.2.1"), &api.FileInfoRequest{}, info, handler)
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.Greater(t, retryInfo.RetryDelay.AsDuration(), time.Duration(0))
+	assert.LessOrEqual(t, retryInfo.RetryDelay.AsDuration(), time.Second+time.Millisecond)
+
+	// Other clients and health checks are not affected
+	_, err = interceptor(peerContext("192.0.2.2"), &api.FileInfoRequest{}, info, handler)
+	assert.NoError(t, err)
+	healthInfo := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	_, err = interceptor(peerContext("192.0.2.1"), nil, healthInfo, handler)
+	assert.NoError(t, err)
+}
+
+func TestStreamRateLimitInterceptor_Bandwidth(t *testing.T) {
+	interceptor := StreamRateLimitInterceptor(ratelimit.New(ratelimit.Config{ClientBytesPerSecond: 10 * 1024}))
+	info := &grpc.StreamServerInfo{FullMethod: "/api.FileTransfer/DownloadFile", IsServerStream: true}
+	chunk := &api.FileChunk{Content: make([]byte, 5*1024)}
+
+	// The first second of bandwidth is available at once, the following 5 KiB take half a second
+	start := time.Now()
+	err := interceptor(nil, &fakeDownloadStream{ctx: peerContext("192.0.2.1")}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		for i := 0; i < 3; i++ {
+			if err := stream.SendMsg(chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+}
+
+func TestStreamRateLimitInterceptor_DeltaBandwidth(t *testing.T) {
+	interceptor := StreamRateLimitInterceptor(ratelimit.New(ratelimit.Config{ClientBytesPerSecond: 10 * 1024}))
+	info := &grpc.StreamServerInfo{FullMethod: "/api.FileTransfer/DeltaDownload", IsClientStream: true, IsServerStream: true}
+
+	// Only literal data counts, copied blocks are not sent
+	chunk := &api.DeltaChunk{Instructions: []*api.DeltaInstruction{
+		{Operation: &api.DeltaInstruction_Copy{Copy: &api.BlockRange{Index: 0, Count: 1000}}},
+		{Operation: &api.DeltaInstruction_Literal{Literal: make([]byte, 5*1024)}},
+	}}
+
+	start := time.Now()
+	err := interceptor(nil, &fakeDownloadStream{ctx: peerContext("192.0.2.1")}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		for i := 0; i < 3; i++ {
+			if err := stream.SendMsg(chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestStreamRateLimitInterceptor_Canceled(t *testing.T) {
+	interceptor := StreamRateLimitInterceptor(ratelimit.New(ratelimit.Config{GlobalBytesPerSecond: 1024}))
+	info := &grpc.StreamServerInfo{FullMethod: "/api.FileTransfer/DownloadFile", IsServerStream: true}
+	ctx, cancel := context.WithCancel(peerContext("192.0.2.1"))
+	cancel()
+
+	err := interceptor(nil, &fakeDownloadStream{ctx: ctx}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return stream.SendMsg(&api.FileChunk{Content: make([]byte, 4096)})
+	})
+
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
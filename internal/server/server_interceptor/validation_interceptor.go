@@ -24,3 +24,34 @@ func ValidationInterceptor() grpc.UnaryServerInterceptor {
 		return resp, err
 	}
 }
+
+// StreamValidationInterceptor returns a stream server interceptor that performs validation on every message
+// received from the client stream.
+func StreamValidationInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		// Call the handler with a stream that validates incoming messages
+		return handler(srv, &validatingServerStream{ServerStream: ss})
+	}
+}
+
+// validatingServerStream wraps a grpc.ServerStream and validates each received message.
+type validatingServerStream struct {
+	grpc.ServerStream
+}
+
+// RecvMsg receives a message from the underlying stream and validates it.
+func (s *validatingServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	// Check if the message type implements the Validate method
+	if v, ok := m.(interface{ Validate() error }); ok {
+		// Validate the message and return an error if validation fails
+		if err := v.Validate(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	return nil
+}
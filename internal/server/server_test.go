@@ -2,13 +2,28 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
 	"errors"
 	"filetransfer/api"
+	"filetransfer/internal/admission"
+	"filetransfer/internal/authz"
 	"filetransfer/internal/logger"
 	"filetransfer/internal/repository"
 	"filetransfer/internal/usecase"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -23,15 +38,54 @@ func TestFileTransferServer_GetFileList(t *testing.T) {
 	fileUsecase := usecase.NewFileUsecase(mockRepo)
 	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
 
-	mockRepo.EXPECT().GetFileList().Return([]string{"file1.txt", "file2.txt"}, nil)
+	entries := []*api.FileEntry{
+		{Name: "file1.txt", Path: "file1.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 10},
+		{Name: "dir", Path: "dir", Type: api.EntryType_ENTRY_TYPE_DIRECTORY},
+		{Name: "file2.txt", Path: "file2.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 20},
+	}
+	mockRepo.EXPECT().GetFileList(gomock.Any(), "", 1).Return(entries, nil)
 
 	resp, err := server.GetFileList(context.Background(), &api.FileListRequest{})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
+	assert.Equal(t, entries, resp.Entries)
 	assert.Equal(t, []string{"file1.txt", "file2.txt"}, resp.Files)
 }
 
+func TestFileTransferServer_GetFileList_Recursive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	mockRepo.EXPECT().GetFileList(gomock.Any(), "dir", 0).Return([]*api.FileEntry{}, nil)
+	mockRepo.EXPECT().GetFileList(gomock.Any(), "dir", 3).Return([]*api.FileEntry{}, nil)
+
+	_, err := server.GetFileList(context.Background(), &api.FileListRequest{Path: "dir", Recursive: true})
+	assert.NoError(t, err)
+
+	_, err = server.GetFileList(context.Background(), &api.FileListRequest{Path: "dir", Recursive: true, MaxDepth: 3})
+	assert.NoError(t, err)
+}
+
+func TestFileTransferServer_GetFileList_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	mockRepo.EXPECT().GetFileList(gomock.Any(), "missing", 1).Return(nil, fs.ErrNotExist)
+
+	_, err := server.GetFileList(context.Background(), &api.FileListRequest{Path: "missing"})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
 func TestFileTransferServer_GetFileInfo(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -40,7 +94,7 @@ func TestFileTransferServer_GetFileInfo(t *testing.T) {
 	fileUsecase := usecase.NewFileUsecase(mockRepo)
 	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
 
-	mockRepo.EXPECT().GetFileInfo("file1.txt").Return(&api.FileInfoResponse{Filename: "file1.txt", Size: 100}, nil)
+	mockRepo.EXPECT().GetFileInfo(gomock.Any(), "file1.txt").Return(&api.FileInfoResponse{Filename: "file1.txt", Size: 100}, nil)
 
 	resp, err := server.GetFileInfo(context.Background(), &api.FileInfoRequest{Filename: "file1.txt"})
 
@@ -50,6 +104,31 @@ func TestFileTransferServer_GetFileInfo(t *testing.T) {
 	assert.Equal(t, uint64(100), resp.Size)
 }
 
+func TestFileTransferServer_GetFileInfo_Hash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	mockRepo.EXPECT().GetFileInfo(gomock.Any(), "file1.txt").Return(&api.FileInfoResponse{Filename: "file1.txt", Size: 7, Type: api.EntryType_ENTRY_TYPE_FILE}, nil)
+	mockRepo.EXPECT().GetFileReader(gomock.Any(), "file1.txt", int64(0), int64(0)).Return(io.NopCloser(strings.NewReader("content")), nil)
+
+	resp, err := server.GetFileInfo(context.Background(), &api.FileInfoRequest{Filename: "file1.txt", Hash: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73", resp.Sha256)
+
+	// Directories have no content to hash
+	mockRepo.EXPECT().GetFileInfo(gomock.Any(), "dir").Return(&api.FileInfoResponse{Filename: "dir", Type: api.EntryType_ENTRY_TYPE_DIRECTORY}, nil)
+
+	resp, err = server.GetFileInfo(context.Background(), &api.FileInfoRequest{Filename: "dir", Hash: true})
+
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Sha256)
+}
+
 func TestFileTransferServer_GetFileContent(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -58,7 +137,7 @@ func TestFileTransferServer_GetFileContent(t *testing.T) {
 	fileUsecase := usecase.NewFileUsecase(mockRepo)
 	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
 
-	mockRepo.EXPECT().GetFileContent("file1.txt").Return([]byte("file content"), nil)
+	mockRepo.EXPECT().GetFileContent(gomock.Any(), "file1.txt").Return([]byte("file content"), nil)
 
 	resp, err := server.GetFileContent(context.Background(), &api.FileInfoRequest{Filename: "file1.txt"})
 
@@ -68,6 +147,93 @@ func TestFileTransferServer_GetFileContent(t *testing.T) {
 	assert.Equal(t, []byte("file content"), resp.Content)
 }
 
+// bufferedRepository is a repository whose writers buffer bufferSize bytes in memory.
+type bufferedRepository struct {
+	*repository.MockFileRepository
+	bufferSize int64
+}
+
+// WriteBufferSize returns the buffer size of the writers.
+func (r *bufferedRepository) WriteBufferSize() int64 {
+	return r.bufferSize
+}
+
+func TestFileTransferServer_AdmissionCost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{}, WithAuthorizer(newTestAuthorizer(t)))
+
+	mockRepo.EXPECT().GetFileInfo(gomock.Any(), "big.bin").Return(&api.FileInfoResponse{Filename: "big.bin", Size: 1 << 20}, nil)
+
+	// GetFileContent holds the whole file in memory
+	cost := server.admissionCost(contextWithClientCertificate("alice"), api.FileTransfer_GetFileContent_FullMethodName, &api.FileInfoRequest{Filename: "big.bin"})
+	assert.Equal(t, admission.Cost{Transfer: true, BufferedBytes: 1 << 20}, cost)
+
+	// The size of files the caller may not read is not looked up
+	cost = server.admissionCost(contextWithClientCertificate("bob", "developers"), api.FileTransfer_GetFileContent_FullMethodName, &api.FileInfoRequest{Filename: "big.bin"})
+	assert.Equal(t, admission.Cost{Transfer: true}, cost)
+
+	cost = server.admissionCost(context.Background(), api.FileTransfer_DownloadFile_FullMethodName, nil)
+	assert.Equal(t, admission.Cost{Transfer: true, BufferedBytes: downloadChunkSize}, cost)
+	cost = server.admissionCost(context.Background(), api.FileTransfer_UploadFile_FullMethodName, nil)
+	assert.Equal(t, admission.Cost{Transfer: true, BufferedBytes: downloadChunkSize}, cost)
+
+	// Uploads hold the write buffer of the repository as well
+	buffered := NewFileTransferServer(usecase.NewFileUsecase(&bufferedRepository{MockFileRepository: mockRepo, bufferSize: 8 << 20}), &logger.MockServerLogger{})
+	cost = buffered.admissionCost(context.Background(), api.FileTransfer_UploadFile_FullMethodName, nil)
+	assert.Equal(t, admission.Cost{Transfer: true, BufferedBytes: downloadChunkSize + 8<<20}, cost)
+	cost = server.admissionCost(context.Background(), api.FileTransfer_DeleteFile_FullMethodName, &api.DeleteFileRequest{Filename: "big.bin"})
+	assert.Equal(t, admission.Cost{}, cost)
+
+	// Hashing reads the whole file like a download
+	cost = server.admissionCost(context.Background(), api.FileTransfer_GetFileInfo_FullMethodName, &api.FileInfoRequest{Filename: "big.bin", Hash: true})
+	assert.Equal(t, admission.Cost{Transfer: true, BufferedBytes: downloadChunkSize}, cost)
+	cost = server.admissionCost(context.Background(), api.FileTransfer_GetFileInfo_FullMethodName, &api.FileInfoRequest{Filename: "big.bin"})
+	assert.Equal(t, admission.Cost{}, cost)
+}
+
+func TestFileTransferServer_GetFileInfo_PathOutsideRoot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	mockRepo.EXPECT().GetFileInfo(gomock.Any(), "../etc/shadow").Return(nil, repository.ErrPathOutsideRoot)
+
+	resp, err := server.GetFileInfo(context.Background(), &api.FileInfoRequest{Filename: "../etc/shadow"})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestFileTransferServer_GetFileInfo_StorageError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockLogger := logger.NewMockServerLogger(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, mockLogger)
+
+	location := &fs.PathError{Op: "stat", Path: "/srv/root/missing/file.txt", Err: fs.ErrNotExist}
+	storageErr := &repository.StorageError{Err: &fs.PathError{Op: "stat", Path: "missing/file.txt", Err: fs.ErrNotExist}, Location: location}
+	mockRepo.EXPECT().GetFileInfo(gomock.Any(), "missing/file.txt").Return(nil, storageErr)
+
+	// The location on the file system is only logged
+	mockLogger.EXPECT().Log(gomock.Any(), slog.LevelDebug, "Error getting file metadata", "error", location)
+
+	_, err := server.GetFileInfo(context.Background(), &api.FileInfoRequest{Filename: "missing/file.txt"})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "Error getting file metadata: stat missing/file.txt: file does not exist", status.Convert(err).Message())
+}
+
 func TestFileTransferServer_GetFileList_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -76,7 +242,7 @@ func TestFileTransferServer_GetFileList_Error(t *testing.T) {
 	fileUsecase := usecase.NewFileUsecase(mockRepo)
 	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
 
-	mockRepo.EXPECT().GetFileList().Return(nil, errors.New("mock error"))
+	mockRepo.EXPECT().GetFileList(gomock.Any(), "", 1).Return(nil, errors.New("mock error"))
 
 	resp, err := server.GetFileList(context.Background(), &api.FileListRequest{})
 
@@ -84,3 +250,477 @@ func TestFileTransferServer_GetFileList_Error(t *testing.T) {
 	assert.Nil(t, resp)
 	assert.Equal(t, codes.Internal, status.Code(err))
 }
+
+func TestFileTransferServer_DownloadFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileServer(ctrl)
+	mockStream.EXPECT().Context().Return(context.Background()).AnyTimes()
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	content := strings.Repeat("x", downloadChunkSize+10)
+	mockRepo.EXPECT().GetFileReader(gomock.Any(), "file1.txt", int64(0), int64(0)).Return(io.NopCloser(strings.NewReader(content)), nil)
+
+	var received []byte
+	var chunks int
+	var checksum string
+	mockStream.EXPECT().Send(gomock.Any()).DoAndReturn(func(chunk *api.FileChunk) error {
+		assert.LessOrEqual(t, len(chunk.Content), downloadChunkSize)
+		assert.Empty(t, checksum, "no message may follow the checksum")
+		received = append(received, chunk.Content...)
+		checksum = chunk.Sha256
+		if len(chunk.Content) > 0 {
+			chunks++
+		}
+		return nil
+	}).Times(3)
+
+	err := server.DownloadFile(&api.DownloadFileRequest{Filename: "file1.txt"}, mockStream)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, chunks)
+	assert.Equal(t, content, string(received))
+	expected := sha256.Sum256([]byte(content))
+	assert.Equal(t, hex.EncodeToString(expected[:]), checksum)
+}
+
+func TestFileTransferServer_DownloadFile_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileServer(ctrl)
+	mockStream.EXPECT().Context().Return(context.Background()).AnyTimes()
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	mockRepo.EXPECT().GetFileReader(gomock.Any(), "missing.txt", int64(0), int64(0)).Return(nil, errors.New("mock error"))
+
+	err := server.DownloadFile(&api.DownloadFileRequest{Filename: "missing.txt"}, mockStream)
+
+	assert.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestFileTransferServer_DownloadFile_Range(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileServer(ctrl)
+	mockStream.EXPECT().Context().Return(context.Background()).AnyTimes()
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	mockRepo.EXPECT().GetFileReader(gomock.Any(), "file1.txt", int64(5), int64(7)).Return(io.NopCloser(strings.NewReader("content")), nil)
+	gomock.InOrder(
+		mockStream.EXPECT().Send(&api.FileChunk{Content: []byte("content")}).Return(nil),
+		mockStream.EXPECT().Send(&api.FileChunk{Sha256: "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73"}).Return(nil),
+	)
+
+	err := server.DownloadFile(&api.DownloadFileRequest{Filename: "file1.txt", Offset: 5, Length: 7}, mockStream)
+
+	assert.NoError(t, err)
+}
+
+func TestFileTransferServer_DownloadFile_HashFromStart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileServer(ctrl)
+	mockStream.EXPECT().Context().Return(context.Background()).AnyTimes()
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	// The content before the range is hashed, but not sent
+	mockRepo.EXPECT().GetFileReader(gomock.Any(), "file1.txt", int64(0), int64(0)).Return(io.NopCloser(strings.NewReader("file content")), nil)
+	gomock.InOrder(
+		mockStream.EXPECT().Send(&api.FileChunk{Content: []byte("content")}).Return(nil),
+		mockStream.EXPECT().Send(&api.FileChunk{Sha256: "e0ac3601005dfa1864f5392aabaf7d898b1b5bab854f1acb4491bcd806b76b0c"}).Return(nil),
+	)
+
+	err := server.DownloadFile(&api.DownloadFileRequest{Filename: "file1.txt", Offset: 5, HashFromStart: true}, mockStream)
+	assert.NoError(t, err)
+
+	// A range starting beyond the end of the file is out of range
+	mockRepo.EXPECT().GetFileReader(gomock.Any(), "file1.txt", int64(0), int64(102)).Return(io.NopCloser(strings.NewReader("file content")), nil)
+
+	err = server.DownloadFile(&api.DownloadFileRequest{Filename: "file1.txt", Offset: 100, Length: 2, HashFromStart: true}, mockStream)
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+}
+
+func TestFileTransferServer_DownloadFile_InvalidRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileServer(ctrl)
+	mockStream.EXPECT().Context().Return(context.Background()).AnyTimes()
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	mockRepo.EXPECT().GetFileReader(gomock.Any(), "file1.txt", int64(100), int64(0)).Return(nil, repository.ErrInvalidRange)
+
+	err := server.DownloadFile(&api.DownloadFileRequest{Filename: "file1.txt", Offset: 100}, mockStream)
+
+	assert.Error(t, err)
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+}
+
+func TestFileTransferServer_UploadFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockWriter := repository.NewMockFileWriter(ctrl)
+	mockStream := api.NewMockFileTransfer_UploadFileServer(ctrl)
+	mockStream.EXPECT().Context().Return(context.Background()).AnyTimes()
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	gomock.InOrder(
+		mockStream.EXPECT().Recv().Return(&api.UploadFileRequest{
+			Payload: &api.UploadFileRequest_Metadata{Metadata: &api.UploadFileMetadata{Filename: "file1.txt"}},
+		}, nil),
+		mockStream.EXPECT().Recv().Return(&api.UploadFileRequest{Payload: &api.UploadFileRequest_Chunk{Chunk: []byte("file ")}}, nil),
+		mockStream.EXPECT().Recv().Return(&api.UploadFileRequest{Payload: &api.UploadFileRequest_Chunk{Chunk: []byte("content")}}, nil),
+		mockStream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	var stored []byte
+	mockRepo.EXPECT().GetFileWriter(gomock.Any(), "file1.txt").Return(mockWriter, nil)
+	mockWriter.EXPECT().Write(gomock.Any()).DoAndReturn(func(p []byte) (int, error) {
+		stored = append(stored, p...)
+		return len(p), nil
+	}).AnyTimes()
+	mockWriter.EXPECT().Close().Return(nil)
+	mockStream.EXPECT().SendAndClose(&api.UploadFileResponse{Filename: "file1.txt", Size: 12}).Return(nil)
+
+	err := server.UploadFile(mockStream)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("file content"), stored)
+}
+
+func TestFileTransferServer_UploadFile_StorageFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockWriter := repository.NewMockFileWriter(ctrl)
+	mockStream := api.NewMockFileTransfer_UploadFileServer(ctrl)
+	mockStream.EXPECT().Context().Return(context.Background()).AnyTimes()
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	gomock.InOrder(
+		mockStream.EXPECT().Recv().Return(&api.UploadFileRequest{
+			Payload: &api.UploadFileRequest_Metadata{Metadata: &api.UploadFileMetadata{Filename: "file1.txt"}},
+		}, nil),
+		mockStream.EXPECT().Recv().Return(&api.UploadFileRequest{Payload: &api.UploadFileRequest_Chunk{Chunk: []byte("content")}}, nil),
+	)
+
+	mockRepo.EXPECT().GetFileWriter(gomock.Any(), "file1.txt").Return(mockWriter, nil)
+	mockWriter.EXPECT().Write(gomock.Any()).Return(0, repository.ErrStorageFull)
+	mockWriter.EXPECT().Abort().Return(nil)
+
+	err := server.UploadFile(mockStream)
+
+	assert.Error(t, err)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestFileTransferServer_UploadFile_MissingMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockStream := api.NewMockFileTransfer_UploadFileServer(ctrl)
+	mockStream.EXPECT().Context().Return(context.Background()).AnyTimes()
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	mockStream.EXPECT().Recv().Return(&api.UploadFileRequest{Payload: &api.UploadFileRequest_Chunk{Chunk: []byte("data")}}, nil)
+
+	err := server.UploadFile(mockStream)
+
+	assert.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// contextWithClientCertificate returns a context of a peer authenticated with a client certificate
+// for the provided user and groups.
+func contextWithClientCertificate(user string, groups ...string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: user, OrganizationalUnit: groups}}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+	})
+}
+
+func newTestAuthorizer(t *testing.T) authz.Authorizer {
+	policy, err := authz.NewPolicy([]authz.Rule{
+		{Users: []string{"alice"}, Paths: []string{"/"}, Operations: []authz.Operation{authz.OperationList, authz.OperationInfo, authz.OperationRead, authz.OperationWrite, authz.OperationDelete}},
+		{Groups: []string{"developers"}, Paths: []string{"public.txt"}, Operations: []authz.Operation{authz.OperationList, authz.OperationRead}},
+	})
+	assert.NoError(t, err)
+	return policy
+}
+
+func TestFileTransferServer_SymlinkAuthorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "public"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "secret", "shared"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "public", "file.txt"), []byte("public"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "secret", "key.txt"), []byte("secret"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "secret", "shared", "file.txt"), []byte("shared"), 0644))
+	assert.NoError(t, os.Symlink("../secret", filepath.Join(root, "public", "link")))
+	assert.NoError(t, os.Symlink("file.txt", filepath.Join(root, "public", "alias.txt")))
+
+	policy, err := authz.NewPolicy([]authz.Rule{
+		{Groups: []string{"developers"}, Paths: []string{"public", "secret/shared"}, Operations: []authz.Operation{authz.OperationList, authz.OperationInfo, authz.OperationRead, authz.OperationWrite}},
+	})
+	assert.NoError(t, err)
+	mockLogger := logger.NewMockServerLogger(ctrl)
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	server := NewFileTransferServer(usecase.NewFileUsecase(repository.NewLocalFileRepository(root)), mockLogger, WithAuthorizer(policy))
+	ctx := contextWithClientCertificate("bob", "developers")
+
+	// A link within the authorized prefix is followed
+	resp, err := server.GetFileContent(ctx, &api.FileInfoRequest{Filename: "public/alias.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("public"), resp.Content)
+
+	// A link leading outside of the authorized prefixes gives no access to its target
+	_, err = server.GetFileContent(ctx, &api.FileInfoRequest{Filename: "public/link/key.txt"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = server.GetFileInfo(ctx, &api.FileInfoRequest{Filename: "public/link/key.txt"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = server.MakeDirectory(ctx, &api.MakeDirectoryRequest{Path: "public/link/new"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.NoDirExists(t, filepath.Join(root, "secret", "new"))
+
+	// Listing through the link shows only what may be seen at its target
+	list, err := server.GetFileList(ctx, &api.FileListRequest{Path: "public/link", Recursive: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"public/link/shared/file.txt"}, list.Files)
+	_, err = server.GetFileContent(ctx, &api.FileInfoRequest{Filename: "public/link/shared/file.txt"})
+	assert.NoError(t, err)
+}
+
+func TestFileTransferServer_GetFileList_Authorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{}, WithAuthorizer(newTestAuthorizer(t)))
+
+	public := &api.FileEntry{Name: "public.txt", Path: "public.txt", Type: api.EntryType_ENTRY_TYPE_FILE}
+	secret := &api.FileEntry{Name: "secret.txt", Path: "secret.txt", Type: api.EntryType_ENTRY_TYPE_FILE}
+	mockRepo.EXPECT().GetFileList(gomock.Any(), "", 1).Return([]*api.FileEntry{public, secret}, nil).Times(3)
+
+	resp, err := server.GetFileList(contextWithClientCertificate("alice"), &api.FileListRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{public, secret}, resp.Entries)
+
+	resp, err = server.GetFileList(contextWithClientCertificate("bob", "developers"), &api.FileListRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.FileEntry{public}, resp.Entries)
+	assert.Equal(t, []string{"public.txt"}, resp.Files)
+
+	resp, err = server.GetFileList(context.Background(), &api.FileListRequest{})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Entries)
+
+	// Directories that may not be seen are denied
+	mockLogger := logger.NewMockServerLogger(ctrl)
+	server = NewFileTransferServer(fileUsecase, mockLogger, WithAuthorizer(newTestAuthorizer(t)))
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+
+	_, err = server.GetFileList(contextWithClientCertificate("bob", "developers"), &api.FileListRequest{Path: "private"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestFileTransferServer_GetFileInfo_PermissionDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockLogger := logger.NewMockServerLogger(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, mockLogger, WithAuthorizer(newTestAuthorizer(t)))
+
+	mockLogger.EXPECT().Log(gomock.Any(), slog.LevelWarn, "Access denied", gomock.Any()).Do(func(ctx context.Context, level slog.Level, msg string, args ...any) {
+		assert.Equal(t, []any{"user", "bob", "groups", []string{"developers"}, "operation", authz.OperationInfo, "path", "public.txt"}, args)
+	})
+
+	resp, err := server.GetFileInfo(contextWithClientCertificate("bob", "developers"), &api.FileInfoRequest{Filename: "public.txt"})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestFileTransferServer_DownloadFile_Authorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockLogger := logger.NewMockServerLogger(ctrl)
+	mockStream := api.NewMockFileTransfer_DownloadFileServer(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, mockLogger, WithAuthorizer(newTestAuthorizer(t)))
+
+	ctx := contextWithClientCertificate("bob", "developers")
+	mockStream.EXPECT().Context().Return(ctx).AnyTimes()
+	mockRepo.EXPECT().GetFileReader(gomock.Any(), "public.txt", int64(0), int64(0)).Return(io.NopCloser(strings.NewReader("content")), nil)
+	mockStream.EXPECT().Send(&api.FileChunk{Content: []byte("content")}).Return(nil)
+	mockStream.EXPECT().Send(&api.FileChunk{Sha256: "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73"}).Return(nil)
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+
+	err := server.DownloadFile(&api.DownloadFileRequest{Filename: "public.txt"}, mockStream)
+	assert.NoError(t, err)
+
+	err = server.DownloadFile(&api.DownloadFileRequest{Filename: "secret.txt"}, mockStream)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestFileTransferServer_UploadFile_PermissionDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockLogger := logger.NewMockServerLogger(ctrl)
+	mockStream := api.NewMockFileTransfer_UploadFileServer(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, mockLogger, WithAuthorizer(newTestAuthorizer(t)))
+
+	mockStream.EXPECT().Context().Return(contextWithClientCertificate("bob", "developers"))
+	mockStream.EXPECT().Recv().Return(&api.UploadFileRequest{
+		Payload: &api.UploadFileRequest_Metadata{Metadata: &api.UploadFileMetadata{Filename: "public.txt"}},
+	}, nil)
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+
+	err := server.UploadFile(mockStream)
+
+	assert.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestFileTransferServer_DeleteFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	mockRepo.EXPECT().DeleteFile(gomock.Any(), "file1.txt", false).Return(nil)
+	mockRepo.EXPECT().DeleteFile(gomock.Any(), "missing.txt", false).Return(fs.ErrNotExist)
+	mockRepo.EXPECT().DeleteFile(gomock.Any(), "dir", false).Return(repository.ErrDirectoryNotEmpty)
+	mockRepo.EXPECT().DeleteFile(gomock.Any(), ".", true).Return(repository.ErrStorageRoot)
+
+	resp, err := server.DeleteFile(context.Background(), &api.DeleteFileRequest{Filename: "file1.txt"})
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+
+	_, err = server.DeleteFile(context.Background(), &api.DeleteFileRequest{Filename: "missing.txt"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = server.DeleteFile(context.Background(), &api.DeleteFileRequest{Filename: "dir"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = server.DeleteFile(context.Background(), &api.DeleteFileRequest{Filename: ".", Recursive: true})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestFileTransferServer_RenameFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	mockRepo.EXPECT().RenameFile(gomock.Any(), "a.txt", "dir/b.txt", false).Return(nil)
+	mockRepo.EXPECT().RenameFile(gomock.Any(), "a.txt", "b.txt", false).Return(&fs.PathError{Op: "create", Path: "b.txt", Err: fs.ErrExist})
+
+	resp, err := server.RenameFile(context.Background(), &api.RenameFileRequest{Source: "a.txt", Destination: "dir/b.txt"})
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+
+	_, err = server.RenameFile(context.Background(), &api.RenameFileRequest{Source: "a.txt", Destination: "b.txt"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestFileTransferServer_CopyFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	mockRepo.EXPECT().CopyFile(gomock.Any(), "a.txt", "b.txt", true).Return(int64(7), nil)
+	mockRepo.EXPECT().CopyFile(gomock.Any(), "dir", "b.txt", false).Return(int64(0), repository.ErrIsDirectory)
+
+	resp, err := server.CopyFile(context.Background(), &api.CopyFileRequest{Source: "a.txt", Destination: "b.txt", Overwrite: true})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), resp.Size)
+
+	_, err = server.CopyFile(context.Background(), &api.CopyFileRequest{Source: "dir", Destination: "b.txt"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestFileTransferServer_MakeDirectory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, &logger.MockServerLogger{})
+
+	mockRepo.EXPECT().MakeDirectory(gomock.Any(), "a/b", true).Return(nil)
+	mockRepo.EXPECT().MakeDirectory(gomock.Any(), "a", false).Return(fs.ErrExist)
+
+	resp, err := server.MakeDirectory(context.Background(), &api.MakeDirectoryRequest{Path: "a/b", Parents: true})
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+
+	_, err = server.MakeDirectory(context.Background(), &api.MakeDirectoryRequest{Path: "a"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestFileTransferServer_RenameFile_PermissionDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockLogger := logger.NewMockServerLogger(ctrl)
+	fileUsecase := usecase.NewFileUsecase(mockRepo)
+	server := NewFileTransferServer(fileUsecase, mockLogger, WithAuthorizer(newTestAuthorizer(t)))
+
+	mockLogger.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(3)
+
+	// Members of the developers group may only read public.txt
+	_, err := server.RenameFile(contextWithClientCertificate("bob", "developers"), &api.RenameFileRequest{Source: "public.txt", Destination: "other.txt"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = server.CopyFile(contextWithClientCertificate("bob", "developers"), &api.CopyFileRequest{Source: "public.txt", Destination: "other.txt"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = server.DeleteFile(contextWithClientCertificate("bob", "developers"), &api.DeleteFileRequest{Filename: "public.txt"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Alice may do everything
+	mockRepo.EXPECT().RenameFile(gomock.Any(), "public.txt", "other.txt", false).Return(nil)
+	_, err = server.RenameFile(contextWithClientCertificate("alice"), &api.RenameFileRequest{Source: "public.txt", Destination: "other.txt"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"filetransfer/internal/client"
+	"filetransfer/internal/logger"
+	"filetransfer/internal/repository"
+	"filetransfer/internal/usecase"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestFileTransferServer_Tracing(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file.txt"), []byte("content"), 0644))
+
+	// Client and server export their spans to separate in-memory exporters, like separate processes would
+	serverExporter := tracetest.NewInMemoryExporter()
+	serverTracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(serverExporter))
+	clientExporter := tracetest.NewInMemoryExporter()
+	clientTracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(clientExporter))
+
+	log, err := logger.New(io.Discard, logger.FormatText, "info")
+	require.NoError(t, err)
+	fileUsecase := usecase.NewFileUsecase(repository.NewLocalFileRepository(root), usecase.WithTracerProvider(serverTracerProvider))
+	server := NewFileTransferServer(fileUsecase, log, WithTracerProvider(serverTracerProvider))
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	defer server.Stop()
+
+	fileTransferClient, err := client.NewFileTransferClient(server.Addr().String(), log, client.WithTracerProvider(clientTracerProvider))
+	require.NoError(t, err)
+	defer fileTransferClient.Close()
+
+	_, err = fileTransferClient.GetFileInfo(context.Background(), "file.txt", false)
+	require.NoError(t, err)
+
+	// The client span is the root of the trace
+	clientSpans := clientExporter.GetSpans()
+	require.Len(t, clientSpans, 1)
+	clientSpan := clientSpans[0]
+	assert.Equal(t, "api.FileTransfer/GetFileInfo", clientSpan.Name)
+	assert.Equal(t, trace.SpanKindClient, clientSpan.SpanKind)
+	assert.False(t, clientSpan.Parent.IsValid())
+
+	// The server continues the trace: server span, use case span, repository span
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range serverExporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	require.Len(t, spans, 3)
+	serverSpan := spans["api.FileTransfer/GetFileInfo"]
+	usecaseSpan := spans["FileUsecase.GetFileInfo"]
+	repoSpan := spans["FileRepository.GetFileInfo"]
+
+	assert.Equal(t, trace.SpanKindServer, serverSpan.SpanKind)
+	assert.Equal(t, clientSpan.SpanContext.TraceID(), serverSpan.SpanContext.TraceID())
+	assert.Equal(t, clientSpan.SpanContext.SpanID(), serverSpan.Parent.SpanID())
+	assert.True(t, serverSpan.Parent.IsRemote())
+	assert.Equal(t, serverSpan.SpanContext.SpanID(), usecaseSpan.Parent.SpanID())
+	assert.Equal(t, usecaseSpan.SpanContext.SpanID(), repoSpan.Parent.SpanID())
+	assert.Equal(t, clientSpan.SpanContext.TraceID(), repoSpan.SpanContext.TraceID())
+}
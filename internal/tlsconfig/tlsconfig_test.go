@@ -0,0 +1,180 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPKI holds the paths of a CA, a server and a client certificate written to a temporary directory.
+type testPKI struct {
+	caFile, serverCertFile, serverKeyFile, clientCertFile, clientKeyFile string
+}
+
+func newTestPKI(t *testing.T) testPKI {
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	pki := testPKI{caFile: filepath.Join(dir, "ca.pem")}
+	writePEM(t, pki.caFile, "CERTIFICATE", caDER)
+
+	issue := func(name string, serial int64, usage x509.ExtKeyUsage) (string, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name, Organization: []string{"filetransfer"}},
+			DNSNames:     []string{name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+
+		certFile, keyFile := filepath.Join(dir, name+".pem"), filepath.Join(dir, name+"-key.pem")
+		writePEM(t, certFile, "CERTIFICATE", der)
+		writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+		return certFile, keyFile
+	}
+
+	pki.serverCertFile, pki.serverKeyFile = issue("server.test", 2, x509.ExtKeyUsageServerAuth)
+	pki.clientCertFile, pki.clientKeyFile = issue("client.test", 3, x509.ExtKeyUsageClientAuth)
+	return pki
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	require.NoError(t, err)
+}
+
+// handshake performs a TLS handshake between the configurations and returns the client certificate
+// subject seen by the server together with the client error.
+func handshake(t *testing.T, serverConfig, clientConfig *tls.Config) (string, error) {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	subject := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			subject <- ""
+			return
+		}
+		defer conn.Close()
+		tlsConn := conn.(*tls.Conn)
+		if err := tlsConn.Handshake(); err != nil || len(tlsConn.ConnectionState().PeerCertificates) == 0 {
+			subject <- ""
+			return
+		}
+		subject <- tlsConn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	if err == nil {
+		// TLS 1.3 reports client certificate rejections on the first read
+		_, err = conn.Read(make([]byte, 1))
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+		conn.Close()
+	}
+	return <-subject, err
+}
+
+func TestNewServerConfig_TLS(t *testing.T) {
+	pki := newTestPKI(t)
+
+	serverConfig, err := NewServerConfig(pki.serverCertFile, pki.serverKeyFile, "")
+	require.NoError(t, err)
+	assert.Equal(t, tls.NoClientCert, serverConfig.ClientAuth)
+
+	clientConfig, err := NewClientConfig(pki.caFile, "", "", "server.test")
+	require.NoError(t, err)
+
+	subject, err := handshake(t, serverConfig, clientConfig)
+	assert.NoError(t, err)
+	assert.Empty(t, subject)
+}
+
+func TestNewServerConfig_MutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+
+	serverConfig, err := NewServerConfig(pki.serverCertFile, pki.serverKeyFile, pki.caFile)
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, serverConfig.ClientAuth)
+
+	clientConfig, err := NewClientConfig(pki.caFile, pki.clientCertFile, pki.clientKeyFile, "server.test")
+	require.NoError(t, err)
+
+	subject, err := handshake(t, serverConfig, clientConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "client.test", subject)
+
+	// Clients without a certificate are rejected
+	clientConfig, err = NewClientConfig(pki.caFile, "", "", "server.test")
+	require.NoError(t, err)
+
+	subject, err = handshake(t, serverConfig, clientConfig)
+	assert.Error(t, err)
+	assert.Empty(t, subject)
+}
+
+func TestNewClientConfig_ServerNameMismatch(t *testing.T) {
+	pki := newTestPKI(t)
+
+	serverConfig, err := NewServerConfig(pki.serverCertFile, pki.serverKeyFile, "")
+	require.NoError(t, err)
+
+	clientConfig, err := NewClientConfig(pki.caFile, "", "", "other.test")
+	require.NoError(t, err)
+
+	_, err = handshake(t, serverConfig, clientConfig)
+	assert.Error(t, err)
+}
+
+func TestNewClientConfig_Errors(t *testing.T) {
+	pki := newTestPKI(t)
+
+	_, err := NewClientConfig(pki.caFile, pki.clientCertFile, "", "")
+	assert.Error(t, err)
+
+	_, err = NewClientConfig(filepath.Join(t.TempDir(), "missing.pem"), "", "", "")
+	assert.Error(t, err)
+
+	_, err = NewServerConfig(pki.serverCertFile, pki.serverKeyFile, pki.serverKeyFile)
+	assert.Error(t, err)
+}
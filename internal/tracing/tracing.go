@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// Exporters supported by NewTracerProvider.
+const (
+	// ExporterNone disables tracing.
+	ExporterNone = "none"
+	// ExporterStdout writes finished spans as JSON.
+	ExporterStdout = "stdout"
+	// ExporterOTLP sends finished spans to an OpenTelemetry collector over gRPC.
+	ExporterOTLP = "otlp"
+)
+
+// Option configures optional behaviour of the exporter of a tracer provider.
+type Option func(*options)
+
+// options holds the optional settings of the exporters.
+type options struct {
+	otlpEndpoint string
+	writer       io.Writer
+}
+
+// WithOTLPEndpoint makes the OTLP exporter send spans to the collector at endpoint, a host and port
+// optionally prefixed by a scheme. The exporter connects with TLS unless the scheme is http://.
+// Without it the standard OTEL_EXPORTER_OTLP_* variables apply, the default collector is localhost:4317.
+func WithOTLPEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.otlpEndpoint = endpoint
+	}
+}
+
+// WithWriter makes the stdout exporter write spans to w instead of stdout.
+func WithWriter(w io.Writer) Option {
+	return func(o *options) {
+		o.writer = w
+	}
+}
+
+// NewTracerProvider creates a tracer provider for the service exporting finished spans with the exporter,
+// one of ExporterNone, ExporterStdout or ExporterOTLP. Spans are not sampled with ExporterNone.
+// The tracer provider must be shut down to flush the remaining spans.
+func NewTracerProvider(ctx context.Context, serviceName, exporter string, opts ...Option) (*sdktrace.TracerProvider, error) {
+	o := &options{writer: os.Stdout}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	res := resource.NewSchemaless(semconv.ServiceName(serviceName))
+
+	var spanExporter sdktrace.SpanExporter
+	switch exporter {
+	case ExporterNone:
+		return sdktrace.NewTracerProvider(sdktrace.WithResource(res), sdktrace.WithSampler(sdktrace.NeverSample())), nil
+	case ExporterStdout:
+		stdoutExporter, err := stdouttrace.New(stdouttrace.WithWriter(o.writer))
+		if err != nil {
+			return nil, err
+		}
+		spanExporter = stdoutExporter
+	case ExporterOTLP:
+		var clientOptions []otlptracegrpc.Option
+		if endpoint, ok := strings.CutPrefix(o.otlpEndpoint, "http://"); ok {
+			clientOptions = append(clientOptions, otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
+		} else if o.otlpEndpoint != "" {
+			clientOptions = append(clientOptions, otlptracegrpc.WithEndpoint(strings.TrimPrefix(o.otlpEndpoint, "https://")))
+		}
+		otlpExporter, err := otlptracegrpc.New(ctx, clientOptions...)
+		if err != nil {
+			return nil, err
+		}
+		spanExporter = otlpExporter
+	default:
+		return nil, fmt.Errorf("invalid trace exporter %q, must be one of %s, %s or %s", exporter, ExporterNone, ExporterStdout, ExporterOTLP)
+	}
+
+	return sdktrace.NewTracerProvider(sdktrace.WithResource(res), sdktrace.WithBatcher(spanExporter)), nil
+}
+
+// Propagator returns the propagator carrying the W3C trace context and baggage in gRPC metadata.
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTracerProvider_Stdout(t *testing.T) {
+	var buf bytes.Buffer
+	tracerProvider, err := NewTracerProvider(context.Background(), "test-service", ExporterStdout, WithWriter(&buf))
+	require.NoError(t, err)
+
+	_, span := tracerProvider.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+	require.NoError(t, tracerProvider.Shutdown(context.Background()))
+
+	assert.Contains(t, buf.String(), `"Name":"operation"`)
+	assert.Contains(t, buf.String(), "test-service")
+}
+
+func TestNewTracerProvider_None(t *testing.T) {
+	tracerProvider, err := NewTracerProvider(context.Background(), "test-service", ExporterNone)
+	require.NoError(t, err)
+
+	_, span := tracerProvider.Tracer("test").Start(context.Background(), "operation")
+	defer span.End()
+
+	assert.False(t, span.IsRecording())
+}
+
+func TestNewTracerProvider_OTLP(t *testing.T) {
+	// The exporter connects lazily, so no collector is needed to create it
+	tracerProvider, err := NewTracerProvider(context.Background(), "test-service", ExporterOTLP, WithOTLPEndpoint("http://127.0.0.1:4317"))
+	require.NoError(t, err)
+	defer tracerProvider.Shutdown(context.Background())
+
+	assert.NotNil(t, tracerProvider.Tracer("test"))
+}
+
+func TestNewTracerProvider_Invalid(t *testing.T) {
+	_, err := NewTracerProvider(context.Background(), "test-service", "jaeger")
+
+	assert.Error(t, err)
+}
@@ -1,47 +1,269 @@
 package usecase
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"filetransfer/api"
 	"filetransfer/internal/repository"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracerName is the name of the tracer creating the spans of the file use case.
+const tracerName = "filetransfer/internal/usecase"
+
 // FileUsecase represents the use case for file-related operations.
 type FileUsecase struct {
 	repository repository.FileRepository
+	tracer     trace.Tracer
+}
+
+// Option configures optional behaviour of a FileUsecase.
+type Option func(*FileUsecase)
+
+// WithTracerProvider makes the use case create its spans with the provided tracer provider
+// instead of the global one.
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(u *FileUsecase) {
+		u.tracer = tracerProvider.Tracer(tracerName)
+	}
 }
 
 // NewFileUsecase creates a new instance of FileUsecase with the provided repository.
-func NewFileUsecase(repository repository.FileRepository) *FileUsecase {
-	return &FileUsecase{
+// Every operation is traced by a span with a child span around each repository call.
+func NewFileUsecase(repository repository.FileRepository, opts ...Option) *FileUsecase {
+	u := &FileUsecase{
 		repository: repository,
+		tracer:     otel.GetTracerProvider().Tracer(tracerName),
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// startSpan starts the span of a use case operation as a child of the span in ctx.
+func (u *FileUsecase) startSpan(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return u.tracer.Start(ctx, "FileUsecase."+operation, trace.WithAttributes(attrs...))
+}
+
+// startRepositorySpan starts a span around a call of the repository operation as a child of the span in ctx.
+func (u *FileUsecase) startRepositorySpan(ctx context.Context, operation string, attrs ...attribute.KeyValue) trace.Span {
+	_, span := u.tracer.Start(ctx, "FileRepository."+operation, trace.WithAttributes(attrs...))
+	return span
+}
+
+// endSpan records err on the span if it is not nil and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
 	}
+	span.End()
 }
 
-// GetFileList retrieves the list of files from the underlying repository.
-func (u *FileUsecase) GetFileList() ([]string, error) {
-	files, err := u.repository.GetFileList()
+// GetFileList retrieves the entries below a specific directory from the underlying repository,
+// descending up to maxDepth directory levels. A maxDepth of 0 returns the whole subtree.
+func (u *FileUsecase) GetFileList(ctx context.Context, dir string, maxDepth int) (files []*api.FileEntry, err error) {
+	ctx, span := u.startSpan(ctx, "GetFileList", attribute.String("path", dir), attribute.Int("max_depth", maxDepth))
+	defer func() { endSpan(span, err) }()
+
+	repoSpan := u.startRepositorySpan(ctx, "GetFileList", attribute.String("path", dir))
+	files, err = u.repository.GetFileList(ctx, dir, maxDepth)
+	endSpan(repoSpan, err)
 	if err != nil {
 		return nil, err
 	}
 
+	span.SetAttributes(attribute.Int("entries", len(files)))
 	return files, nil
 }
 
 // GetFileInfo retrieves information about a specific file from the underlying repository.
-func (u *FileUsecase) GetFileInfo(filename string) (interface{}, error) {
-	fileMetadata, err := u.repository.GetFileInfo(filename)
+func (u *FileUsecase) GetFileInfo(ctx context.Context, filename string) (fileMetadata interface{}, err error) {
+	ctx, span := u.startSpan(ctx, "GetFileInfo", attribute.String("filename", filename))
+	defer func() { endSpan(span, err) }()
+
+	repoSpan := u.startRepositorySpan(ctx, "GetFileInfo", attribute.String("filename", filename))
+	fileMetadata, err = u.repository.GetFileInfo(ctx, filename)
+	endSpan(repoSpan, err)
 	if err != nil {
 		return nil, err
 	}
 
+	if info, ok := fileMetadata.(*api.FileInfoResponse); ok {
+		span.SetAttributes(attribute.Int64("size", int64(info.Size)))
+	}
 	return fileMetadata, nil
 }
 
+// GetFileHash computes the hex encoded SHA-256 hash of the content of a specific file from the underlying repository.
+func (u *FileUsecase) GetFileHash(ctx context.Context, filename string) (hash string, err error) {
+	ctx, span := u.startSpan(ctx, "GetFileHash", attribute.String("filename", filename))
+	defer func() { endSpan(span, err) }()
+
+	repoSpan := u.startRepositorySpan(ctx, "GetFileReader", attribute.String("filename", filename))
+	reader, err := u.repository.GetFileReader(ctx, filename, 0, 0)
+	endSpan(repoSpan, err)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	sha := sha256.New()
+	size, err := io.Copy(sha, reader)
+	if err != nil {
+		return "", err
+	}
+
+	span.SetAttributes(attribute.Int64("size", size))
+	return hex.EncodeToString(sha.Sum(nil)), nil
+}
+
 // GetFileContent retrieves the content of a specific file from the underlying repository.
-func (u *FileUsecase) GetFileContent(filename string) ([]byte, error) {
-	content, err := u.repository.GetFileContent(filename)
+func (u *FileUsecase) GetFileContent(ctx context.Context, filename string) (content []byte, err error) {
+	ctx, span := u.startSpan(ctx, "GetFileContent", attribute.String("filename", filename))
+	defer func() { endSpan(span, err) }()
+
+	repoSpan := u.startRepositorySpan(ctx, "GetFileContent", attribute.String("filename", filename))
+	content, err = u.repository.GetFileContent(ctx, filename)
+	endSpan(repoSpan, err)
 	if err != nil {
 		return nil, err
 	}
 
+	span.SetAttributes(attribute.Int("size", len(content)))
 	return content, nil
 }
+
+// GetFileReader opens a specific file from the underlying repository for sequential reading
+// of the byte range starting at offset. A length of zero reads until the end of the file.
+func (u *FileUsecase) GetFileReader(ctx context.Context, filename string, offset, length int64) (reader io.ReadCloser, err error) {
+	attrs := []attribute.KeyValue{attribute.String("filename", filename), attribute.Int64("offset", offset), attribute.Int64("length", length)}
+	ctx, span := u.startSpan(ctx, "GetFileReader", attrs...)
+	defer func() { endSpan(span, err) }()
+
+	repoSpan := u.startRepositorySpan(ctx, "GetFileReader", attrs...)
+	reader, err = u.repository.GetFileReader(ctx, filename, offset, length)
+	endSpan(repoSpan, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// UploadFile stores the content read from the provided reader as a specific file in the underlying repository.
+// It returns the number of bytes stored. If reading fails, the partially written file is discarded.
+func (u *FileUsecase) UploadFile(ctx context.Context, filename string, r io.Reader) (written int64, err error) {
+	ctx, span := u.startSpan(ctx, "UploadFile", attribute.String("filename", filename))
+	defer func() {
+		span.SetAttributes(attribute.Int64("size", written))
+		endSpan(span, err)
+	}()
+
+	repoSpan := u.startRepositorySpan(ctx, "GetFileWriter", attribute.String("filename", filename))
+	writer, err := u.repository.GetFileWriter(ctx, filename)
+	endSpan(repoSpan, err)
+	if err != nil {
+		return 0, err
+	}
+
+	written, err = io.Copy(writer, r)
+	if err != nil {
+		writer.Abort()
+		return written, err
+	}
+
+	// Closing the writer makes the content visible, which is part of the repository work
+	repoSpan = u.startRepositorySpan(ctx, "FileWriter.Close", attribute.String("filename", filename))
+	err = writer.Close()
+	endSpan(repoSpan, err)
+	if err != nil {
+		return written, err
+	}
+
+	return written, nil
+}
+
+// DeleteFile deletes a specific file or directory from the underlying repository.
+// Directories with content are only deleted if recursive is set.
+func (u *FileUsecase) DeleteFile(ctx context.Context, filename string, recursive bool) (err error) {
+	attrs := []attribute.KeyValue{attribute.String("filename", filename), attribute.Bool("recursive", recursive)}
+	ctx, span := u.startSpan(ctx, "DeleteFile", attrs...)
+	defer func() { endSpan(span, err) }()
+
+	repoSpan := u.startRepositorySpan(ctx, "DeleteFile", attrs...)
+	err = u.repository.DeleteFile(ctx, filename, recursive)
+	endSpan(repoSpan, err)
+	return err
+}
+
+// RenameFile renames or moves a file or directory within the underlying repository.
+func (u *FileUsecase) RenameFile(ctx context.Context, source, destination string, overwrite bool) (err error) {
+	attrs := []attribute.KeyValue{attribute.String("source", source), attribute.String("destination", destination)}
+	ctx, span := u.startSpan(ctx, "RenameFile", attrs...)
+	defer func() { endSpan(span, err) }()
+
+	repoSpan := u.startRepositorySpan(ctx, "RenameFile", attrs...)
+	err = u.repository.RenameFile(ctx, source, destination, overwrite)
+	endSpan(repoSpan, err)
+	return err
+}
+
+// CopyFile copies a file within the underlying repository and returns the number of bytes copied.
+func (u *FileUsecase) CopyFile(ctx context.Context, source, destination string, overwrite bool) (written int64, err error) {
+	attrs := []attribute.KeyValue{attribute.String("source", source), attribute.String("destination", destination)}
+	ctx, span := u.startSpan(ctx, "CopyFile", attrs...)
+	defer func() { endSpan(span, err) }()
+
+	repoSpan := u.startRepositorySpan(ctx, "CopyFile", attrs...)
+	written, err = u.repository.CopyFile(ctx, source, destination, overwrite)
+	endSpan(repoSpan, err)
+	if err != nil {
+		return written, err
+	}
+
+	span.SetAttributes(attribute.Int64("size", written))
+	return written, nil
+}
+
+// MakeDirectory creates a directory in the underlying repository, including missing parents if requested.
+func (u *FileUsecase) MakeDirectory(ctx context.Context, path string, parents bool) (err error) {
+	attrs := []attribute.KeyValue{attribute.String("path", path), attribute.Bool("parents", parents)}
+	ctx, span := u.startSpan(ctx, "MakeDirectory", attrs...)
+	defer func() { endSpan(span, err) }()
+
+	repoSpan := u.startRepositorySpan(ctx, "MakeDirectory", attrs...)
+	err = u.repository.MakeDirectory(ctx, path, parents)
+	endSpan(repoSpan, err)
+	return err
+}
+
+// CheckHealth probes the underlying repository and returns an error if the storage is not available.
+func (u *FileUsecase) CheckHealth(ctx context.Context) error {
+	return u.repository.Probe(ctx)
+}
+
+// ResolveLinks returns the path relative to the storage root that name refers to once every symbolic link on it
+// is followed. Paths of repositories without symbolic links are returned unchanged.
+func (u *FileUsecase) ResolveLinks(ctx context.Context, name string) (string, error) {
+	if resolver, ok := u.repository.(repository.LinkResolver); ok {
+		return resolver.ResolveLinks(ctx, name)
+	}
+	return name, nil
+}
+
+// WriteBufferSize returns the number of bytes every writer of the underlying repository buffers in memory,
+// zero if the repository does not report it.
+func (u *FileUsecase) WriteBufferSize() int64 {
+	if sizer, ok := u.repository.(repository.WriteBufferSizer); ok {
+		return sizer.WriteBufferSize()
+	}
+	return 0
+}
@@ -1,12 +1,22 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"filetransfer/api"
 	"filetransfer/internal/repository"
 	"go.uber.org/mock/gomock"
+	"io"
+	"io/fs"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestFileUsecase_GetFileList(t *testing.T) {
@@ -16,12 +26,16 @@ func TestFileUsecase_GetFileList(t *testing.T) {
 	mockRepo := repository.NewMockFileRepository(ctrl)
 	usecase := NewFileUsecase(mockRepo)
 
-	mockRepo.EXPECT().GetFileList().Return([]string{"file1.txt", "file2.txt"}, nil)
+	entries := []*api.FileEntry{
+		{Name: "file1.txt", Path: "file1.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 10},
+		{Name: "file2.txt", Path: "file2.txt", Type: api.EntryType_ENTRY_TYPE_FILE, Size: 20},
+	}
+	mockRepo.EXPECT().GetFileList(gomock.Any(), "", 1).Return(entries, nil)
 
-	files, err := usecase.GetFileList()
+	files, err := usecase.GetFileList(context.Background(), "", 1)
 
 	assert.NoError(t, err)
-	assert.Equal(t, []string{"file1.txt", "file2.txt"}, files)
+	assert.Equal(t, entries, files)
 }
 
 func TestFileUsecase_GetFileInfo(t *testing.T) {
@@ -31,9 +45,9 @@ func TestFileUsecase_GetFileInfo(t *testing.T) {
 	mockRepo := repository.NewMockFileRepository(ctrl)
 	usecase := NewFileUsecase(mockRepo)
 
-	mockRepo.EXPECT().GetFileInfo("file1.txt").Return("file info", nil)
+	mockRepo.EXPECT().GetFileInfo(gomock.Any(), "file1.txt").Return("file info", nil)
 
-	fileInfo, err := usecase.GetFileInfo("file1.txt")
+	fileInfo, err := usecase.GetFileInfo(context.Background(), "file1.txt")
 
 	assert.NoError(t, err)
 	assert.Equal(t, "file info", fileInfo)
@@ -46,9 +60,9 @@ func TestFileUsecase_GetFileContent(t *testing.T) {
 	mockRepo := repository.NewMockFileRepository(ctrl)
 	usecase := NewFileUsecase(mockRepo)
 
-	mockRepo.EXPECT().GetFileContent("file1.txt").Return([]byte("file content"), nil)
+	mockRepo.EXPECT().GetFileContent(gomock.Any(), "file1.txt").Return([]byte("file content"), nil)
 
-	content, err := usecase.GetFileContent("file1.txt")
+	content, err := usecase.GetFileContent(context.Background(), "file1.txt")
 
 	assert.NoError(t, err)
 	assert.Equal(t, []byte("file content"), content)
@@ -61,10 +75,243 @@ func TestFileUsecase_GetFileList_Error(t *testing.T) {
 	mockRepo := repository.NewMockFileRepository(ctrl)
 	usecase := NewFileUsecase(mockRepo)
 
-	mockRepo.EXPECT().GetFileList().Return(nil, errors.New("mock error"))
+	mockRepo.EXPECT().GetFileList(gomock.Any(), "", 1).Return(nil, errors.New("mock error"))
 
-	files, err := usecase.GetFileList()
+	files, err := usecase.GetFileList(context.Background(), "", 1)
 
 	assert.Error(t, err)
 	assert.Nil(t, files)
 }
+
+func TestFileUsecase_GetFileHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	usecase := NewFileUsecase(mockRepo)
+
+	mockRepo.EXPECT().GetFileReader(gomock.Any(), "file1.txt", int64(0), int64(0)).Return(io.NopCloser(strings.NewReader("content")), nil)
+
+	hash, err := usecase.GetFileHash(context.Background(), "file1.txt")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73", hash)
+}
+
+func TestFileUsecase_GetFileHash_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	usecase := NewFileUsecase(mockRepo)
+
+	mockRepo.EXPECT().GetFileReader(gomock.Any(), "file1.txt", int64(0), int64(0)).Return(io.NopCloser(iotest.ErrReader(errors.New("mock error"))), nil)
+
+	hash, err := usecase.GetFileHash(context.Background(), "file1.txt")
+
+	assert.Error(t, err)
+	assert.Empty(t, hash)
+}
+
+func TestFileUsecase_GetFileReader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	usecase := NewFileUsecase(mockRepo)
+
+	mockRepo.EXPECT().GetFileReader(gomock.Any(), "file1.txt", int64(5), int64(0)).Return(io.NopCloser(strings.NewReader("content")), nil)
+
+	reader, err := usecase.GetFileReader(context.Background(), "file1.txt", 5, 0)
+	assert.NoError(t, err)
+
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("content"), content)
+}
+
+func TestFileUsecase_UploadFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockWriter := repository.NewMockFileWriter(ctrl)
+	usecase := NewFileUsecase(mockRepo)
+
+	mockRepo.EXPECT().GetFileWriter(gomock.Any(), "file1.txt").Return(mockWriter, nil)
+	mockWriter.EXPECT().Write([]byte("file content")).Return(12, nil)
+	mockWriter.EXPECT().Close().Return(nil)
+
+	written, err := usecase.UploadFile(context.Background(), "file1.txt", strings.NewReader("file content"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), written)
+}
+
+func TestFileUsecase_UploadFile_ReadError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	mockWriter := repository.NewMockFileWriter(ctrl)
+	usecase := NewFileUsecase(mockRepo)
+
+	mockRepo.EXPECT().GetFileWriter(gomock.Any(), "file1.txt").Return(mockWriter, nil)
+	mockWriter.EXPECT().Abort().Return(nil)
+
+	_, err := usecase.UploadFile(context.Background(), "file1.txt", iotest.ErrReader(errors.New("mock error")))
+
+	assert.Error(t, err)
+}
+
+func TestFileUsecase_DeleteFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	usecase := NewFileUsecase(mockRepo)
+
+	mockRepo.EXPECT().DeleteFile(gomock.Any(), "dir", true).Return(nil)
+	mockRepo.EXPECT().DeleteFile(gomock.Any(), "dir", false).Return(repository.ErrDirectoryNotEmpty)
+
+	assert.NoError(t, usecase.DeleteFile(context.Background(), "dir", true))
+	assert.ErrorIs(t, usecase.DeleteFile(context.Background(), "dir", false), repository.ErrDirectoryNotEmpty)
+}
+
+func TestFileUsecase_RenameFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	usecase := NewFileUsecase(mockRepo)
+
+	mockRepo.EXPECT().RenameFile(gomock.Any(), "a.txt", "b.txt", true).Return(nil)
+
+	assert.NoError(t, usecase.RenameFile(context.Background(), "a.txt", "b.txt", true))
+}
+
+func TestFileUsecase_CopyFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	usecase := NewFileUsecase(mockRepo)
+
+	mockRepo.EXPECT().CopyFile(gomock.Any(), "a.txt", "b.txt", false).Return(int64(7), nil)
+
+	written, err := usecase.CopyFile(context.Background(), "a.txt", "b.txt", false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), written)
+}
+
+func TestFileUsecase_MakeDirectory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	usecase := NewFileUsecase(mockRepo)
+
+	mockRepo.EXPECT().MakeDirectory(gomock.Any(), "a/b", true).Return(nil)
+
+	assert.NoError(t, usecase.MakeDirectory(context.Background(), "a/b", true))
+}
+
+func TestFileUsecase_PassesContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	usecase := NewFileUsecase(mockRepo)
+
+	// The repository sees the cancellation of the caller through the span context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	canceled := gomock.Cond(func(x any) bool { return x.(context.Context).Err() == context.Canceled })
+	mockRepo.EXPECT().GetFileReader(canceled, "file1.txt", int64(0), int64(0)).Return(nil, context.Canceled)
+	mockRepo.EXPECT().Probe(canceled).Return(context.Canceled)
+
+	_, err := usecase.GetFileHash(ctx, "file1.txt")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, usecase.CheckHealth(ctx), context.Canceled)
+}
+
+func TestFileUsecase_CheckHealth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	usecase := NewFileUsecase(mockRepo)
+
+	mockRepo.EXPECT().Probe(gomock.Any()).Return(nil)
+	mockRepo.EXPECT().Probe(gomock.Any()).Return(fs.ErrNotExist)
+
+	assert.NoError(t, usecase.CheckHealth(context.Background()))
+	assert.ErrorIs(t, usecase.CheckHealth(context.Background()), fs.ErrNotExist)
+}
+
+// newTestTracerProvider returns a tracer provider recording finished spans in an in-memory exporter.
+func newTestTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
+
+// spanAttributes returns the attributes of a span as a map.
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func TestFileUsecase_Tracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	tracerProvider, exporter := newTestTracerProvider()
+	usecase := NewFileUsecase(mockRepo, WithTracerProvider(tracerProvider))
+
+	mockRepo.EXPECT().GetFileInfo(gomock.Any(), "file1.txt").Return(&api.FileInfoResponse{Filename: "file1.txt", Size: 42}, nil)
+
+	ctx, parent := tracerProvider.Tracer("test").Start(context.Background(), "parent")
+	_, err := usecase.GetFileInfo(ctx, "file1.txt")
+	parent.End()
+
+	assert.NoError(t, err)
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 3)
+
+	// Spans are exported when they end, so the innermost span comes first
+	repoSpan, usecaseSpan, parentSpan := spans[0], spans[1], spans[2]
+	assert.Equal(t, "FileRepository.GetFileInfo", repoSpan.Name)
+	assert.Equal(t, "FileUsecase.GetFileInfo", usecaseSpan.Name)
+	assert.Equal(t, usecaseSpan.SpanContext.SpanID(), repoSpan.Parent.SpanID())
+	assert.Equal(t, parentSpan.SpanContext.SpanID(), usecaseSpan.Parent.SpanID())
+	assert.Equal(t, "file1.txt", spanAttributes(usecaseSpan)["filename"].AsString())
+	assert.Equal(t, int64(42), spanAttributes(usecaseSpan)["size"].AsInt64())
+	assert.Equal(t, "file1.txt", spanAttributes(repoSpan)["filename"].AsString())
+}
+
+func TestFileUsecase_Tracing_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repository.NewMockFileRepository(ctrl)
+	tracerProvider, exporter := newTestTracerProvider()
+	usecase := NewFileUsecase(mockRepo, WithTracerProvider(tracerProvider))
+
+	mockRepo.EXPECT().CopyFile(gomock.Any(), "a.txt", "b.txt", false).Return(int64(0), fs.ErrExist)
+
+	_, err := usecase.CopyFile(context.Background(), "a.txt", "b.txt", false)
+
+	assert.ErrorIs(t, err, fs.ErrExist)
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, codes.Error, span.Status.Code, span.Name)
+		assert.Equal(t, "a.txt", spanAttributes(span)["source"].AsString())
+		assert.Equal(t, "b.txt", spanAttributes(span)["destination"].AsString())
+	}
+}